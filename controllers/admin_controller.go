@@ -0,0 +1,254 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/olabanji12-ojo/CarWashApp/middleware"
+	"github.com/olabanji12-ojo/CarWashApp/services"
+	"github.com/olabanji12-ojo/CarWashApp/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// AdminController handles the platform moderation API (admins only)
+type AdminController struct {
+	AdminService *services.AdminService
+}
+
+// NewAdminController creates a new AdminController instance
+func NewAdminController(adminService *services.AdminService) *AdminController {
+	return &AdminController{AdminService: adminService}
+}
+
+type adminReasonInput struct {
+	Reason string `json:"reason"`
+}
+
+// ListUsers handles GET /api/admin/users?q=&role=&status=&page=&limit=
+func (ac *AdminController) ListUsers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	limit, _ := strconv.Atoi(query.Get("limit"))
+
+	result, err := ac.AdminService.SearchUsers(services.UserSearch{
+		Query:  query.Get("q"),
+		Role:   query.Get("role"),
+		Status: query.Get("status"),
+		Page:   page,
+		Limit:  limit,
+	})
+	if err != nil {
+		utils.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.JSON(w, http.StatusOK, result)
+}
+
+// GetUser handles GET /api/admin/users/{id}
+func (ac *AdminController) GetUser(w http.ResponseWriter, r *http.Request) {
+	user, err := ac.AdminService.GetUser(mux.Vars(r)["id"])
+	if err != nil {
+		utils.Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	utils.JSON(w, http.StatusOK, user)
+}
+
+// SetUserStatus handles PATCH /api/admin/users/{id}/status
+func (ac *AdminController) SetUserStatus(w http.ResponseWriter, r *http.Request) {
+	authCtx, err := middleware.GetAuthContextDirect(r)
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var input struct {
+		Status string `json:"status"` // active, suspended
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid JSON input")
+		return
+	}
+
+	if err := ac.AdminService.SetUserStatus(authCtx.UserID, mux.Vars(r)["id"], input.Status, input.Reason, utils.ClientIP(r)); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.JSON(w, http.StatusOK, map[string]string{"message": "User status updated to " + input.Status})
+}
+
+// ImpersonateUser handles POST /api/admin/users/{id}/impersonate.
+// The tokens are returned in the body only, so the admin's own session cookies are left alone.
+func (ac *AdminController) ImpersonateUser(w http.ResponseWriter, r *http.Request) {
+	authCtx, err := middleware.GetAuthContextDirect(r)
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var input adminReasonInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid JSON input")
+		return
+	}
+
+	pair, err := ac.AdminService.Impersonate(authCtx.UserID, mux.Vars(r)["id"], input.Reason, clientInfo(r))
+	if err != nil {
+		logrus.Warnf("Impersonation by %s refused: %v", authCtx.UserID, err)
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.JSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Impersonation session started",
+		"data":    pair,
+	})
+}
+
+// ListCarwashes handles GET /api/admin/carwashes?approval_status=
+func (ac *AdminController) ListCarwashes(w http.ResponseWriter, r *http.Request) {
+	carwashes, err := ac.AdminService.ListCarwashes(r.URL.Query().Get("approval_status"))
+	if err != nil {
+		utils.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.JSON(w, http.StatusOK, carwashes)
+}
+
+// GetCarwashVerification handles GET /api/admin/carwashes/{id}/verification
+func (ac *AdminController) GetCarwashVerification(w http.ResponseWriter, r *http.Request) {
+	verification, err := ac.AdminService.GetCarwashVerification(mux.Vars(r)["id"])
+	if err != nil {
+		utils.Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	utils.JSON(w, http.StatusOK, verification)
+}
+
+// SetCarwashApproval handles PATCH /api/admin/carwashes/{id}/approval
+func (ac *AdminController) SetCarwashApproval(w http.ResponseWriter, r *http.Request) {
+	authCtx, err := middleware.GetAuthContextDirect(r)
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var input struct {
+		Status string `json:"status"` // approved, rejected, deactivated
+		Note   string `json:"note"`   // reason shown to the owner
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid JSON input")
+		return
+	}
+
+	if err := ac.AdminService.SetCarwashApproval(authCtx.UserID, mux.Vars(r)["id"], input.Status, input.Note, utils.ClientIP(r)); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.JSON(w, http.StatusOK, map[string]string{"message": "Carwash listing " + input.Status})
+}
+
+// SetReviewVisibility handles PATCH /api/admin/reviews/{id}/visibility
+func (ac *AdminController) SetReviewVisibility(w http.ResponseWriter, r *http.Request) {
+	authCtx, err := middleware.GetAuthContextDirect(r)
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var input struct {
+		Hidden bool   `json:"hidden"`
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid JSON input")
+		return
+	}
+
+	if err := ac.AdminService.SetReviewVisibility(authCtx.UserID, mux.Vars(r)["id"], input.Hidden, input.Reason, utils.ClientIP(r)); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	message := "Review restored"
+	if input.Hidden {
+		message = "Review hidden"
+	}
+	utils.JSON(w, http.StatusOK, map[string]string{"message": message})
+}
+
+// ListAuditEvents handles GET /api/admin/audit-events?action=&actor_id=&user_id=&limit=
+func (ac *AdminController) ListAuditEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, _ := strconv.ParseInt(query.Get("limit"), 10, 64)
+
+	events, err := ac.AdminService.ListAuditEvents(query.Get("action"), query.Get("actor_id"), query.Get("user_id"), limit)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.JSON(w, http.StatusOK, events)
+}
+
+// ListOutboxJobs handles GET /api/admin/outbox?status=&limit=
+func (ac *AdminController) ListOutboxJobs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, _ := strconv.ParseInt(query.Get("limit"), 10, 64)
+
+	jobs, err := ac.AdminService.ListOutboxJobs(query.Get("status"), limit)
+	if err != nil {
+		utils.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.JSON(w, http.StatusOK, jobs)
+}
+
+// RetryOutboxJob handles POST /api/admin/outbox/{id}/retry
+func (ac *AdminController) RetryOutboxJob(w http.ResponseWriter, r *http.Request) {
+	if err := ac.AdminService.RetryOutboxJob(mux.Vars(r)["id"]); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.JSON(w, http.StatusOK, map[string]string{"message": "Job queued for delivery"})
+}
+
+// ListTemplates handles GET /api/admin/templates
+func (ac *AdminController) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	utils.JSON(w, http.StatusOK, ac.AdminService.ListTemplates())
+}
+
+// PreviewTemplate handles GET and POST /api/admin/templates/{event}/preview?locale=&format=json|html|text.
+// A POST body of {"data": {...}} overrides the sample values.
+func (ac *AdminController) PreviewTemplate(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			utils.Error(w, http.StatusBadRequest, "Invalid JSON input")
+			return
+		}
+	}
+
+	query := r.URL.Query()
+	msg, err := ac.AdminService.PreviewTemplate(mux.Vars(r)["event"], query.Get("locale"), input.Data)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	switch query.Get("format") {
+	case "html":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(msg.HTML))
+	case "text":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(msg.Subject + "\n\n" + msg.Text))
+	default:
+		utils.JSON(w, http.StatusOK, msg)
+	}
+}
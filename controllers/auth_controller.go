@@ -11,8 +11,8 @@ import (
 	"net/url"
 	"os"
 
-	"github.com/golang-jwt/jwt/v4"
 	"github.com/olabanji12-ojo/CarWashApp/config"
+	"github.com/olabanji12-ojo/CarWashApp/middleware"
 	"github.com/olabanji12-ojo/CarWashApp/models"
 	"github.com/olabanji12-ojo/CarWashApp/services"
 	"github.com/olabanji12-ojo/CarWashApp/utils"
@@ -20,8 +20,11 @@ import (
 	"golang.org/x/oauth2"
 
 	"bytes"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"math"
+	"strconv"
 	"strings"
 	"time"
 
@@ -35,11 +38,89 @@ import (
 )
 
 type AuthController struct {
-	AuthService *services.AuthService
+	AuthService      *services.AuthService
+	SessionService   *services.SessionService
+	TwoFactorService *services.TwoFactorService
 }
 
-func NewAuthController(authService *services.AuthService) *AuthController {
-	return &AuthController{AuthService: authService}
+func NewAuthController(authService *services.AuthService, sessionService *services.SessionService, twoFactorService *services.TwoFactorService) *AuthController {
+	return &AuthController{AuthService: authService, SessionService: sessionService, TwoFactorService: twoFactorService}
+}
+
+const refreshCookieName = "refresh_token"
+
+// clientInfo captures the device details stored on a new session
+func clientInfo(r *http.Request) services.ClientInfo {
+	return services.ClientInfo{UserAgent: r.UserAgent(), IPAddress: utils.ClientIP(r)}
+}
+
+// writeThrottled answers 429 with Retry-After if err is a throttling error, reporting whether it did
+func writeThrottled(w http.ResponseWriter, err error) bool {
+	var throttled *services.TooManyAttemptsError
+	if !errors.As(err, &throttled) {
+		return false
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+	utils.Error(w, http.StatusTooManyRequests, throttled.Error())
+	return true
+}
+
+// setSessionCookies stores the access token and the refresh token as HttpOnly cookies.
+// The refresh cookie is only sent to /api/auth so it never travels with normal API calls.
+func setSessionCookies(w http.ResponseWriter, pair *services.TokenPair, sameSite http.SameSite) {
+	secure := sameSite == http.SameSiteNoneMode || os.Getenv("ENVIRONMENT") == "production"
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     "jwt",
+		Value:    pair.AccessToken,
+		Path:     "/",
+		Expires:  time.Now().Add(time.Duration(pair.ExpiresIn) * time.Second),
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: sameSite,
+	})
+	http.SetCookie(w, &http.Cookie{
+		Name:     refreshCookieName,
+		Value:    pair.RefreshToken,
+		Path:     "/api/auth",
+		Expires:  pair.RefreshExpiresAt,
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: sameSite,
+	})
+}
+
+// clearSessionCookies removes both session cookies
+func clearSessionCookies(w http.ResponseWriter) {
+	secure := os.Getenv("ENVIRONMENT") == "production"
+	for name, path := range map[string]string{"jwt": "/", refreshCookieName: "/api/auth"} {
+		http.SetCookie(w, &http.Cookie{
+			Name:     name,
+			Value:    "",
+			Path:     path,
+			Expires:  time.Now().Add(-1 * time.Hour),
+			HttpOnly: true,
+			Secure:   secure,
+			SameSite: http.SameSiteStrictMode,
+		})
+	}
+}
+
+// refreshTokenFromRequest reads the refresh token from the JSON body or the refresh cookie
+func refreshTokenFromRequest(r *http.Request) string {
+	var body struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if r.ContentLength > 0 {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+	}
+	if body.RefreshToken != "" {
+		return body.RefreshToken
+	}
+	if cookie, err := r.Cookie(refreshCookieName); err == nil {
+		return cookie.Value
+	}
+	return ""
 }
 
 // REGISTER HANDLER
@@ -83,8 +164,8 @@ func (ac *AuthController) RegisterHandler(w http.ResponseWriter, r *http.Request
 	}
 	logrus.Infof("✅ [RegisterHandler] User registered successfully: %s", newUser.Email)
 
-	// Generate JWT token for the new user
-	token, err := utils.GenerateToken(newUser.ID.Hex(), newUser.Email, newUser.Role, newUser.AccountType)
+	// Start a session for the new user
+	session, err := ac.SessionService.IssueSession(newUser, clientInfo(r))
 	if err != nil {
 		logrus.Error("❌ [RegisterHandler] Error generating token: ", err)
 		utils.Error(w, http.StatusInternalServerError, "Failed to generate token")
@@ -103,7 +184,9 @@ func (ac *AuthController) RegisterHandler(w http.ResponseWriter, r *http.Request
 				"name":         newUser.Name,
 				"phone":        newUser.Phone,
 			},
-			"token": token,
+			"token":         session.AccessToken,
+			"refresh_token": session.RefreshToken,
+			"expires_in":    session.ExpiresIn,
 		},
 	}
 
@@ -151,8 +234,11 @@ func (ac *AuthController) LoginHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, user, err := ac.AuthService.LoginUser(credentials.Email, credentials.Password)
+	user, err := ac.AuthService.LoginUser(credentials.Email, credentials.Password, utils.ClientIP(r))
 	if err != nil {
+		if writeThrottled(w, err) {
+			return
+		}
 		utils.Error(w, http.StatusUnauthorized, err.Error())
 		return
 	}
@@ -163,26 +249,56 @@ func (ac *AuthController) LoginHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Set HttpOnly cookie
-	http.SetCookie(w, &http.Cookie{
-		Name:     "jwt",
-		Value:    token,
-		Path:     "/",
-		Expires:  time.Now().Add(24 * time.Hour),
-		HttpOnly: true,
-		Secure:   os.Getenv("ENVIRONMENT") == "production",
-		SameSite: http.SameSiteStrictMode,
-	})
+	// Accounts with 2FA (or whose carwash requires it) finish logging in through /api/auth/2fa
+	challenge, err := ac.TwoFactorService.Challenge(user)
+	if err != nil {
+		utils.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if challenge != nil {
+		writeTwoFactorChallenge(w, challenge)
+		return
+	}
+
+	session, err := ac.SessionService.IssueSession(user, clientInfo(r))
+	if err != nil {
+		utils.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Set HttpOnly cookies
+	setSessionCookies(w, session, http.SameSiteStrictMode)
 
 	utils.JSON(w, http.StatusOK, map[string]interface{}{
 		"message": "Login successful",
 		"data": map[string]interface{}{
-			"user":  user,
-			"token": token,
+			"user":          user,
+			"token":         session.AccessToken,
+			"refresh_token": session.RefreshToken,
+			"expires_in":    session.ExpiresIn,
 		},
 	})
 }
 
+// RefreshTokenHandler handles POST /api/auth/refresh
+// It rotates the refresh token and returns a fresh access token
+func (ac *AuthController) RefreshTokenHandler(w http.ResponseWriter, r *http.Request) {
+	session, err := ac.SessionService.Refresh(refreshTokenFromRequest(r))
+	if err != nil {
+		clearSessionCookies(w)
+		utils.Error(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	setSessionCookies(w, session, http.SameSiteStrictMode)
+
+	utils.JSON(w, http.StatusOK, map[string]interface{}{
+		"token":         session.AccessToken,
+		"refresh_token": session.RefreshToken,
+		"expires_in":    session.ExpiresIn,
+	})
+}
+
 // VERIFY EMAIL HANDLER
 func (ac *AuthController) VerifyEmailHandler(w http.ResponseWriter, r *http.Request) {
 	var input struct {
@@ -214,6 +330,11 @@ func (ac *AuthController) ResendVerificationEmailHandler(w http.ResponseWriter,
 		return
 	}
 
+	if err := ac.AuthService.ThrottleEmailRequest(services.EmailActionResendVerification, input.Email, utils.ClientIP(r)); err != nil {
+		writeThrottled(w, err)
+		return
+	}
+
 	if err := ac.AuthService.ResendVerificationEmail(input.Email); err != nil {
 		utils.Error(w, http.StatusBadRequest, err.Error())
 		return
@@ -393,37 +514,39 @@ func (ac *AuthController) GoogleCallbackHandler(w http.ResponseWriter, r *http.R
 		u, _ = userRepo.FindUserByEmail(gi.Email)
 	}
 
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		logrus.Error("missing JWT_SECRET env")
-		utils.Error(w, http.StatusInternalServerError, "server misconfigured")
+	if u == nil {
+		utils.Error(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	if u.Status == "suspended" {
+		utils.Error(w, http.StatusForbidden, "account suspended")
+		return
+	}
+
+	// Google proves the password step only; a required second factor is finished on the frontend
+	challenge, err := ac.TwoFactorService.Challenge(u)
+	if err != nil {
+		utils.Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	claims := jwt.MapClaims{
-		"user_id":      u.ID.Hex(),
-		"email":        u.Email,
-		"role":         u.Role,
-		"account_type": u.AccountType,
-		"exp":          time.Now().Add(72 * time.Hour).Unix(),
+	if challenge != nil {
+		params := url.Values{}
+		params.Set("two_factor", challenge.Purpose)
+		params.Set("pre_auth_token", challenge.PreAuthToken)
+		http.Redirect(w, r, os.Getenv("FRONTEND_URL")+"/CallbackPage?"+params.Encode(), http.StatusFound)
+		return
 	}
-	tokenJwt := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := tokenJwt.SignedString([]byte(jwtSecret))
+
+	session, err := ac.SessionService.IssueSession(u, clientInfo(r))
 	if err != nil {
-		logrus.Error("failed to sign jwt: ", err)
+		logrus.Error("failed to start session: ", err)
 		utils.Error(w, http.StatusInternalServerError, "failed to sign token")
 		return
 	}
+	signedToken := session.AccessToken
 
-	// Set HttpOnly cookie
-	http.SetCookie(w, &http.Cookie{
-		Name:     "jwt",
-		Value:    signedToken,
-		Path:     "/",
-		Expires:  time.Now().Add(72 * time.Hour),
-		HttpOnly: true,
-		Secure:   true, // Always true for SameSite=None
-		SameSite: http.SameSiteNoneMode,
-	})
+	// Set HttpOnly cookies (SameSite=None: the callback is a cross-site redirect)
+	setSessionCookies(w, session, http.SameSiteNoneMode)
 
 	// Redirect to frontend callback
 	frontendURL := os.Getenv("FRONTEND_URL")
@@ -433,7 +556,7 @@ func (ac *AuthController) GoogleCallbackHandler(w http.ResponseWriter, r *http.R
 }
 
 // ForgotPasswordHandler - Send password reset email
-func ForgotPasswordHandler(w http.ResponseWriter, r *http.Request) {
+func (ac *AuthController) ForgotPasswordHandler(w http.ResponseWriter, r *http.Request) {
 	var payload struct {
 		Email string `json:"email"`
 	}
@@ -449,6 +572,12 @@ func ForgotPasswordHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Limit per address and IP so this can't be used to flood an inbox
+	if err := ac.AuthService.ThrottleEmailRequest(services.EmailActionForgotPassword, payload.Email, utils.ClientIP(r)); err != nil {
+		writeThrottled(w, err)
+		return
+	}
+
 	userRepo := repositories.NewUserRepository(database.DB)
 	user, err := userRepo.FindUserByEmail(payload.Email)
 
@@ -483,7 +612,7 @@ func ForgotPasswordHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Send reset email
-	err = utils.SendPasswordResetEmail(user.Email, user.Name, resetToken)
+	err = utils.SendPasswordResetEmail(user.Email, user.Name, resetToken, user.PreferredLanguage)
 	if err != nil {
 		logrus.Error("Failed to send reset email: ", err)
 		// Don't fail the request if email fails, token is still saved
@@ -495,8 +624,9 @@ func ForgotPasswordHandler(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// ResetPasswordHandler - Reset password with token
-func ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
+// ResetPasswordHandler - Reset password with token. Every existing session is revoked, so
+// whoever had the account before the reset is logged out.
+func (ac *AuthController) ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
 	var payload struct {
 		Token       string `json:"token"`
 		NewPassword string `json:"new_password"`
@@ -550,21 +680,52 @@ func ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if _, err := ac.SessionService.LogoutAll(user.ID.Hex(), models.SessionRevokedPasswordReset); err != nil {
+		logrus.Error("Failed to revoke sessions after password reset: ", err)
+	}
+
 	logrus.Infof("Password successfully reset for user: %s", user.Email)
 	utils.JSON(w, http.StatusOK, map[string]string{
 		"message": "Password reset successful",
 	})
 }
 
+// LogoutHandler revokes the current session (by refresh token, or by the access token's session)
 func (ac *AuthController) LogoutHandler(w http.ResponseWriter, r *http.Request) {
-	http.SetCookie(w, &http.Cookie{
-		Name:     "jwt",
-		Value:    "",
-		Path:     "/",
-		Expires:  time.Now().Add(-1 * time.Hour),
-		HttpOnly: true,
-		Secure:   os.Getenv("ENVIRONMENT") == "production",
-		SameSite: http.SameSiteStrictMode,
-	})
+	if refreshToken := refreshTokenFromRequest(r); refreshToken != "" {
+		if err := ac.SessionService.Logout(refreshToken); err != nil {
+			logrus.Warn("Logout with unknown refresh token: ", err)
+		}
+	} else if authHeader := r.Header.Get("Authorization"); strings.HasPrefix(authHeader, "Bearer ") {
+		if sid, err := ac.SessionService.SessionIDFromAccessToken(strings.TrimPrefix(authHeader, "Bearer ")); err == nil {
+			if err := ac.SessionService.RevokeSessionByID(sid, models.SessionRevokedLogout); err != nil {
+				logrus.Warn("Failed to revoke session on logout: ", err)
+			}
+		}
+	}
+
+	clearSessionCookies(w)
 	utils.JSON(w, http.StatusOK, map[string]string{"message": "Logged out successfully"})
 }
+
+// LogoutAllHandler handles POST /api/auth/logout-all
+// It revokes every session of the caller, logging out all devices
+func (ac *AuthController) LogoutAllHandler(w http.ResponseWriter, r *http.Request) {
+	authCtx, err := middleware.GetAuthContextDirect(r)
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	revoked, err := ac.SessionService.LogoutAll(authCtx.UserID, models.SessionRevokedLogoutAll)
+	if err != nil {
+		utils.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	clearSessionCookies(w)
+	utils.JSON(w, http.StatusOK, map[string]interface{}{
+		"message":          "Logged out of all devices",
+		"sessions_revoked": revoked,
+	})
+}
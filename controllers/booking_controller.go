@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"strings"
@@ -9,11 +10,14 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/olabanji12-ojo/CarWashApp/middleware"
 	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/policy"
 	"github.com/olabanji12-ojo/CarWashApp/services"
+	"github.com/olabanji12-ojo/CarWashApp/services/tracking"
 	"github.com/olabanji12-ojo/CarWashApp/utils"
 	"github.com/sirupsen/logrus"
 
 	"fmt"
+	"os"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -152,8 +156,21 @@ func (bc *BookingController) UpdateBookingStatusHandler(w http.ResponseWriter, r
 		return
 	}
 
-	if err := bc.BookingService.UpdateBookingStatus(bookingID, payload.Status, payload.VerificationCode); err != nil {
-		utils.Error(w, http.StatusInternalServerError, err.Error())
+	requester, err := policy.SubjectFromRequest(r)
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := bc.BookingService.ChangeBookingStatus(requester, bookingID, payload.Status, payload.VerificationCode); err != nil {
+		switch {
+		case errors.Is(err, policy.ErrForbidden):
+			utils.Error(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, services.ErrInvalidBookingStatus), errors.Is(err, services.ErrInvalidTrackedStatus):
+			utils.Error(w, http.StatusBadRequest, err.Error())
+		default:
+			utils.Error(w, http.StatusInternalServerError, err.Error())
+		}
 		return
 	}
 
@@ -273,8 +290,7 @@ func (bc *BookingController) GetBookingsByDateHandler(w http.ResponseWriter, r *
 func (bc *BookingController) UpdateBookingHandler(w http.ResponseWriter, r *http.Request) {
 	authCtx := r.Context().Value("auth").(middleware.AuthContext)
 	userID := authCtx.UserID
-	bookingID := mux.Vars(r)["bookingID"]
-	logrus.Info("Vars:", bookingID)
+	bookingID := mux.Vars(r)["id"]
 
 	var updates map[string]interface{}
 	if err := json.NewDecoder(r.Body).Decode(&updates); err != nil {
@@ -284,11 +300,11 @@ func (bc *BookingController) UpdateBookingHandler(w http.ResponseWriter, r *http
 
 	err := bc.BookingService.UpdateBooking(userID, bookingID, updates)
 	if err != nil {
-		utils.Error(w, http.StatusInternalServerError, err.Error())
+		utils.Error(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	utils.JSON(w, http.StatusOK, map[string]string{"message": "Car updated successfully"})
+	utils.JSON(w, http.StatusOK, map[string]string{"message": "Booking updated successfully"})
 }
 
 // Get Bookings with filter from the controller
@@ -380,12 +396,114 @@ func (bc *BookingController) UpdateWorkerLocationHandler(w http.ResponseWriter,
 	utils.JSON(w, http.StatusOK, map[string]string{"message": "Location updated"})
 }
 
-// GetPublicBookingHandler handles GET /api/bookings/track/:id
-func (bc *BookingController) GetPublicBookingHandler(w http.ResponseWriter, r *http.Request) {
+// GetTravelPathHandler handles GET /api/bookings/:id/path
+func (bc *BookingController) GetTravelPathHandler(w http.ResponseWriter, r *http.Request) {
 	id := mux.Vars(r)["id"]
-	booking, err := bc.BookingService.GetBookingByID(id)
+
+	path, err := bc.BookingService.GetTravelPath(id)
+	if err != nil {
+		utils.Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, path)
+}
+
+// CreateTrackingLinkHandler handles POST /api/bookings/:id/tracking-links
+// Customers get read-only links; the assigned worker can request a write link
+func (bc *BookingController) CreateTrackingLinkHandler(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	requester, err := policy.SubjectFromRequest(r)
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var input struct {
+		Scope string `json:"scope"`
+	}
+	// Body is optional; scope defaults to read
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			utils.Error(w, http.StatusBadRequest, "Invalid input")
+			return
+		}
+	}
+
+	rawToken, token, err := bc.BookingService.IssueTrackingLink(id, requester, input.Scope)
+	if err != nil {
+		utils.Error(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	utils.JSON(w, http.StatusCreated, map[string]interface{}{
+		"token":      rawToken,
+		"scope":      token.Scope,
+		"expires_at": token.ExpiresAt,
+		"url":        fmt.Sprintf("%s/track/%s", os.Getenv("FRONTEND_URL"), rawToken),
+	})
+}
+
+// UpdateTrackedLocationHandler handles PATCH /api/bookings/track/:token/location (write link)
+func (bc *BookingController) UpdateTrackedLocationHandler(w http.ResponseWriter, r *http.Request) {
+	booking, err := bc.BookingService.ResolveTrackingLink(mux.Vars(r)["token"], models.TrackingScopeWrite)
+	if err != nil {
+		utils.Error(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	var input struct {
+		Lat float64 `json:"lat"`
+		Lng float64 `json:"lng"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid location data")
+		return
+	}
+
+	if err := bc.BookingService.UpdateWorkerLocation(booking.ID.Hex(), input.Lat, input.Lng); err != nil {
+		utils.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, map[string]string{"message": "Location updated"})
+}
+
+// UpdateTrackedStatusHandler handles PATCH /api/bookings/track/:token/status (write link).
+// The worker can only move the booking forward: in_progress, arrived, completed.
+func (bc *BookingController) UpdateTrackedStatusHandler(w http.ResponseWriter, r *http.Request) {
+	booking, err := bc.BookingService.ResolveTrackingLink(mux.Vars(r)["token"], models.TrackingScopeWrite)
+	if err != nil {
+		utils.Error(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	var payload struct {
+		Status           string `json:"status"`
+		VerificationCode string `json:"verification_code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.Status == "" {
+		utils.Error(w, http.StatusBadRequest, "Invalid status input")
+		return
+	}
+
+	if err := bc.BookingService.UpdateTrackedStatus(booking, payload.Status, payload.VerificationCode); err != nil {
+		if errors.Is(err, services.ErrInvalidTrackedStatus) {
+			utils.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, map[string]string{"message": "Booking status updated"})
+}
+
+// GetPublicBookingHandler handles GET /api/bookings/track/:token
+func (bc *BookingController) GetPublicBookingHandler(w http.ResponseWriter, r *http.Request) {
+	booking, err := bc.BookingService.ResolveTrackingLink(mux.Vars(r)["token"], models.TrackingScopeRead)
 	if err != nil {
-		utils.Error(w, http.StatusNotFound, "Booking not found")
+		utils.Error(w, http.StatusNotFound, err.Error())
 		return
 	}
 
@@ -399,5 +517,83 @@ func (bc *BookingController) GetPublicBookingHandler(w http.ResponseWriter, r *h
 		"address_note":  booking.AddressNote,
 	}
 
+	if booking.WorkerLocation != nil {
+		publicInfo["worker_location"] = booking.WorkerLocation
+	}
+
+	// ETA from the worker's last reported position (home service only)
+	if estimate := bc.BookingService.EstimateArrival(booking); estimate != nil {
+		publicInfo["eta_minutes"] = estimate.DurationMinutes
+		publicInfo["distance_km"] = estimate.DistanceKm
+	}
+
 	utils.JSON(w, http.StatusOK, publicInfo)
 }
+
+// StreamBookingTrackingHandler handles GET /api/bookings/track/:token/stream
+// It streams worker location, status and ETA updates as Server-Sent Events
+func (bc *BookingController) StreamBookingTrackingHandler(w http.ResponseWriter, r *http.Request) {
+	booking, err := bc.BookingService.ResolveTrackingLink(mux.Vars(r)["token"], models.TrackingScopeRead)
+	if err != nil {
+		utils.Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	id := booking.ID.Hex()
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		utils.Error(w, http.StatusInternalServerError, "Streaming not supported")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // Disable proxy buffering (nginx, Render)
+	w.WriteHeader(http.StatusOK)
+
+	events, unsubscribe := bc.BookingService.SubscribeTracking(booking.ID.Hex())
+	defer unsubscribe()
+
+	// Send the current state first so the page doesn't wait for the next ping
+	if err := writeTrackingEvent(w, bc.BookingService.TrackingSnapshot(booking)); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			logrus.Infof("[Tracking] Client disconnected from booking %s stream", id)
+			return
+		case <-heartbeat.C:
+			// SSE comment line keeps idle connections open through proxies
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case event, open := <-events:
+			if !open {
+				return
+			}
+			if err := writeTrackingEvent(w, event); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeTrackingEvent writes a single SSE frame named after the event type
+func writeTrackingEvent(w http.ResponseWriter, event tracking.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		logrus.Errorf("[Tracking] Failed to encode event: %v", err)
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, payload)
+	return err
+}
@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/olabanji12-ojo/CarWashApp/middleware"
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/policy"
+	"github.com/olabanji12-ojo/CarWashApp/services"
+	"github.com/olabanji12-ojo/CarWashApp/utils"
+)
+
+// BusinessController handles a business and its branches
+type BusinessController struct {
+	BusinessService *services.BusinessService
+}
+
+// NewBusinessController creates a new BusinessController instance
+func NewBusinessController(businessService *services.BusinessService) *BusinessController {
+	return &BusinessController{BusinessService: businessService}
+}
+
+// writeBranchScopeError maps branch-scoping errors to a status code
+func writeBranchScopeError(w http.ResponseWriter, err error) {
+	if errors.Is(err, policy.ErrForbidden) {
+		utils.Error(w, http.StatusForbidden, "You do not have access to any branch of this business")
+		return
+	}
+	utils.Error(w, http.StatusBadRequest, err.Error())
+}
+
+// GetMyBusiness handles GET /api/businesses/me
+func (bc *BusinessController) GetMyBusiness(w http.ResponseWriter, r *http.Request) {
+	authCtx, err := middleware.GetAuthContextDirect(r)
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	business, err := bc.BusinessService.GetMyBusiness(authCtx.UserID)
+	if err != nil {
+		utils.Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	utils.JSON(w, http.StatusOK, business)
+}
+
+// UpdateBusiness handles PUT /api/businesses/{id}
+func (bc *BusinessController) UpdateBusiness(w http.ResponseWriter, r *http.Request) {
+	var input models.Business
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid JSON input")
+		return
+	}
+
+	if err := bc.BusinessService.UpdateBusiness(mux.Vars(r)["id"], input); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.JSON(w, http.StatusOK, map[string]string{"message": "Business updated successfully"})
+}
+
+// ListBranches handles GET /api/businesses/{id}/branches
+func (bc *BusinessController) ListBranches(w http.ResponseWriter, r *http.Request) {
+	sub, err := policy.SubjectFromRequest(r)
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	branches, err := bc.BusinessService.ListBranches(sub, mux.Vars(r)["id"])
+	if err != nil {
+		writeBranchScopeError(w, err)
+		return
+	}
+	utils.JSON(w, http.StatusOK, branches)
+}
+
+// ListBookings handles GET /api/businesses/{id}/bookings?branch_id=&status=&from=&to=
+func (bc *BusinessController) ListBookings(w http.ResponseWriter, r *http.Request) {
+	sub, err := policy.SubjectFromRequest(r)
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	query := r.URL.Query()
+	bookings, err := bc.BusinessService.ListBookings(sub, mux.Vars(r)["id"], query.Get("branch_id"), query.Get("status"), query.Get("from"), query.Get("to"))
+	if err != nil {
+		writeBranchScopeError(w, err)
+		return
+	}
+	utils.JSON(w, http.StatusOK, bookings)
+}
+
+// ListWorkers handles GET /api/businesses/{id}/workers?branch_id=
+func (bc *BusinessController) ListWorkers(w http.ResponseWriter, r *http.Request) {
+	sub, err := policy.SubjectFromRequest(r)
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	workers, err := bc.BusinessService.ListWorkers(sub, mux.Vars(r)["id"], r.URL.Query().Get("branch_id"))
+	if err != nil {
+		writeBranchScopeError(w, err)
+		return
+	}
+	utils.JSON(w, http.StatusOK, workers)
+}
+
+// GetReport handles GET /api/businesses/{id}/reports?branch_id=&from=&to=
+func (bc *BusinessController) GetReport(w http.ResponseWriter, r *http.Request) {
+	sub, err := policy.SubjectFromRequest(r)
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	query := r.URL.Query()
+	report, err := bc.BusinessService.Report(sub, mux.Vars(r)["id"], query.Get("branch_id"), query.Get("from"), query.Get("to"))
+	if err != nil {
+		writeBranchScopeError(w, err)
+		return
+	}
+	utils.JSON(w, http.StatusOK, report)
+}
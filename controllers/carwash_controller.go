@@ -20,12 +20,14 @@ import (
 )
 
 type CarWashController struct {
-	CarWashService *services.CarWashService
-	UserService    *services.UserService
+	CarWashService  *services.CarWashService
+	UserService     *services.UserService
+	BusinessService *services.BusinessService
+	SMSService      *services.SMSService
 }
 
-func NewCarWashController(carwashService *services.CarWashService, userService *services.UserService) *CarWashController {
-	return &CarWashController{CarWashService: carwashService, UserService: userService}
+func NewCarWashController(carwashService *services.CarWashService, userService *services.UserService, businessService *services.BusinessService, smsService *services.SMSService) *CarWashController {
+	return &CarWashController{CarWashService: carwashService, UserService: userService, BusinessService: businessService, SMSService: smsService}
 }
 
 func (cwc *CarWashController) CreateCarwashHandler(w http.ResponseWriter, r *http.Request) {
@@ -69,7 +71,8 @@ func (cwc *CarWashController) CreateCarwashHandler(w http.ResponseWriter, r *htt
 		return
 	}
 
-	err = cwc.UserService.UpdateUserCarwashID(objID, carwash.ID)
+	// Each new carwash is another branch of the owner's business
+	err = cwc.BusinessService.AddBranch(objID, carwash)
 	if err != nil {
 		utils.Error(w, http.StatusInternalServerError, "Failed to assign carwash to user")
 		return
@@ -137,6 +140,64 @@ func (cwc *CarWashController) SetCarwashStatusHandler(w http.ResponseWriter, r *
 	utils.JSON(w, http.StatusOK, map[string]string{"message": "Status updated"})
 }
 
+// SetWorkerTwoFactorHandler handles PUT /api/carwashes/{id}/worker-2fa
+func (cwc *CarWashController) SetWorkerTwoFactorHandler(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	var payload struct {
+		Required bool `json:"required"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	if err := cwc.CarWashService.SetWorkerTwoFactorRequirement(id, payload.Required); err != nil {
+		utils.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, map[string]interface{}{"require_worker_2fa": payload.Required})
+}
+
+// GetSMSUsageHandler handles GET /api/carwashes/{id}/sms
+func (cwc *CarWashController) GetSMSUsageHandler(w http.ResponseWriter, r *http.Request) {
+	usage, err := cwc.SMSService.GetUsage(mux.Vars(r)["id"])
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, usage)
+}
+
+// SetSMSBudgetHandler handles PUT /api/carwashes/{id}/sms-budget
+func (cwc *CarWashController) SetSMSBudgetHandler(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	var payload struct {
+		MonthlyBudget *float64 `json:"monthly_budget"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.MonthlyBudget == nil {
+		utils.Error(w, http.StatusBadRequest, "monthly_budget is required")
+		return
+	}
+
+	if err := cwc.SMSService.SetMonthlyBudget(id, *payload.MonthlyBudget); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	usage, err := cwc.SMSService.GetUsage(id)
+	if err != nil {
+		utils.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.JSON(w, http.StatusOK, usage)
+}
+
 func (cwc *CarWashController) CompleteOnboarding(w http.ResponseWriter, r *http.Request) {
 	id := mux.Vars(r)["id"]
 
@@ -502,3 +563,47 @@ func (cwc *CarWashController) UploadCarwashPhotoHandler(w http.ResponseWriter, r
 		"url":     url,
 	})
 }
+
+// UploadVerificationDocumentHandler handles POST /api/carwashes/{id}/documents (multipart "document" + "type")
+func (cwc *CarWashController) UploadVerificationDocumentHandler(w http.ResponseWriter, r *http.Request) {
+	carwashID := mux.Vars(r)["id"]
+
+	if err := r.ParseMultipartForm(10 << 20); err != nil {
+		utils.Error(w, http.StatusBadRequest, "File too large or invalid multipart")
+		return
+	}
+
+	file, header, err := r.FormFile("document")
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Missing 'document' file in request")
+		return
+	}
+	defer file.Close()
+
+	docFile := &services.ProfilePhotoFile{
+		File:     file,
+		Filename: header.Filename,
+		Size:     header.Size,
+	}
+
+	doc, err := cwc.CarWashService.UploadVerificationDocument(carwashID, r.FormValue("type"), docFile)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.JSON(w, http.StatusCreated, map[string]interface{}{
+		"message":  "Document submitted for review",
+		"document": doc,
+	})
+}
+
+// GetVerificationHandler handles GET /api/carwashes/{id}/verification
+func (cwc *CarWashController) GetVerificationHandler(w http.ResponseWriter, r *http.Request) {
+	verification, err := cwc.CarWashService.GetVerification(mux.Vars(r)["id"])
+	if err != nil {
+		utils.Error(w, http.StatusNotFound, "Carwash not found")
+		return
+	}
+	utils.JSON(w, http.StatusOK, verification)
+}
@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/olabanji12-ojo/CarWashApp/middleware"
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/services"
+	"github.com/olabanji12-ojo/CarWashApp/utils"
+)
+
+// DeviceController handles push token registration
+type DeviceController struct {
+	DeviceService *services.DeviceService
+}
+
+// NewDeviceController creates a new DeviceController instance
+func NewDeviceController(deviceService *services.DeviceService) *DeviceController {
+	return &DeviceController{DeviceService: deviceService}
+}
+
+type deviceTokenInput struct {
+	Platform   string `json:"platform"` // ios, android, web
+	Token      string `json:"token"`
+	AppVersion string `json:"app_version"`
+}
+
+// RegisterDevice handles POST /api/devices
+func (dc *DeviceController) RegisterDevice(w http.ResponseWriter, r *http.Request) {
+	authCtx, err := middleware.GetAuthContextDirect(r)
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var input deviceTokenInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid JSON input")
+		return
+	}
+
+	device, err := dc.DeviceService.RegisterDevice(authCtx.UserID, models.DeviceToken{
+		Platform:   input.Platform,
+		Token:      input.Token,
+		AppVersion: input.AppVersion,
+	})
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.JSON(w, http.StatusOK, device)
+}
+
+// ListDevices handles GET /api/devices
+func (dc *DeviceController) ListDevices(w http.ResponseWriter, r *http.Request) {
+	authCtx, err := middleware.GetAuthContextDirect(r)
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	devices, err := dc.DeviceService.ListDevices(authCtx.UserID)
+	if err != nil {
+		utils.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.JSON(w, http.StatusOK, devices)
+}
+
+// UnregisterDevice handles DELETE /api/devices with {"token": "..."}.
+// Tokens can be long and contain ':', so they travel in the body rather than the path.
+func (dc *DeviceController) UnregisterDevice(w http.ResponseWriter, r *http.Request) {
+	authCtx, err := middleware.GetAuthContextDirect(r)
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var input deviceTokenInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid JSON input")
+		return
+	}
+
+	if err := dc.DeviceService.UnregisterDevice(authCtx.UserID, input.Token); err != nil {
+		utils.Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	utils.JSON(w, http.StatusOK, map[string]string{"message": "Device unregistered"})
+}
@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/olabanji12-ojo/CarWashApp/services"
+	"github.com/olabanji12-ojo/CarWashApp/utils"
+)
+
+// maxEmailEventsBody caps the size of a batch of provider reports
+const maxEmailEventsBody = 1 << 20
+
+// EmailController receives delivery reports from the email provider
+type EmailController struct {
+	EmailService *services.EmailService
+}
+
+// NewEmailController creates a new EmailController instance
+func NewEmailController(emailService *services.EmailService) *EmailController {
+	return &EmailController{EmailService: emailService}
+}
+
+// HandleEmailEvents handles POST /api/email/events, the provider's bounce and complaint webhook.
+// The provider authenticates with "Authorization: Bearer <secret>" or ?token=<secret>.
+// The body is one report, an array of reports or {"events": [...]}.
+func (ec *EmailController) HandleEmailEvents(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		token = r.URL.Query().Get("token")
+	}
+	if !ec.EmailService.VerifyWebhookToken(token) {
+		utils.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxEmailEventsBody))
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var events []services.EmailEvent
+	switch trimmed := bytes.TrimSpace(body); {
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		err = json.Unmarshal(trimmed, &events)
+	default:
+		var envelope struct {
+			Events []services.EmailEvent `json:"events"`
+			services.EmailEvent
+		}
+		err = json.Unmarshal(trimmed, &envelope)
+		events = envelope.Events
+		if events == nil {
+			events = []services.EmailEvent{envelope.EmailEvent}
+		}
+	}
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid JSON input")
+		return
+	}
+
+	handled, err := ec.EmailService.HandleEvents(events)
+	if err != nil {
+		// The provider retries on a 5xx, so nothing is lost
+		utils.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.JSON(w, http.StatusOK, map[string]int{"received": len(events), "suppressed": handled})
+}
@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/olabanji12-ojo/CarWashApp/services/tokens"
+	"github.com/sirupsen/logrus"
+)
+
+// JWKSController publishes the public keys access tokens can be verified with
+type JWKSController struct {
+	keys *tokens.KeySet
+}
+
+// NewJWKSController creates a new JWKSController instance
+func NewJWKSController(keys *tokens.KeySet) *JWKSController {
+	return &JWKSController{keys: keys}
+}
+
+// JWKSHandler handles GET /.well-known/jwks.json
+// The document is served bare (not in the API envelope) so standard JWT libraries can consume it.
+func (jc *JWKSController) JWKSHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+
+	if err := json.NewEncoder(w).Encode(jc.keys.JWKS()); err != nil {
+		logrus.Error("Error encoding JWKS: ", err)
+	}
+}
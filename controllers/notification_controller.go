@@ -2,8 +2,10 @@ package controllers
 
 import (
 	"encoding/json"
+	"html/template"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/olabanji12-ojo/CarWashApp/middleware"
@@ -12,36 +14,55 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// GetUserNotifications gets notifications for the authenticated user
-func GetUserNotifications(w http.ResponseWriter, r *http.Request) {
-	// Get user ID from auth context
+// NotificationController serves the notification inbox and preferences
+type NotificationController struct {
+	NotificationService *services.NotificationService
+}
+
+// NewNotificationController creates a new NotificationController instance
+func NewNotificationController(notificationService *services.NotificationService) *NotificationController {
+	return &NotificationController{NotificationService: notificationService}
+}
+
+type notificationIDsInput struct {
+	IDs []string `json:"ids"`
+}
+
+// GetUserNotifications handles GET /api/notifications?type=&read=true|false&archived=true&cursor=&limit=
+func (nc *NotificationController) GetUserNotifications(w http.ResponseWriter, r *http.Request) {
 	authCtx := r.Context().Value("auth").(middleware.AuthContext)
-	userID := authCtx.UserID
+	query := r.URL.Query()
 
-	// Get limit from query params (default: 20)
-	limitStr := r.URL.Query().Get("limit")
-	limit := 20
-	if limitStr != "" {
-		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
-			limit = parsedLimit
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	input := services.NotificationQuery{
+		Type:     query.Get("type"),
+		Archived: query.Get("archived") == "true",
+		Cursor:   query.Get("cursor"),
+		Limit:    limit,
+	}
+	if raw := query.Get("read"); raw != "" {
+		read, err := strconv.ParseBool(raw)
+		if err != nil {
+			utils.Error(w, http.StatusBadRequest, "read must be true or false")
+			return
 		}
+		input.Read = &read
 	}
 
-	// Get notifications
-	notifications, err := services.NotificationSvc.GetUserNotifications(userID, limit)
+	page, err := nc.NotificationService.ListNotifications(authCtx.UserID, input)
 	if err != nil {
-		utils.Error(w, http.StatusInternalServerError, err.Error())
+		utils.Error(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	utils.JSON(w, http.StatusOK, notifications)
+	utils.JSON(w, http.StatusOK, page)
 }
 
 // MarkNotificationAsRead marks a specific notification as read
-func MarkNotificationAsRead(w http.ResponseWriter, r *http.Request) {
+func (nc *NotificationController) MarkNotificationAsRead(w http.ResponseWriter, r *http.Request) {
 	notificationID := mux.Vars(r)["id"]
 
-	err := services.NotificationSvc.MarkAsRead(notificationID)
+	err := nc.NotificationService.MarkAsRead(notificationID)
 	if err != nil {
 		utils.Error(w, http.StatusInternalServerError, err.Error())
 		return
@@ -53,12 +74,10 @@ func MarkNotificationAsRead(w http.ResponseWriter, r *http.Request) {
 }
 
 // GetUnreadNotificationCount gets count of unread notifications
-func GetUnreadNotificationCount(w http.ResponseWriter, r *http.Request) {
-	// Get user ID from auth context
+func (nc *NotificationController) GetUnreadNotificationCount(w http.ResponseWriter, r *http.Request) {
 	authCtx := r.Context().Value("auth").(middleware.AuthContext)
-	userID := authCtx.UserID
 
-	count, err := services.NotificationSvc.GetUnreadCount(userID)
+	count, err := nc.NotificationService.GetUnreadCount(authCtx.UserID)
 	if err != nil {
 		utils.Error(w, http.StatusInternalServerError, err.Error())
 		return
@@ -68,12 +87,10 @@ func GetUnreadNotificationCount(w http.ResponseWriter, r *http.Request) {
 }
 
 // MarkAllNotificationsAsRead marks all notifications as read for user
-func MarkAllNotificationsAsRead(w http.ResponseWriter, r *http.Request) {
-	// Get user ID from auth context
+func (nc *NotificationController) MarkAllNotificationsAsRead(w http.ResponseWriter, r *http.Request) {
 	authCtx := r.Context().Value("auth").(middleware.AuthContext)
-	userID := authCtx.UserID
 
-	err := services.NotificationSvc.MarkAllAsRead(userID)
+	err := nc.NotificationService.MarkAllAsRead(authCtx.UserID)
 	if err != nil {
 		utils.Error(w, http.StatusInternalServerError, err.Error())
 		return
@@ -84,8 +101,60 @@ func MarkAllNotificationsAsRead(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// ArchiveNotifications handles POST /api/notifications/archive with {"ids": [...]}
+func (nc *NotificationController) ArchiveNotifications(w http.ResponseWriter, r *http.Request) {
+	authCtx := r.Context().Value("auth").(middleware.AuthContext)
+
+	var input notificationIDsInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid JSON input")
+		return
+	}
+
+	archived, err := nc.NotificationService.ArchiveNotifications(authCtx.UserID, input.IDs)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, map[string]int64{"archived": archived})
+}
+
+// DeleteNotifications handles POST /api/notifications/delete with {"ids": [...]}
+func (nc *NotificationController) DeleteNotifications(w http.ResponseWriter, r *http.Request) {
+	authCtx := r.Context().Value("auth").(middleware.AuthContext)
+
+	var input notificationIDsInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid JSON input")
+		return
+	}
+
+	deleted, err := nc.NotificationService.DeleteNotifications(authCtx.UserID, input.IDs)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, map[string]int64{"deleted": deleted})
+}
+
+// DeleteNotification handles DELETE /api/notifications/{id}
+func (nc *NotificationController) DeleteNotification(w http.ResponseWriter, r *http.Request) {
+	authCtx := r.Context().Value("auth").(middleware.AuthContext)
+
+	if _, err := nc.NotificationService.DeleteNotifications(authCtx.UserID, []string{mux.Vars(r)["id"]}); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, map[string]string{
+		"message": "Notification deleted",
+	})
+}
+
 // TestNotification sends a test notification (development only)
-func TestNotification(w http.ResponseWriter, r *http.Request) {
+func (nc *NotificationController) TestNotification(w http.ResponseWriter, r *http.Request) {
 	// Get user ID from auth context
 	authCtx := r.Context().Value("auth").(middleware.AuthContext)
 	userID := authCtx.UserID
@@ -108,12 +177,11 @@ func TestNotification(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Send test notification
-	err = services.NotificationSvc.CreateNotification(
+	err = nc.NotificationService.CreateNotification(
 		userObjID,
 		payload.Title,
 		payload.Message,
 		"general",
-		true, // Send email
 	)
 
 	if err != nil {
@@ -125,3 +193,71 @@ func TestNotification(w http.ResponseWriter, r *http.Request) {
 		"message": "Test notification sent successfully",
 	})
 }
+
+// GetNotificationPreferences returns the authenticated user's notification preferences
+func (nc *NotificationController) GetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	authCtx := r.Context().Value("auth").(middleware.AuthContext)
+
+	prefs, err := nc.NotificationService.GetPreferences(authCtx.UserID)
+	if err != nil {
+		utils.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, prefs)
+}
+
+// UpdateNotificationPreferences changes channels per notification type and the quiet hours
+func (nc *NotificationController) UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	authCtx := r.Context().Value("auth").(middleware.AuthContext)
+
+	var input services.NotificationPreferencesInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid JSON input")
+		return
+	}
+
+	prefs, err := nc.NotificationService.UpdatePreferences(authCtx.UserID, input)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, prefs)
+}
+
+// unsubscribePage asks for confirmation before unsubscribing, and confirms once done
+var unsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>Unsubscribe</title></head>
+<body style="font-family: sans-serif; max-width: 32rem; margin: 4rem auto; text-align: center">
+{{if .Done}}<p>You have been unsubscribed from these emails.</p>
+{{else}}<p>Stop receiving these emails?</p>
+<form method="post" action="{{.Action}}"><button type="submit">Unsubscribe</button></form>
+{{end}}</body></html>`))
+
+// ConfirmUnsubscribe handles GET on the unsubscribe link in notification emails. It only shows a
+// confirmation button: mail scanners and link previews fetch the link without the user asking.
+func (nc *NotificationController) ConfirmUnsubscribe(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	unsubscribePage.Execute(w, map[string]interface{}{"Action": r.URL.RequestURI()})
+}
+
+// UnsubscribeFromEmails handles POST on the unsubscribe link, from the confirmation page or a mail
+// client's one-click button (List-Unsubscribe-Post). The token in the link identifies the user.
+func (nc *NotificationController) UnsubscribeFromEmails(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if err := nc.NotificationService.Unsubscribe(query.Get("token"), query.Get("type")); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// The confirmation page's form is submitted by a browser
+	if strings.Contains(r.Header.Get("Accept"), "text/html") {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		unsubscribePage.Execute(w, map[string]interface{}{"Done": true})
+		return
+	}
+	utils.JSON(w, http.StatusOK, map[string]string{
+		"message": "You have been unsubscribed from these emails",
+	})
+}
@@ -23,14 +23,6 @@ func NewOrderController(orderService *services.OrderService) *OrderController {
 //  Create Order from Booking (business only)
 func(oc *OrderController) CreateOrderHandler(w http.ResponseWriter, r *http.Request) {
 	
-	ctx := r.Context().Value("auth").(middleware.AuthContext)
-	// authCtx := r.Context().Value("auth").(middleware.AuthContext)
-	
-	if ctx.Role != "business" {
-		utils.Error(w, http.StatusForbidden, "Only businesses can create orders")
-		return
-	}
-
 	bookingID := mux.Vars(r)["booking_id"]
 	
 	order, err := oc.OrderService.CreateOrderFromBooking(bookingID)
@@ -75,11 +67,6 @@ func(oc *OrderController) GetUserOrdersHandler(w http.ResponseWriter, r *http.Re
 func(oc *OrderController) GetCarwashOrdersHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context().Value("auth").(middleware.AuthContext)
 
-	if ctx.Role != "business" {
-		utils.Error(w, http.StatusForbidden, "Only businesses can view their orders")
-		return
-	}
-
 	orders, err := oc.OrderService.GetOrdersByCarwash(ctx.UserID)
 	if err != nil {
 		utils.Error(w, http.StatusInternalServerError, err.Error())
@@ -91,13 +78,6 @@ func(oc *OrderController) GetCarwashOrdersHandler(w http.ResponseWriter, r *http
 
 //  Update Order Status (business)
 func(oc *OrderController) UpdateOrderStatusHandler(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context().Value("auth").(middleware.AuthContext)
-
-	if ctx.Role != "business" {
-		utils.Error(w, http.StatusForbidden, "Only businesses can update order status")
-		return
-	}
-
 	orderID := mux.Vars(r)["order_id"]
 
 	var input struct {
@@ -119,13 +99,6 @@ func(oc *OrderController) UpdateOrderStatusHandler(w http.ResponseWriter, r *htt
 
 //  Assign Worker to Order (optional)
 func(oc *OrderController) AssignWorkerHandler(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context().Value("auth").(middleware.AuthContext)
-
-	if ctx.Role != "business" {
-		utils.Error(w, http.StatusForbidden, "Only businesses can assign workers")
-		return
-	}
-
 	orderID := mux.Vars(r)["order_id"]
 
 	var input struct {
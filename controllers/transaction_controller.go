@@ -14,8 +14,17 @@ import (
 
 )
 
-//  POST /api/payments → Create a new payment
-func CreatePaymentHandler(w http.ResponseWriter, r *http.Request) {
+// PaymentController handles payment endpoints
+type PaymentController struct {
+	PaymentService *services.PaymentService
+}
+
+func NewPaymentController(paymentService *services.PaymentService) *PaymentController {
+	return &PaymentController{PaymentService: paymentService}
+}
+
+//  POST /api/payments/order/{id} → Pay for an order (amount and carwash are taken from the order)
+func (pc *PaymentController) CreatePaymentHandler(w http.ResponseWriter, r *http.Request) {
 	authCtx := r.Context().Value("auth").(middleware.AuthContext)
 	userID  := authCtx.UserID
 
@@ -25,14 +34,9 @@ func CreatePaymentHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := input.Validate(); err != nil {
-	utils.Error(w, http.StatusBadRequest, err.Error())
-	return
-   }
-
-	created, err := services.CreatePayment(userID, input)
+	created, err := pc.PaymentService.CreatePayment(userID, mux.Vars(r)["id"], input)
 	if err != nil {
-		utils.Error(w, http.StatusInternalServerError, err.Error())
+		utils.Error(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -40,10 +44,10 @@ func CreatePaymentHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 //  GET /api/payments/{id} → Get payment by ID
-func GetPaymentByIDHandler(w http.ResponseWriter, r *http.Request) {
+func (pc *PaymentController) GetPaymentByIDHandler(w http.ResponseWriter, r *http.Request) {
 	paymentID := mux.Vars(r)["id"]
 
-	payment, err := services.GetPaymentByOrderID(paymentID)
+	payment, err := pc.PaymentService.GetPaymentByOrderID(paymentID)
 	if err != nil {
 		utils.Error(w, http.StatusNotFound, err.Error())
 		return
@@ -54,12 +58,12 @@ func GetPaymentByIDHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // GET /api/payments/user → Get all user payments
-func GetPaymentsByUserHandler(w http.ResponseWriter, r *http.Request) {
+func (pc *PaymentController) GetPaymentsByUserHandler(w http.ResponseWriter, r *http.Request) {
 
 	authCtx := r.Context().Value("auth").(middleware.AuthContext)
 	userID  := authCtx.UserID
 
-	payments, err := services.GetPaymentsByUserID(userID)
+	payments, err := pc.PaymentService.GetPaymentsByUserID(userID)
 	if err != nil {
 		utils.Error(w, http.StatusInternalServerError, err.Error())
 		return
@@ -69,10 +73,10 @@ func GetPaymentsByUserHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 //  GET /api/payments/carwash/{id} → All for a carwash
-func GetPaymentsByCarwashHandler(w http.ResponseWriter, r *http.Request) {
+func (pc *PaymentController) GetPaymentsByCarwashHandler(w http.ResponseWriter, r *http.Request) {
 	carwashID := mux.Vars(r)["id"]
 
-	payments, err := services.GetPaymentsByCarwashID(carwashID)
+	payments, err := pc.PaymentService.GetPaymentsByCarwashID(carwashID)
 	if err != nil {
 		utils.Error(w, http.StatusInternalServerError, err.Error())
 		return
@@ -0,0 +1,227 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/olabanji12-ojo/CarWashApp/middleware"
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/services"
+	"github.com/olabanji12-ojo/CarWashApp/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// TwoFactorController handles TOTP enrolment and the second login step
+type TwoFactorController struct {
+	TwoFactorService *services.TwoFactorService
+	SessionService   *services.SessionService
+}
+
+// NewTwoFactorController creates a new TwoFactorController instance
+func NewTwoFactorController(twoFactorService *services.TwoFactorService, sessionService *services.SessionService) *TwoFactorController {
+	return &TwoFactorController{TwoFactorService: twoFactorService, SessionService: sessionService}
+}
+
+type twoFactorCodeInput struct {
+	PreAuthToken string `json:"pre_auth_token"`
+	Code         string `json:"code"` // TOTP code or recovery code
+}
+
+// writeTwoFactorChallenge answers a login that still needs a second factor
+func writeTwoFactorChallenge(w http.ResponseWriter, challenge *services.TwoFactorChallenge) {
+	message := "Two-factor authentication required"
+	if challenge.Purpose == models.PreAuthEnroll {
+		message = "Your carwash requires two-factor authentication. Please set it up to continue."
+	}
+
+	utils.JSON(w, http.StatusOK, map[string]interface{}{
+		"message": message,
+		"data": map[string]interface{}{
+			"two_factor_required": true,
+			"pre_auth_token":      challenge.PreAuthToken,
+			"purpose":             challenge.Purpose,
+			"expires_in":          challenge.ExpiresIn,
+		},
+	})
+}
+
+// SetupHandler handles POST /api/auth/2fa/setup
+// It returns a new secret and provisioning URI for the logged-in user
+func (tc *TwoFactorController) SetupHandler(w http.ResponseWriter, r *http.Request) {
+	authCtx, err := middleware.GetAuthContextDirect(r)
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	setup, err := tc.TwoFactorService.BeginEnrollment(authCtx.UserID)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, setup)
+}
+
+// ConfirmHandler handles POST /api/auth/2fa/confirm
+// It enables 2FA and returns the recovery codes (shown only once)
+func (tc *TwoFactorController) ConfirmHandler(w http.ResponseWriter, r *http.Request) {
+	authCtx, err := middleware.GetAuthContextDirect(r)
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var input twoFactorCodeInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	recoveryCodes, err := tc.TwoFactorService.ConfirmEnrollment(authCtx.UserID, input.Code)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, map[string]interface{}{
+		"message":        "Two-factor authentication enabled",
+		"recovery_codes": recoveryCodes,
+	})
+}
+
+// DisableHandler handles POST /api/auth/2fa/disable
+func (tc *TwoFactorController) DisableHandler(w http.ResponseWriter, r *http.Request) {
+	authCtx, err := middleware.GetAuthContextDirect(r)
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var input twoFactorCodeInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	if err := tc.TwoFactorService.Disable(authCtx.UserID, input.Code); err != nil {
+		if writeThrottled(w, err) {
+			return
+		}
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, map[string]string{"message": "Two-factor authentication disabled"})
+}
+
+// RegenerateRecoveryCodesHandler handles POST /api/auth/2fa/recovery-codes
+// It replaces all recovery codes; the old ones stop working
+func (tc *TwoFactorController) RegenerateRecoveryCodesHandler(w http.ResponseWriter, r *http.Request) {
+	authCtx, err := middleware.GetAuthContextDirect(r)
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var input twoFactorCodeInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	recoveryCodes, err := tc.TwoFactorService.RegenerateRecoveryCodes(authCtx.UserID, input.Code)
+	if err != nil {
+		if writeThrottled(w, err) {
+			return
+		}
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, map[string]interface{}{"recovery_codes": recoveryCodes})
+}
+
+// VerifyLoginHandler handles POST /api/auth/2fa/verify
+// It completes a login with the pre-auth token and a TOTP or recovery code
+func (tc *TwoFactorController) VerifyLoginHandler(w http.ResponseWriter, r *http.Request) {
+	var input twoFactorCodeInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	user, err := tc.TwoFactorService.VerifyChallenge(input.PreAuthToken, input.Code)
+	if err != nil {
+		if writeThrottled(w, err) {
+			return
+		}
+		utils.Error(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	tc.startSession(w, r, user, nil)
+}
+
+// EnrollSetupHandler handles POST /api/auth/2fa/enroll/setup
+// Used during login when the user's carwash requires 2FA they haven't set up yet
+func (tc *TwoFactorController) EnrollSetupHandler(w http.ResponseWriter, r *http.Request) {
+	var input twoFactorCodeInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	setup, err := tc.TwoFactorService.BeginChallengeEnrollment(input.PreAuthToken)
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, setup)
+}
+
+// EnrollConfirmHandler handles POST /api/auth/2fa/enroll/confirm
+// It enables 2FA, logs the user in and returns their recovery codes
+func (tc *TwoFactorController) EnrollConfirmHandler(w http.ResponseWriter, r *http.Request) {
+	var input twoFactorCodeInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	user, recoveryCodes, err := tc.TwoFactorService.ConfirmChallengeEnrollment(input.PreAuthToken, input.Code)
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	tc.startSession(w, r, user, recoveryCodes)
+}
+
+func (tc *TwoFactorController) startSession(w http.ResponseWriter, r *http.Request, user *models.User, recoveryCodes []string) {
+	session, err := tc.SessionService.IssueSession(user, clientInfo(r))
+	if err != nil {
+		logrus.Error("Failed to start session after 2FA: ", err)
+		utils.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	setSessionCookies(w, session, http.SameSiteStrictMode)
+
+	user.Password = ""
+	data := map[string]interface{}{
+		"user":          user,
+		"token":         session.AccessToken,
+		"refresh_token": session.RefreshToken,
+		"expires_in":    session.ExpiresIn,
+	}
+	if recoveryCodes != nil {
+		data["recovery_codes"] = recoveryCodes
+	}
+
+	utils.JSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Login successful",
+		"data":    data,
+	})
+}
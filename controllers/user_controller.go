@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/gorilla/mux"
+	"github.com/olabanji12-ojo/CarWashApp/middleware"
 	"github.com/olabanji12-ojo/CarWashApp/models"
 	"github.com/olabanji12-ojo/CarWashApp/services"
 	"github.com/olabanji12-ojo/CarWashApp/utils"
@@ -83,9 +84,10 @@ func (uc *UserController) UpdateUserProfile(w http.ResponseWriter, r *http.Reque
 
 		// Extract form fields
 		input := &models.User{
-			Name:  r.FormValue("name"),
-			Phone: r.FormValue("phone"),
-			Email: r.FormValue("email"),
+			Name:              r.FormValue("name"),
+			Phone:             r.FormValue("phone"),
+			Email:             r.FormValue("email"),
+			PreferredLanguage: r.FormValue("preferred_language"),
 		}
 
 		// Validate input
@@ -236,12 +238,13 @@ func (uc *UserController) GetPublicUser(w http.ResponseWriter, r *http.Request)
 func (uc *UserController) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
 	logrus.Info("👉 /api/user/me endpoint hit")
 
-	userID, err := utils.GetUserIDFromRequest(r)
+	authCtx, err := middleware.GetAuthContextDirect(r)
 	if err != nil {
 		logrus.Warn("❌ Failed to extract user ID from request: ", err)
 		utils.Error(w, http.StatusUnauthorized, err.Error())
 		return
 	}
+	userID := authCtx.UserID
 
 	logrus.Infof("✅ Extracted userID from token: %s", userID)
 
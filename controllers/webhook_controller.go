@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/olabanji12-ojo/CarWashApp/services"
+	"github.com/olabanji12-ojo/CarWashApp/utils"
+)
+
+// WebhookController lets carwash owners manage their webhook endpoints and inspect deliveries
+type WebhookController struct {
+	WebhookService *services.WebhookService
+}
+
+// NewWebhookController creates a new WebhookController instance
+func NewWebhookController(webhookService *services.WebhookService) *WebhookController {
+	return &WebhookController{WebhookService: webhookService}
+}
+
+// CreateWebhook handles POST /api/carwashes/{id}/webhooks.
+// The signing secret is only returned here and when it is rotated.
+func (wc *WebhookController) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var input services.WebhookEndpointInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid JSON input")
+		return
+	}
+
+	endpoint, secret, err := wc.WebhookService.CreateEndpoint(mux.Vars(r)["id"], input)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.JSON(w, http.StatusCreated, map[string]interface{}{
+		"webhook": endpoint,
+		"secret":  secret,
+	})
+}
+
+// ListWebhooks handles GET /api/carwashes/{id}/webhooks
+func (wc *WebhookController) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	endpoints, err := wc.WebhookService.ListEndpoints(mux.Vars(r)["id"])
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.JSON(w, http.StatusOK, endpoints)
+}
+
+// GetWebhook handles GET /api/carwashes/{id}/webhooks/{webhookID}
+func (wc *WebhookController) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	endpoint, err := wc.WebhookService.GetEndpoint(vars["id"], vars["webhookID"])
+	if err != nil {
+		utils.Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	utils.JSON(w, http.StatusOK, endpoint)
+}
+
+// UpdateWebhook handles PATCH /api/carwashes/{id}/webhooks/{webhookID}
+// with any of {"url", "description", "events", "active"}
+func (wc *WebhookController) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	var input services.WebhookEndpointInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid JSON input")
+		return
+	}
+
+	vars := mux.Vars(r)
+	endpoint, err := wc.WebhookService.UpdateEndpoint(vars["id"], vars["webhookID"], input)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.JSON(w, http.StatusOK, endpoint)
+}
+
+// DeleteWebhook handles DELETE /api/carwashes/{id}/webhooks/{webhookID}
+func (wc *WebhookController) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	if err := wc.WebhookService.DeleteEndpoint(vars["id"], vars["webhookID"]); err != nil {
+		utils.Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	utils.JSON(w, http.StatusOK, map[string]string{"message": "Webhook deleted"})
+}
+
+// RotateWebhookSecret handles POST /api/carwashes/{id}/webhooks/{webhookID}/rotate-secret
+func (wc *WebhookController) RotateWebhookSecret(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	secret, err := wc.WebhookService.RotateSecret(vars["id"], vars["webhookID"])
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.JSON(w, http.StatusOK, map[string]string{"secret": secret})
+}
+
+// ListWebhookDeliveries handles GET /api/carwashes/{id}/webhooks/{webhookID}/deliveries?status=&limit=
+func (wc *WebhookController) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+
+	deliveries, err := wc.WebhookService.ListDeliveries(vars["id"], vars["webhookID"], query.Get("status"), limit)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.JSON(w, http.StatusOK, deliveries)
+}
+
+// GetWebhookDelivery handles GET /api/carwashes/{id}/webhooks/{webhookID}/deliveries/{deliveryID}
+func (wc *WebhookController) GetWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	delivery, err := wc.WebhookService.GetDelivery(vars["id"], vars["webhookID"], vars["deliveryID"])
+	if err != nil {
+		utils.Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	utils.JSON(w, http.StatusOK, delivery)
+}
+
+// RedeliverWebhook handles POST /api/carwashes/{id}/webhooks/{webhookID}/deliveries/{deliveryID}/redeliver
+func (wc *WebhookController) RedeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	delivery, err := wc.WebhookService.Redeliver(vars["id"], vars["webhookID"], vars["deliveryID"])
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	utils.JSON(w, http.StatusAccepted, delivery)
+}
@@ -2,25 +2,31 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/gorilla/mux"
 	"github.com/olabanji12-ojo/CarWashApp/middleware"
 	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/policy"
 	"github.com/olabanji12-ojo/CarWashApp/services"
 	"github.com/olabanji12-ojo/CarWashApp/utils"
 	"github.com/sirupsen/logrus"
 )
 
 type WorkerController struct {
-	WorkerService *services.WorkerService
-	UserService   *services.UserService
+	WorkerService    *services.WorkerService
+	UserService      *services.UserService
+	SessionService   *services.SessionService
+	TwoFactorService *services.TwoFactorService
 }
 
-func NewWorkerController(workerService *services.WorkerService, userService *services.UserService) *WorkerController {
+func NewWorkerController(workerService *services.WorkerService, userService *services.UserService, sessionService *services.SessionService, twoFactorService *services.TwoFactorService) *WorkerController {
 	return &WorkerController{
-		WorkerService: workerService,
-		UserService:   userService,
+		WorkerService:    workerService,
+		UserService:      userService,
+		SessionService:   sessionService,
+		TwoFactorService: twoFactorService,
 	}
 }
 
@@ -55,16 +61,130 @@ func (wc *WorkerController) CreateWorkersForBusiness(w http.ResponseWriter, r *h
 	logrus.Infof("👷 [WorkerController] Decoded Worker Input: Name=%s, Email=%s, CarWashID=%v", workerInput.Name, workerInput.Email, workerInput.CarWashID)
 
 	// Create worker using service
-	err = wc.WorkerService.CreateWorker(*requester, workerInput)
+	invite, err := wc.WorkerService.CreateWorker(*requester, workerInput)
 	if err != nil {
 		logrus.Errorf("❌ Failed to create worker: %v", err)
 		utils.Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	logrus.Infof("✅ Worker created: %s", workerInput.Email)
-	utils.JSON(w, http.StatusCreated, map[string]string{
-		"message": "Worker created successfully",
+	logrus.Infof("✅ Worker created and invited: %s", workerInput.Email)
+	utils.JSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "Worker created successfully. An invite has been sent to set up their account",
+		"invite":  invite,
+	})
+}
+
+// getRequester loads the authenticated user making the request
+func (wc *WorkerController) getRequester(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	authCtx, err := middleware.GetAuthContextDirect(r)
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, "Invalid authentication context")
+		return nil, false
+	}
+
+	requester, err := wc.UserService.GetUserByID(authCtx.UserID)
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, "Invalid user")
+		return nil, false
+	}
+	return requester, true
+}
+
+// ListWorkerInvites handles GET /api/workers/invites
+func (wc *WorkerController) ListWorkerInvites(w http.ResponseWriter, r *http.Request) {
+	requester, ok := wc.getRequester(w, r)
+	if !ok {
+		return
+	}
+
+	invites, err := wc.WorkerService.ListPendingInvites(*requester)
+	if err != nil {
+		utils.Error(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, invites)
+}
+
+// RevokeWorkerInvite handles DELETE /api/workers/invites/{id}
+func (wc *WorkerController) RevokeWorkerInvite(w http.ResponseWriter, r *http.Request) {
+	requester, ok := wc.getRequester(w, r)
+	if !ok {
+		return
+	}
+
+	if err := wc.WorkerService.RevokeInvite(*requester, mux.Vars(r)["id"]); err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, map[string]string{"message": "Invite revoked"})
+}
+
+// ResendWorkerInvite handles POST /api/workers/invites/{id}/resend
+func (wc *WorkerController) ResendWorkerInvite(w http.ResponseWriter, r *http.Request) {
+	requester, ok := wc.getRequester(w, r)
+	if !ok {
+		return
+	}
+
+	invite, err := wc.WorkerService.ResendInvite(*requester, mux.Vars(r)["id"])
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Invite resent",
+		"invite":  invite,
+	})
+}
+
+// AcceptWorkerInvite handles POST /api/workers/invites/accept (public)
+// The worker sets their own password and is logged in straight away
+func (wc *WorkerController) AcceptWorkerInvite(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	worker, err := wc.WorkerService.AcceptInvite(input.Token, input.Password)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// The carwash may require 2FA before the worker gets a session
+	challenge, err := wc.TwoFactorService.Challenge(worker)
+	if err != nil {
+		utils.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if challenge != nil {
+		writeTwoFactorChallenge(w, challenge)
+		return
+	}
+
+	session, err := wc.SessionService.IssueSession(worker, clientInfo(r))
+	if err != nil {
+		logrus.Error("Error generating token: ", err)
+		utils.Error(w, http.StatusInternalServerError, "Failed to generate token")
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Account activated successfully",
+		"data": map[string]interface{}{
+			"user":          worker,
+			"token":         session.AccessToken,
+			"refresh_token": session.RefreshToken,
+			"expires_in":    session.ExpiresIn,
+		},
 	})
 }
 
@@ -107,6 +227,35 @@ func (wc *WorkerController) UpdateWorkerStatus(w http.ResponseWriter, r *http.Re
 	utils.JSON(w, http.StatusOK, map[string]string{"message": "Worker status updated"})
 }
 
+// UpdateStaffRole handles PATCH /api/workers/{id}/staff-role
+// It changes a worker's staff role (manager, cashier, washer)
+func (wc *WorkerController) UpdateStaffRole(w http.ResponseWriter, r *http.Request) {
+	var data struct {
+		StaffRole string `json:"staff_role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	requester, err := policy.SubjectFromRequest(r)
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := wc.WorkerService.SetStaffRole(requester, mux.Vars(r)["id"], data.StaffRole); err != nil {
+		if errors.Is(err, policy.ErrForbidden) {
+			utils.Error(w, http.StatusForbidden, err.Error())
+			return
+		}
+		utils.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, map[string]string{"message": "Staff role updated", "staff_role": data.StaffRole})
+}
+
 // GetAvailableWorkersForBusiness handles getting available workers for assignment
 func (wc *WorkerController) GetAvailableWorkersForBusiness(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
@@ -160,8 +309,18 @@ func (wc *WorkerController) AssignWorkerToOrder(w http.ResponseWriter, r *http.R
 
 	logrus.Infof("📝 [WorkerController.AssignWorkerToOrder] Assigning Worker %s to Order %s", data.WorkerID, data.OrderID)
 
-	if err := wc.WorkerService.AssignWorkerToOrder(data.WorkerID, data.OrderID); err != nil {
+	requester, err := policy.SubjectFromRequest(r)
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := wc.WorkerService.AssignWorkerToOrder(requester, data.WorkerID, data.OrderID); err != nil {
 		logrus.Errorf("❌ [WorkerController.AssignWorkerToOrder] Failed: %v", err)
+		if errors.Is(err, policy.ErrForbidden) {
+			utils.Error(w, http.StatusForbidden, err.Error())
+			return
+		}
 		utils.Error(w, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -191,7 +350,17 @@ func (wc *WorkerController) RemoveWorkerFromOrder(w http.ResponseWriter, r *http
 		return
 	}
 
-	if err := wc.WorkerService.RemoveWorkerFromOrder(data.WorkerID, data.OrderID); err != nil {
+	requester, err := policy.SubjectFromRequest(r)
+	if err != nil {
+		utils.Error(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := wc.WorkerService.RemoveWorkerFromOrder(requester, data.WorkerID, data.OrderID); err != nil {
+		if errors.Is(err, policy.ErrForbidden) {
+			utils.Error(w, http.StatusForbidden, err.Error())
+			return
+		}
 		utils.Error(w, http.StatusBadRequest, err.Error())
 		return
 	}
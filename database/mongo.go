@@ -2,9 +2,11 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -107,5 +109,267 @@ func createIndexes() error {
 		return fmt.Errorf("failed to create carwash location index: %v", err)
 	}
 
+	// Worker invites are looked up by token hash
+	inviteTokenIndex := mongo.IndexModel{
+		Keys:    bson.M{"token_hash": 1},
+		Options: options.Index().SetUnique(true),
+	}
+	inviteCarwashIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "carwash_id", Value: 1}, {Key: "status", Value: 1}},
+	}
+
+	_, err = DB.Collection("worker_invites").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		inviteTokenIndex,
+		inviteCarwashIndex,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create worker invite indexes: %v", err)
+	}
+
+	// Tracking links are looked up by token hash; expired ones are cleaned up by TTL
+	trackingTokenIndex := mongo.IndexModel{
+		Keys:    bson.M{"token_hash": 1},
+		Options: options.Index().SetUnique(true),
+	}
+	trackingTokenExpiryIndex := mongo.IndexModel{
+		Keys:    bson.M{"expires_at": 1},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	}
+
+	_, err = DB.Collection("tracking_tokens").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		trackingTokenIndex,
+		trackingTokenExpiryIndex,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create tracking token indexes: %v", err)
+	}
+
+	// Sessions are looked up by current/previous refresh token hash and listed per user
+	sessionTokenIndex := mongo.IndexModel{
+		Keys:    bson.M{"token_hash": 1},
+		Options: options.Index().SetUnique(true),
+	}
+	sessionPreviousTokenIndex := mongo.IndexModel{
+		Keys: bson.M{"previous_token_hashes": 1},
+	}
+	sessionUserIndex := mongo.IndexModel{
+		Keys: bson.M{"user_id": 1},
+	}
+	sessionExpiryIndex := mongo.IndexModel{
+		Keys:    bson.M{"expires_at": 1},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	}
+
+	_, err = DB.Collection("sessions").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		sessionTokenIndex,
+		sessionPreviousTokenIndex,
+		sessionUserIndex,
+		sessionExpiryIndex,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create session indexes: %v", err)
+	}
+
+	// Auth throttle counters disappear once their window (or lockout) ends
+	_, err = DB.Collection("auth_throttles").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.M{"expires_at": 1},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create auth throttle indexes: %v", err)
+	}
+
+	// Pre-auth tokens are looked up by hash and expire a few minutes after login
+	_, err = DB.Collection("pre_auth_tokens").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.M{"token_hash": 1}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.M{"expires_at": 1}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create pre-auth token indexes: %v", err)
+	}
+
+	// One pending phone login code per number, gone once expired
+	_, err = DB.Collection("phone_otps").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.M{"phone": 1}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.M{"expires_at": 1}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create phone OTP indexes: %v", err)
+	}
+
+	// Audit events are listed newest first, optionally by action or user
+	_, err = DB.Collection("audit_events").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "action", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "target_user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "actor_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create audit event indexes: %v", err)
+	}
+
+	// Admins review listings by approval status
+	_, err = DB.Collection("carwashes").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "approval_status", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create carwash approval index: %v", err)
+	}
+
+	// One business per owner; branches are looked up by business
+	_, err = DB.Collection("businesses").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "owner_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create business indexes: %v", err)
+	}
+	_, err = DB.Collection("carwashes").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "business_id", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create carwash business index: %v", err)
+	}
+
+	// The outbox worker claims pending notification jobs that are due
+	_, err = DB.Collection("notification_jobs").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create notification job indexes: %v", err)
+	}
+
+	// One preference document per user; unsubscribe links look it up by token
+	_, err = DB.Collection("notification_preferences").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "unsubscribe_token", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create notification preference indexes: %v", err)
+	}
+
+	// A push token belongs to one device; a user's tokens are listed when sending
+	_, err = DB.Collection("device_tokens").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "token", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create device token indexes: %v", err)
+	}
+
+	// SMS spend is summed per carwash for the current month
+	_, err = DB.Collection("sms_messages").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "carwash_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create sms message indexes: %v", err)
+	}
+
+	// Scheduled jobs are deduplicated by key, claimed when due and cancelled per subject
+	_, err = DB.Collection("scheduled_jobs").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "key", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "run_at", Value: 1}}},
+		{Keys: bson.D{{Key: "kind", Value: 1}, {Key: "subject_id", Value: 1}, {Key: "status", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create scheduled job indexes: %v", err)
+	}
+
+	// The inbox pages a user's notifications newest first
+	_, err = DB.Collection("notifications").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "archived_at", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create notification indexes: %v", err)
+	}
+	if err := createNotificationRetentionIndex(ctx); err != nil {
+		return fmt.Errorf("failed to create notification retention index: %v", err)
+	}
+
+	// Webhook endpoints are matched per carwash and event; deliveries are listed per endpoint, newest first
+	_, err = DB.Collection("webhook_endpoints").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "carwash_id", Value: 1}, {Key: "active", Value: 1}, {Key: "events", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create webhook endpoint indexes: %v", err)
+	}
+	_, err = DB.Collection("webhook_deliveries").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "endpoint_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create webhook delivery indexes: %v", err)
+	}
+
+	// One suppression per address, checked before every email
+	_, err = DB.Collection("email_suppressions").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "email", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create email suppression index: %v", err)
+	}
+
+	if err := createLocationHistoryCollection(ctx); err != nil {
+		return fmt.Errorf("failed to create location history collection: %v", err)
+	}
+
 	return nil
-}
\ No newline at end of file
+}
+
+// createLocationHistoryCollection sets up the time-series collection for worker location pings.
+// Pings expire after LOCATION_HISTORY_RETENTION_DAYS (default 90).
+func createLocationHistoryCollection(ctx context.Context) error {
+	retentionDays := 90
+	if v, err := strconv.Atoi(os.Getenv("LOCATION_HISTORY_RETENTION_DAYS")); err == nil && v > 0 {
+		retentionDays = v
+	}
+
+	opts := options.CreateCollection().
+		SetTimeSeriesOptions(options.TimeSeries().
+			SetTimeField("recorded_at").
+			SetMetaField("meta").
+			SetGranularity("seconds")).
+		SetExpireAfterSeconds(int64(retentionDays * 24 * 60 * 60))
+
+	err := DB.CreateCollection(ctx, "location_history", opts)
+	if err != nil {
+		// Collection already exists from a previous start
+		var cmdErr mongo.CommandError
+		if errors.As(err, &cmdErr) && cmdErr.Code == 48 {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+// createNotificationRetentionIndex deletes read notifications NOTIFICATION_RETENTION_DAYS
+// (default 90) after they were read. Unread ones have no read_at and are kept.
+func createNotificationRetentionIndex(ctx context.Context) error {
+	retentionDays := 90
+	if v, err := strconv.Atoi(os.Getenv("NOTIFICATION_RETENTION_DAYS")); err == nil && v > 0 {
+		retentionDays = v
+	}
+	expireAfter := int32(retentionDays * 24 * 60 * 60)
+
+	_, err := DB.Collection("notifications").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "read_at", Value: 1}},
+		Options: options.Index().SetName("read_at_ttl").SetExpireAfterSeconds(expireAfter),
+	})
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == 85 {
+		// IndexOptionsConflict: the retention period changed since the index was created
+		err = DB.RunCommand(ctx, bson.D{
+			{Key: "collMod", Value: "notifications"},
+			{Key: "index", Value: bson.D{{Key: "name", Value: "read_at_ttl"}, {Key: "expireAfterSeconds", Value: expireAfter}}},
+		}).Err()
+	}
+	if err != nil {
+		return err
+	}
+
+	// Notifications read before read_at was recorded start their retention period now
+	_, err = DB.Collection("notifications").UpdateMany(ctx,
+		bson.M{"is_read": true, "read_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"read_at": time.Now()}},
+	)
+	return err
+}
@@ -1,10 +1,15 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
+	_ "time/tzdata" // quiet hours use IANA timezones even where the OS has no zoneinfo
 
 	"github.com/gorilla/csrf"
 	"github.com/gorilla/mux"
@@ -14,6 +19,9 @@ import (
 	"github.com/olabanji12-ojo/CarWashApp/middleware"
 	"github.com/olabanji12-ojo/CarWashApp/routes"
 	"github.com/olabanji12-ojo/CarWashApp/services/geocoding/google"
+	"github.com/olabanji12-ojo/CarWashApp/services/tokens"
+	"github.com/olabanji12-ojo/CarWashApp/templates"
+	"github.com/olabanji12-ojo/CarWashApp/utils"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/negroni"
 )
@@ -46,9 +54,27 @@ func main() {
 	geocoder := google.NewGoogleMapsGeocoder(googleMapsAPIKey)
 	logrus.Println("✅ Google Maps Geocoder initialized")
 
+	// Load JWT signing/verification keys
+	keys, err := tokens.LoadKeySetFromEnv()
+	if err != nil {
+		logrus.Fatal("❌ Failed to load JWT keys: ", err)
+	}
+	issuer := tokens.NewIssuer(keys, tokens.AccessTokenTTLFromEnv())
+
+	// Parse the notification templates up front so a broken one stops startup
+	templates.Default()
+	logrus.Println("✅ Notification templates loaded")
+
+	// Client IPs (rate limits, lockouts, audit log) only come from X-Forwarded-For behind these proxies
+	if err := utils.SetTrustedProxies(strings.Split(os.Getenv("TRUSTED_PROXIES"), ",")); err != nil {
+		logrus.Fatal("❌ Invalid TRUSTED_PROXIES: ", err)
+	}
+
 	// Create a single main router
 	mainRouter := mux.NewRouter()
-	routes.InitRoutes(mainRouter, db, geocoder) // Pass geocoder to routes
+	outboxWorker, scheduler := routes.InitRoutes(mainRouter, db, geocoder, issuer) // Pass geocoder and token issuer to routes
+	outboxWorker.Start()
+	scheduler.Start()
 	config.InitCloudinary()
 
 	csrfSecret := []byte(os.Getenv("CSRF_SECRET"))
@@ -90,8 +116,29 @@ func main() {
 	})
 	n.UseHandler(finalHandler)
 
-	fmt.Println("🚀 Listening on http://localhost:" + port)
-	if err := http.ListenAndServe(":"+port, n); err != nil {
-		logrus.Fatal("Server failed to start: ", err)
+	server := &http.Server{Addr: ":" + port, Handler: n}
+	go func() {
+		fmt.Println("🚀 Listening on http://localhost:" + port)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logrus.Fatal("Server failed to start: ", err)
+		}
+	}()
+
+	// On SIGINT/SIGTERM stop taking requests, then let running jobs and queued notifications finish
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	<-stop
+	logrus.Info("🛑 Shutting down...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		logrus.Error("HTTP server shutdown: ", err)
+	}
+	if err := scheduler.Shutdown(ctx); err != nil {
+		logrus.Warn("Job scheduler did not stop in time: ", err)
+	}
+	if err := outboxWorker.Shutdown(ctx); err != nil {
+		logrus.Warn("Notification outbox did not drain in time: ", err)
 	}
 }
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/olabanji12-ojo/CarWashApp/utils"
 	"github.com/rs/cors"
 	"github.com/sirupsen/logrus"
@@ -20,6 +21,48 @@ type AuthContext struct {
 	Email       string
 	Role        string
 	AccountType string
+	SessionID   string
+	// ImpersonatorID is set when a platform admin is acting as this user
+	ImpersonatorID string
+}
+
+// SessionValidator checks that the user and session behind a valid JWT are still allowed in
+// (not suspended, not logged out). It is injected at startup to keep this package free of DB access.
+type SessionValidator interface {
+	ValidateSession(userID, sessionID string) error
+}
+
+var sessionValidator SessionValidator
+
+// SetSessionValidator registers the validator used by AuthMiddleware
+func SetSessionValidator(v SessionValidator) {
+	sessionValidator = v
+}
+
+// TokenVerifier checks an access token's signature and expiry and returns its claims.
+// It is injected at startup so key material is never package-global.
+type TokenVerifier interface {
+	ValidateToken(tokenString string) (*jwt.Token, jwt.MapClaims, error)
+}
+
+var tokenVerifier TokenVerifier
+
+// SetTokenVerifier registers the verifier used by AuthMiddleware
+func SetTokenVerifier(v TokenVerifier) {
+	tokenVerifier = v
+}
+
+// ImpersonationAuditor records requests an admin makes while impersonating a user.
+// It is injected at startup like the session validator.
+type ImpersonationAuditor interface {
+	RecordImpersonatedRequest(impersonatorID, userID, method, path, ipAddress string)
+}
+
+var impersonationAuditor ImpersonationAuditor
+
+// SetImpersonationAuditor registers the auditor used by AuthMiddleware
+func SetImpersonationAuditor(a ImpersonationAuditor) {
+	impersonationAuditor = a
 }
 
 // typed context key (prevents collisions)
@@ -85,7 +128,13 @@ func AuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		token, claims, err := utils.ValidateToken(tokenString)
+		if tokenVerifier == nil {
+			logrus.Error("AuthMiddleware used before a token verifier was configured")
+			http.Error(w, "Authentication unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		token, claims, err := tokenVerifier.ValidateToken(tokenString)
 		if err != nil || !token.Valid {
 			logrus.WithError(err).Warn("Token invalid or expired")
 			http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
@@ -96,18 +145,36 @@ func AuthMiddleware(next http.Handler) http.Handler {
 		email, ok2 := claims["email"].(string)
 		role, ok3 := claims["role"].(string)
 		accountType, ok4 := claims["account_type"].(string)
+		sessionID, ok5 := claims["sid"].(string)
 
-		if !ok1 || !ok2 || !ok3 || !ok4 || userID == "" || email == "" || role == "" || accountType == "" {
+		if !ok1 || !ok2 || !ok3 || !ok4 || !ok5 || userID == "" || email == "" || role == "" || accountType == "" || sessionID == "" {
 			logrus.Warn("Invalid or missing token claims")
 			http.Error(w, "Invalid token claims", http.StatusUnauthorized)
 			return
 		}
 
+		if sessionValidator != nil {
+			if err := sessionValidator.ValidateSession(userID, sessionID); err != nil {
+				logrus.WithError(err).Warnf("Rejected token for user %s", userID)
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+		}
+
 		authCtx := AuthContext{
 			UserID:      userID,
 			Email:       email,
 			Role:        role,
 			AccountType: accountType,
+			SessionID:   sessionID,
+		}
+
+		// Every request made under impersonation is attributed to the admin
+		if impersonatorID, _ := claims["imp"].(string); impersonatorID != "" {
+			authCtx.ImpersonatorID = impersonatorID
+			if impersonationAuditor != nil {
+				impersonationAuditor.RecordImpersonatedRequest(impersonatorID, userID, r.Method, r.URL.Path, utils.ClientIP(r))
+			}
 		}
 
 		ctx := context.WithValue(r.Context(), authKey, authCtx)
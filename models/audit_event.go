@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuditEvent is a security-relevant action kept for the admin audit view
+type AuditEvent struct {
+	ID           primitive.ObjectID     `bson:"_id,omitempty" json:"id,omitempty"`
+	Action       string                 `bson:"action" json:"action"`
+	ActorID      *primitive.ObjectID    `bson:"actor_id,omitempty" json:"actor_id,omitempty"`             // who did it (nil for anonymous/system)
+	TargetUserID *primitive.ObjectID    `bson:"target_user_id,omitempty" json:"target_user_id,omitempty"` // whose account it concerns
+	IPAddress    string                 `bson:"ip_address,omitempty" json:"ip_address,omitempty"`
+	Details      map[string]interface{} `bson:"details,omitempty" json:"details,omitempty"`
+	CreatedAt    time.Time              `bson:"created_at" json:"created_at"`
+}
+
+// Audit actions
+const (
+	AuditAccountLocked   = "account_locked"
+	AuditIPLocked        = "ip_locked"
+	AuditTwoFactorLocked = "two_factor_locked"
+
+	// Admin actions
+	AuditUserSuspended        = "user_suspended"
+	AuditUserReactivated      = "user_reactivated"
+	AuditCarwashApproval      = "carwash_approval_changed"
+	AuditReviewHidden         = "review_hidden"
+	AuditReviewUnhidden       = "review_unhidden"
+	AuditImpersonationStarted = "impersonation_started"
+	AuditImpersonatedRequest  = "impersonated_request"
+)
+
+func (e *AuditEvent) SetDefaults() {
+	e.ID = primitive.NewObjectID()
+	e.CreatedAt = time.Now()
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// AuthThrottle counts hits against one key (e.g. "login:account:<email>", "login:ip:<ip>",
+// "forgot_password:<email>") within a rolling window. It expires with the window.
+type AuthThrottle struct {
+	Key         string     `bson:"_id" json:"key"`
+	Count       int        `bson:"count" json:"count"`
+	LockedUntil *time.Time `bson:"locked_until,omitempty" json:"locked_until,omitempty"`
+	ExpiresAt   time.Time  `bson:"expires_at" json:"expires_at"`
+}
+
+// IsLocked reports whether the key is still inside a lockout
+func (t *AuthThrottle) IsLocked() bool {
+	return t.LockedUntil != nil && time.Now().Before(*t.LockedUntil)
+}
@@ -3,24 +3,29 @@ package models
 import (
 	"time"
 
+	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type (
+	// Business is the organisation an owner runs. Each of its carwashes is a branch: BranchIDs is
+	// the authoritative list, Carwash.BusinessID only points back for lookups.
 	Business struct {
-		ID           primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-		Name         string             `bson:"name" json:"name"`
-		Email        string             `bson:"email" json:"email"`
-		Phone        string             `bson:"phone" json:"phone"`
-		Address      string             `bson:"address" json:"address"`
-		Logo         string             `bson:"logo" json:"logo"`
-		OpeningHours time.Time          `bson:"opening_hours" json:"opening_hours"`
-		ClosingHours time.Time          `bson:"closing_hours" json:"closing_hours"`
-		CreatedAt    time.Time          `bson:"created_at,omitempty" json:"created_at,omitempty"`
-		State        string             `bson:"state,omitempty" json:"state,omitempty"`
-		Country      string             `bson:"country,omitempty" json:"country,omitempty"`
-		LGA          string             `bson:"lga,omitempty" json:"lga,omitempty"`
-		UpdatedAt    *time.Time         `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+		ID           primitive.ObjectID   `bson:"_id,omitempty" json:"id,omitempty"`
+		OwnerID      primitive.ObjectID   `bson:"owner_id" json:"owner_id"`
+		BranchIDs    []primitive.ObjectID `bson:"branch_ids,omitempty" json:"branch_ids,omitempty"`
+		Name         string               `bson:"name" json:"name"`
+		Email        string               `bson:"email" json:"email"`
+		Phone        string               `bson:"phone" json:"phone"`
+		Address      string               `bson:"address" json:"address"`
+		Logo         string               `bson:"logo" json:"logo"`
+		OpeningHours time.Time            `bson:"opening_hours" json:"opening_hours"`
+		ClosingHours time.Time            `bson:"closing_hours" json:"closing_hours"`
+		CreatedAt    time.Time            `bson:"created_at,omitempty" json:"created_at,omitempty"`
+		State        string               `bson:"state,omitempty" json:"state,omitempty"`
+		Country      string               `bson:"country,omitempty" json:"country,omitempty"`
+		LGA          string               `bson:"lga,omitempty" json:"lga,omitempty"`
+		UpdatedAt    *time.Time           `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
 	}
 )
 
@@ -28,3 +33,20 @@ func (b *Business) SetDefaults() {
 	b.CreatedAt = time.Now()
 	b.ID = primitive.NewObjectID()
 }
+
+// HasBranch reports whether the carwash is one of the business's branches
+func (b *Business) HasBranch(carwashID primitive.ObjectID) bool {
+	for _, id := range b.BranchIDs {
+		if id == carwashID {
+			return true
+		}
+	}
+	return false
+}
+
+func (b Business) Validate() error {
+	return validation.ValidateStruct(&b,
+		validation.Field(&b.Name, validation.Required, validation.Length(2, 100)),
+		validation.Field(&b.OwnerID, validation.Required),
+	)
+}
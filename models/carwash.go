@@ -30,7 +30,8 @@ type Service struct {
 
 type Carwash struct {
 	ID                  primitive.ObjectID       `bson:"_id,omitempty" json:"id,omitempty"`
-	OwnerID             primitive.ObjectID       `bson:"owner_id" json:"owner_id"` // Link to User who owns this
+	OwnerID             primitive.ObjectID       `bson:"owner_id" json:"owner_id"`                           // Link to User who owns this
+	BusinessID          *primitive.ObjectID      `bson:"business_id,omitempty" json:"business_id,omitempty"` // The business this branch belongs to
 	Name                string                   `bson:"name" json:"name"`
 	Description         string                   `bson:"description,omitempty" json:"description,omitempty"`
 	Address             string                   `bson:"address" json:"address"`
@@ -56,6 +57,49 @@ type Carwash struct {
 	Features            []string                 `bson:"features,omitempty" json:"features,omitempty"`
 	Addons              []map[string]interface{} `bson:"addons,omitempty" json:"addons,omitempty"`
 	BasePrice           float64                  `bson:"base_price" json:"base_price"`
+	RequireWorker2FA    bool                     `bson:"require_worker_2fa,omitempty" json:"require_worker_2fa,omitempty"`
+	SMSMonthlyBudget    *float64                 `bson:"sms_monthly_budget,omitempty" json:"-"`                      // nil = platform default, 0 = no SMS; see SMSService
+	ApprovalStatus      string                   `bson:"approval_status,omitempty" json:"approval_status,omitempty"` // pending_review, approved, rejected, deactivated (set by platform admins)
+	ApprovalNote        string                   `bson:"approval_note,omitempty" json:"approval_note,omitempty"`     // rejection or deactivation reason
+	ApprovedAt          *time.Time               `bson:"approved_at,omitempty" json:"approved_at,omitempty"`
+	// KYC documents are only shown to the owner and platform admins
+	VerificationDocuments []VerificationDocument `bson:"verification_documents,omitempty" json:"-"`
+}
+
+// VerificationDocument is a business document an owner submits for KYC review
+type VerificationDocument struct {
+	ID         primitive.ObjectID `bson:"_id" json:"id"`
+	Type       string             `bson:"type" json:"type"` // business_registration, tax_id, owner_id, other
+	FileName   string             `bson:"file_name" json:"file_name"`
+	URL        string             `bson:"url" json:"url"`
+	PublicID   string             `bson:"public_id" json:"-"`
+	UploadedAt time.Time          `bson:"uploaded_at" json:"uploaded_at"`
+}
+
+// Carwash approval statuses. Only approved listings are shown to customers;
+// listings created before moderation existed have no status and stay visible.
+const (
+	CarwashApprovalPendingReview = "pending_review"
+	CarwashApprovalApproved      = "approved"
+	CarwashApprovalRejected      = "rejected"
+	CarwashApprovalDeactivated   = "deactivated"
+)
+
+// Verification document types
+const (
+	DocumentBusinessRegistration = "business_registration"
+	DocumentTaxID                = "tax_id"
+	DocumentOwnerID              = "owner_id"
+	DocumentOther                = "other"
+)
+
+// IsValidDocumentType reports whether t is a known verification document type
+func IsValidDocumentType(t string) bool {
+	switch t {
+	case DocumentBusinessRegistration, DocumentTaxID, DocumentOwnerID, DocumentOther:
+		return true
+	}
+	return false
 }
 
 func (c *Carwash) SetDefaults() {
@@ -64,6 +108,7 @@ func (c *Carwash) SetDefaults() {
 	c.UpdatedAt = time.Now()
 	c.QueueCount = 0
 	c.IsActive = true
+	c.ApprovalStatus = CarwashApprovalPendingReview
 	c.Services = []Service{} // initialize Services as empty slice
 	if c.BasePrice == 0 {
 		c.BasePrice = 5000 // Default base price if not set
@@ -116,6 +161,11 @@ func (c Carwash) Validate() error {
 	)
 }
 
+// IsVerified reports whether a platform admin has approved the listing's documents
+func (c *Carwash) IsVerified() bool {
+	return c.ApprovalStatus == CarwashApprovalApproved
+}
+
 // GetDistanceFrom calculates the distance between the carwash and a given location
 // Returns distance in kilometers and a human-readable string
 func (c *Carwash) GetDistanceFrom(userLat, userLng float64) (distanceKm float64, distanceText string) {
@@ -167,6 +217,7 @@ func (c *Carwash) WithDistance(userLat, userLng float64) map[string]interface{}
 		"addons":             c.Addons,
 		"operating_hours":    c.OpenHours,
 		"base_price":         c.BasePrice,
+		"verified":           c.IsVerified(),
 	}
 }
 
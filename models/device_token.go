@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Device platforms
+const (
+	PlatformIOS     = "ios"
+	PlatformAndroid = "android"
+	PlatformWeb     = "web"
+)
+
+// DeviceToken is a push token a mobile or web client registered for its user.
+// A token belongs to one user at a time; registering it again moves it to whoever is logged in.
+type DeviceToken struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID     primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Platform   string             `bson:"platform" json:"platform"` // ios, android, web
+	Token      string             `bson:"token" json:"token"`
+	AppVersion string             `bson:"app_version,omitempty" json:"app_version,omitempty"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+	LastSeenAt time.Time          `bson:"last_seen_at" json:"last_seen_at"`
+}
+
+func (d DeviceToken) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.Platform, validation.Required, validation.In(PlatformIOS, PlatformAndroid, PlatformWeb)),
+		validation.Field(&d.Token, validation.Required, validation.Length(16, 4096)),
+		validation.Field(&d.AppVersion, validation.Length(0, 32)),
+	)
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Why an address stopped receiving email
+const (
+	EmailSuppressionBounce    = "bounce"    // hard bounce: the mailbox doesn't exist or refuses mail
+	EmailSuppressionComplaint = "complaint" // the recipient marked our mail as spam
+)
+
+// EmailSuppression is an address we no longer send email to, reported by the email provider.
+// Mailing addresses that bounce or complain hurts deliverability for everyone else.
+type EmailSuppression struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Email     string             `bson:"email" json:"email"`   // lower-cased
+	Reason    string             `bson:"reason" json:"reason"` // bounce, complaint
+	Detail    string             `bson:"detail,omitempty" json:"detail,omitempty"`
+	Reports   int                `bson:"reports" json:"reports"` // how often the provider reported it
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LocationPingMeta identifies the booking/worker a ping belongs to.
+// It is the time-series meta field so pings for the same booking are bucketed together.
+type LocationPingMeta struct {
+	BookingID primitive.ObjectID `bson:"booking_id" json:"booking_id"`
+	WorkerID  primitive.ObjectID `bson:"worker_id,omitempty" json:"worker_id,omitempty"`
+}
+
+// LocationPing is one reported worker position, appended to the location_history time-series
+type LocationPing struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Meta       LocationPingMeta   `bson:"meta" json:"meta"`
+	Location   GeoLocation        `bson:"location" json:"location"`
+	RecordedAt time.Time          `bson:"recorded_at" json:"recorded_at"`
+}
+
+// TravelPath is a booking's recorded route as a GeoJSON Feature with a LineString geometry
+type TravelPath struct {
+	Type       string               `json:"type"` // always "Feature"
+	Geometry   TravelPathGeometry   `json:"geometry"`
+	Properties TravelPathProperties `json:"properties"`
+}
+
+// TravelPathGeometry is a GeoJSON LineString ([lng, lat] pairs)
+type TravelPathGeometry struct {
+	Type        string      `json:"type"` // always "LineString"
+	Coordinates [][]float64 `json:"coordinates"`
+}
+
+// TravelPathProperties summarises the route for disputes and mileage claims
+type TravelPathProperties struct {
+	BookingID         string     `json:"booking_id"`
+	WorkerID          string     `json:"worker_id,omitempty"`
+	PingCount         int        `json:"ping_count"`
+	DistanceKm        float64    `json:"distance_km"`
+	StartedAt         *time.Time `json:"started_at,omitempty"`
+	EndedAt           *time.Time `json:"ended_at,omitempty"`
+	ArrivedAt         *time.Time `json:"arrived_at,omitempty"`
+	TimeOnSiteMinutes float64    `json:"time_on_site_minutes"`
+}
@@ -8,25 +8,28 @@ import (
 
 // Notification represents a user notification
 type Notification struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
-	Title     string            `bson:"title" json:"title"`
-	Message   string            `bson:"message" json:"message"`
-	Type      string            `bson:"type" json:"type"` // "booking", "order", "payment", "worker"
-	IsRead    bool              `bson:"is_read" json:"is_read"`
-	EmailSent bool              `bson:"email_sent" json:"email_sent"`
-	CreatedAt time.Time         `bson:"created_at" json:"created_at"`
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID     primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Title      string             `bson:"title" json:"title"`
+	Message    string             `bson:"message" json:"message"`
+	Type       string             `bson:"type" json:"type"` // "booking", "order", "payment", "worker", "marketing"
+	IsRead     bool               `bson:"is_read" json:"is_read"`
+	EmailSent  bool               `bson:"email_sent" json:"email_sent"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+	ReadAt     *time.Time         `bson:"read_at,omitempty" json:"read_at,omitempty"`         // read notifications expire a while after this
+	ArchivedAt *time.Time         `bson:"archived_at,omitempty" json:"archived_at,omitempty"` // hidden from the inbox
 }
 
 // NotificationTypes - constants for notification types
 
 const (
 
-	NotificationTypeBooking = "booking"
-	NotificationTypeOrder   = "order"
-	NotificationTypePayment = "payment"
-	NotificationTypeWorker  = "worker"
-	NotificationTypeGeneral = "general"
+	NotificationTypeBooking   = "booking"
+	NotificationTypeOrder     = "order"
+	NotificationTypePayment   = "payment"
+	NotificationTypeWorker    = "worker"
+	NotificationTypeGeneral   = "general"
+	NotificationTypeMarketing = "marketing"
 	
 )
 
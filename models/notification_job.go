@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NotificationJob is one pending delivery in the notification outbox.
+// It is written together with its notification and worked off by the outbox worker pool.
+type NotificationJob struct {
+	ID             primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
+	NotificationID *primitive.ObjectID `bson:"notification_id,omitempty" json:"notification_id,omitempty"`
+	UserID         *primitive.ObjectID `bson:"user_id,omitempty" json:"user_id,omitempty"`
+	CarwashID      *primitive.ObjectID `bson:"carwash_id,omitempty" json:"carwash_id,omitempty"` // whose SMS budget pays for an SMS
+	Channel        string              `bson:"channel" json:"channel"`                           // email, push, sms
+	Recipient      string              `bson:"recipient,omitempty" json:"recipient,omitempty"`   // explicit address; otherwise looked up from UserID when sending
+	Subject        string              `bson:"subject,omitempty" json:"subject,omitempty"`
+	Body           string              `bson:"body,omitempty" json:"body,omitempty"`           // HTML part
+	TextBody       string              `bson:"text_body,omitempty" json:"text_body,omitempty"` // plain-text part
+	Template       string              `bson:"template,omitempty" json:"template,omitempty"`   // version/locale/event it was rendered from
+	UnsubscribeURL string              `bson:"unsubscribe_url,omitempty" json:"-"`
+	Status         string              `bson:"status" json:"status"` // pending, processing, sent, dead
+	Attempts       int                 `bson:"attempts" json:"attempts"`
+	MaxAttempts    int                 `bson:"max_attempts" json:"max_attempts"`
+	NextAttemptAt  time.Time           `bson:"next_attempt_at" json:"next_attempt_at"`
+	LockedBy       string              `bson:"locked_by,omitempty" json:"locked_by,omitempty"`
+	LockedUntil    *time.Time          `bson:"locked_until,omitempty" json:"locked_until,omitempty"`
+	LastError      string              `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	SentAt         *time.Time          `bson:"sent_at,omitempty" json:"sent_at,omitempty"`
+	CreatedAt      time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// Outbox job statuses
+const (
+	JobStatusPending    = "pending"
+	JobStatusProcessing = "processing"
+	JobStatusSent       = "sent"
+	JobStatusDead       = "dead" // gave up after MaxAttempts; can be retried by an admin
+)
+
+// ChannelEmail is the email delivery channel; the others are listed with the notification preferences
+const ChannelEmail = "email"
+
+// DefaultJobMaxAttempts is how often a delivery is tried before it is dead-lettered
+const DefaultJobMaxAttempts = 8
+
+func (j *NotificationJob) SetDefaults() {
+	j.ID = primitive.NewObjectID()
+	j.Status = JobStatusPending
+	j.Attempts = 0
+	if j.MaxAttempts == 0 {
+		j.MaxAttempts = DefaultJobMaxAttempts
+	}
+	j.NextAttemptAt = time.Now()
+	j.CreatedAt = time.Now()
+	j.UpdatedAt = time.Now()
+}
@@ -0,0 +1,178 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Notification channels
+const (
+	ChannelInApp = "in_app"
+	ChannelSMS   = "sms"
+	ChannelPush  = "push"
+)
+
+// NotificationChannels lists every channel a preference can turn on or off
+var NotificationChannels = []string{ChannelInApp, ChannelEmail, ChannelSMS, ChannelPush}
+
+// NotificationEventTypes lists the notification types users can configure
+var NotificationEventTypes = []string{
+	NotificationTypeBooking,
+	NotificationTypeOrder,
+	NotificationTypePayment,
+	NotificationTypeWorker,
+	NotificationTypeMarketing,
+}
+
+// ChannelPreferences says which channels one event type is delivered over
+type ChannelPreferences struct {
+	InApp bool `bson:"in_app" json:"in_app"`
+	Email bool `bson:"email" json:"email"`
+	SMS   bool `bson:"sms" json:"sms"`
+	Push  bool `bson:"push" json:"push"`
+}
+
+// Allows reports whether the channel is switched on
+func (c ChannelPreferences) Allows(channel string) bool {
+	switch channel {
+	case ChannelInApp:
+		return c.InApp
+	case ChannelEmail:
+		return c.Email
+	case ChannelSMS:
+		return c.SMS
+	case ChannelPush:
+		return c.Push
+	}
+	return false
+}
+
+// QuietHours holds back email, SMS and push between Start and End (HH:MM, local to Timezone).
+// In-app notifications are still stored straight away.
+type QuietHours struct {
+	Enabled  bool   `bson:"enabled" json:"enabled"`
+	Start    string `bson:"start,omitempty" json:"start,omitempty"`       // e.g. "22:00"
+	End      string `bson:"end,omitempty" json:"end,omitempty"`           // e.g. "07:00"; may be on the next day
+	Timezone string `bson:"timezone,omitempty" json:"timezone,omitempty"` // IANA name, defaults to UTC
+}
+
+// Validate checks the clock times and timezone
+func (q QuietHours) Validate() error {
+	if !q.Enabled {
+		return nil
+	}
+	if _, err := time.Parse("15:04", q.Start); err != nil {
+		return errors.New("quiet hours start must be HH:MM")
+	}
+	if _, err := time.Parse("15:04", q.End); err != nil {
+		return errors.New("quiet hours end must be HH:MM")
+	}
+	if q.Start == q.End {
+		return errors.New("quiet hours start and end must differ")
+	}
+	if _, err := time.LoadLocation(q.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q", q.Timezone)
+	}
+	return nil
+}
+
+// Until returns when the quiet period covering now ends, or false when now is outside it
+func (q QuietHours) Until(now time.Time) (time.Time, bool) {
+	if !q.Enabled {
+		return time.Time{}, false
+	}
+	loc, err := time.LoadLocation(q.Timezone)
+	if err != nil {
+		return time.Time{}, false
+	}
+	start, err1 := time.Parse("15:04", q.Start)
+	end, err2 := time.Parse("15:04", q.End)
+	if err1 != nil || err2 != nil {
+		return time.Time{}, false
+	}
+
+	local := now.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	startMin := start.Hour()*60 + start.Minute()
+	endMin := end.Hour()*60 + end.Minute()
+
+	var inside bool
+	if startMin < endMin {
+		inside = minute >= startMin && minute < endMin
+	} else { // window wraps past midnight
+		inside = minute >= startMin || minute < endMin
+	}
+	if !inside {
+		return time.Time{}, false
+	}
+
+	until := time.Date(local.Year(), local.Month(), local.Day(), end.Hour(), end.Minute(), 0, 0, loc)
+	if !until.After(local) {
+		until = until.AddDate(0, 0, 1)
+	}
+	return until, true
+}
+
+// NotificationPreferences are one user's choices of what reaches them and how
+type NotificationPreferences struct {
+	ID               primitive.ObjectID            `bson:"_id,omitempty" json:"-"`
+	UserID           primitive.ObjectID            `bson:"user_id" json:"user_id"`
+	Events           map[string]ChannelPreferences `bson:"events" json:"events"` // by notification type
+	QuietHours       QuietHours                    `bson:"quiet_hours" json:"quiet_hours"`
+	UnsubscribeToken string                        `bson:"unsubscribe_token" json:"-"` // identifies the user in email unsubscribe links
+	UpdatedAt        time.Time                     `bson:"updated_at" json:"updated_at"`
+}
+
+// DefaultNotificationPreferences are used until a user saves their own.
+// Service messages go in-app, by email and push; worker updates also by SMS, since they matter
+// right away. Marketing is opt-in.
+func DefaultNotificationPreferences(userID primitive.ObjectID) *NotificationPreferences {
+	service := ChannelPreferences{InApp: true, Email: true, Push: true}
+	urgent := ChannelPreferences{InApp: true, Email: true, SMS: true, Push: true}
+	return &NotificationPreferences{
+		UserID: userID,
+		Events: map[string]ChannelPreferences{
+			NotificationTypeBooking:   service,
+			NotificationTypeOrder:     service,
+			NotificationTypePayment:   service,
+			NotificationTypeWorker:    urgent,
+			NotificationTypeMarketing: {},
+		},
+		QuietHours: QuietHours{Timezone: "UTC"},
+	}
+}
+
+// For returns the channel choices for a notification type.
+// Types users can't configure, such as "general", go in-app and by email.
+func (p *NotificationPreferences) For(notificationType string) ChannelPreferences {
+	if prefs, ok := p.Events[notificationType]; ok {
+		return prefs
+	}
+	if defaults, ok := DefaultNotificationPreferences(p.UserID).Events[notificationType]; ok {
+		return defaults
+	}
+	return ChannelPreferences{InApp: true, Email: true}
+}
+
+// Validate rejects unknown notification types and a malformed quiet-hours window
+func (p *NotificationPreferences) Validate() error {
+	for notificationType := range p.Events {
+		if !IsConfigurableNotificationType(notificationType) {
+			return fmt.Errorf("unknown notification type %q", notificationType)
+		}
+	}
+	return p.QuietHours.Validate()
+}
+
+// IsConfigurableNotificationType reports whether users can set preferences for the type
+func IsConfigurableNotificationType(notificationType string) bool {
+	for _, t := range NotificationEventTypes {
+		if t == notificationType {
+			return true
+		}
+	}
+	return false
+}
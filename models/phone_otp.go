@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PhoneOTP is a pending passwordless login code. There is at most one per phone number;
+// requesting a new code replaces the old one.
+type PhoneOTP struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Phone     string             `bson:"phone" json:"phone"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	CodeHash  string             `bson:"code_hash" json:"-"`
+	Attempts  int                `bson:"attempts" json:"attempts"`
+	ExpiresAt time.Time          `bson:"expires_at" json:"expires_at"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+const (
+	PhoneOTPLength      = 6
+	PhoneOTPTTL         = 5 * time.Minute
+	PhoneOTPMaxAttempts = 5
+)
+
+func (o *PhoneOTP) SetDefaults() {
+	o.ID = primitive.NewObjectID()
+	o.CreatedAt = time.Now()
+	o.ExpiresAt = time.Now().Add(PhoneOTPTTL)
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PreAuthToken is handed out after a correct password when a second factor is still needed.
+// It can only be exchanged for a session through the 2FA endpoints, never used as an access token.
+type PreAuthToken struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	TokenHash string             `bson:"token_hash" json:"-"`
+	Purpose   string             `bson:"purpose" json:"purpose"` // verify, enroll
+	Attempts  int                `bson:"attempts" json:"attempts"`
+	ExpiresAt time.Time          `bson:"expires_at" json:"expires_at"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// Pre-auth purposes
+const (
+	PreAuthVerify = "verify" // user has 2FA and must enter a code
+	PreAuthEnroll = "enroll" // user's carwash requires 2FA and they must set it up first
+)
+
+const (
+	PreAuthTokenTTL      = 5 * time.Minute
+	PreAuthMaxAttempts   = 5
+	RecoveryCodeCount    = 10
+	TwoFactorIssuerLabel = "CarWashApp"
+)
+
+func (t *PreAuthToken) SetDefaults() {
+	t.ID = primitive.NewObjectID()
+	t.CreatedAt = time.Now()
+	t.ExpiresAt = time.Now().Add(PreAuthTokenTTL)
+}
@@ -21,6 +21,9 @@ type Review struct {
 	Response     string              `bson:"response,omitempty" json:"response,omitempty"`           // Business owner's reply
 	ResponseDate time.Time           `bson:"response_date,omitempty" json:"response_date,omitempty"` // Date of reply
 	CustomerName string              `bson:"customer_name,omitempty" json:"customer_name,omitempty"` // Populated by aggregation
+	Hidden       bool                `bson:"hidden,omitempty" json:"hidden,omitempty"`               // Hidden by a platform admin
+	HiddenReason string              `bson:"hidden_reason,omitempty" json:"hidden_reason,omitempty"`
+	HiddenAt     *time.Time          `bson:"hidden_at,omitempty" json:"hidden_at,omitempty"`
 	CreatedAt    time.Time           `bson:"created_at" json:"created_at"`
 	UpdatedAt    time.Time           `bson:"updated_at" json:"updated_at"`
 }
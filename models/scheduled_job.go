@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ScheduledJob is work to be done at RunAt, such as a booking reminder.
+// Key is unique, so scheduling the same job twice (e.g. after a restart) keeps one document,
+// and instances take turns through a lease so each job runs once.
+type ScheduledJob struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Kind        string             `bson:"kind" json:"kind"`                         // booking_reminder
+	Key         string             `bson:"key" json:"key"`                           // e.g. booking_reminder:<booking>:<offset>:<run at>
+	SubjectID   primitive.ObjectID `bson:"subject_id" json:"subject_id"`             // the booking (or other document) the job is about
+	Params      map[string]string  `bson:"params,omitempty" json:"params,omitempty"` // kind-specific settings
+	RunAt       time.Time          `bson:"run_at" json:"run_at"`
+	Status      string             `bson:"status" json:"status"` // pending, processing, done, cancelled, failed
+	Attempts    int                `bson:"attempts" json:"attempts"`
+	MaxAttempts int                `bson:"max_attempts" json:"max_attempts"`
+	LockedBy    string             `bson:"locked_by,omitempty" json:"locked_by,omitempty"`
+	LockedUntil *time.Time         `bson:"locked_until,omitempty" json:"locked_until,omitempty"`
+	LastError   string             `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	CompletedAt *time.Time         `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// Scheduled job statuses
+const (
+	ScheduledJobPending    = "pending"
+	ScheduledJobProcessing = "processing"
+	ScheduledJobDone       = "done"
+	ScheduledJobCancelled  = "cancelled"
+	ScheduledJobFailed     = "failed"
+)
+
+// JobKindBookingReminder reminds a customer of an upcoming booking
+const JobKindBookingReminder = "booking_reminder"
+
+// DefaultScheduledJobMaxAttempts is how often a job is tried before it is marked failed
+const DefaultScheduledJobMaxAttempts = 5
+
+func (j *ScheduledJob) SetDefaults() {
+	j.ID = primitive.NewObjectID()
+	j.Status = ScheduledJobPending
+	j.Attempts = 0
+	if j.MaxAttempts == 0 {
+		j.MaxAttempts = DefaultScheduledJobMaxAttempts
+	}
+	j.CreatedAt = time.Now()
+	j.UpdatedAt = time.Now()
+}
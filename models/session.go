@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Session is one logged-in device. Its ID is the "sid" claim in every access token issued for it.
+// The refresh token rotates on every use; old hashes are kept so a replayed token can be detected.
+type Session struct {
+	ID                  primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID              primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	TokenHash           string              `bson:"token_hash" json:"-"`
+	PreviousTokenHashes []string            `bson:"previous_token_hashes,omitempty" json:"-"`
+	UserAgent           string              `bson:"user_agent,omitempty" json:"user_agent,omitempty"`
+	IPAddress           string              `bson:"ip_address,omitempty" json:"ip_address,omitempty"`
+	ExpiresAt           time.Time           `bson:"expires_at" json:"expires_at"`
+	LastUsedAt          time.Time           `bson:"last_used_at" json:"last_used_at"`
+	RevokedAt           *time.Time          `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	RevokedReason       string              `bson:"revoked_reason,omitempty" json:"revoked_reason,omitempty"`   // logout, logout_all, token_reuse, suspended
+	ImpersonatorID      *primitive.ObjectID `bson:"impersonator_id,omitempty" json:"impersonator_id,omitempty"` // admin acting as this user
+	CreatedAt           time.Time           `bson:"created_at" json:"created_at"`
+}
+
+// Session revocation reasons
+const (
+	SessionRevokedLogout        = "logout"
+	SessionRevokedLogoutAll     = "logout_all"
+	SessionRevokedReuse         = "token_reuse"
+	SessionRevokedSuspended     = "suspended"
+	SessionRevokedPasswordReset = "password_reset"
+)
+
+// RefreshTokenTTL is how long a session survives without being refreshed
+const RefreshTokenTTL = 30 * 24 * time.Hour
+
+// ImpersonationTTL caps how long an admin can act as another user
+const ImpersonationTTL = time.Hour
+
+func (s *Session) SetDefaults() {
+	s.ID = primitive.NewObjectID()
+	s.CreatedAt = time.Now()
+	s.LastUsedAt = time.Now()
+	s.ExpiresAt = time.Now().Add(RefreshTokenTTL)
+}
+
+// IsActive reports whether the session can still be used
+func (s *Session) IsActive() bool {
+	return s.RevokedAt == nil && time.Now().Before(s.ExpiresAt)
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Why an SMS was sent
+const (
+	SMSPurposeWorkerOnTheWay   = "worker_on_the_way"
+	SMSPurposeWorkerArrived    = "worker_arrived"
+	SMSPurposeVerificationCode = "verification_code"
+	SMSPurposeWorkerInvite     = "worker_invite"
+)
+
+// SMS delivery outcomes
+const (
+	SMSStatusSent   = "sent"
+	SMSStatusFailed = "failed"
+)
+
+// SMSMessage records one SMS for cost tracking. The body is not kept, since it may hold a login code.
+// Messages sent for a carwash's bookings count against that carwash's monthly SMS budget.
+type SMSMessage struct {
+	ID                primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
+	CarwashID         *primitive.ObjectID `bson:"carwash_id,omitempty" json:"carwash_id,omitempty"`
+	UserID            *primitive.ObjectID `bson:"user_id,omitempty" json:"user_id,omitempty"`
+	Purpose           string              `bson:"purpose" json:"purpose"`
+	To                string              `bson:"to" json:"to"`
+	Segments          int                 `bson:"segments" json:"segments"`
+	Cost              float64             `bson:"cost" json:"cost"`
+	ProviderMessageID string              `bson:"provider_message_id,omitempty" json:"provider_message_id,omitempty"`
+	Status            string              `bson:"status" json:"status"` // sent, failed
+	Error             string              `bson:"error,omitempty" json:"error,omitempty"`
+	CreatedAt         time.Time           `bson:"created_at" json:"created_at"`
+}
+
+// SMSUsage is a carwash's SMS spend for the current calendar month (UTC)
+type SMSUsage struct {
+	CarwashID   primitive.ObjectID `json:"carwash_id"`
+	PeriodStart time.Time          `json:"period_start"`
+	Budget      float64            `json:"budget"`
+	Spent       float64            `json:"spent"`
+	Remaining   float64            `json:"remaining"`
+	Messages    int64              `json:"messages"`
+	Recent      []SMSMessage       `json:"recent"`
+}
@@ -0,0 +1,48 @@
+package models
+
+// Staff roles a carwash owner can give the people working for them.
+// All staff accounts have User.Role "worker"; StaffRole decides what they may do.
+const (
+	StaffRoleManager = "manager"
+	StaffRoleCashier = "cashier"
+	StaffRoleWasher  = "washer"
+)
+
+// Permission is a single capability within a carwash
+type Permission string
+
+// Carwash permissions. The owner holds all of them.
+const (
+	PermManageBookings Permission = "manage_bookings" // view/update/cancel bookings and orders, assign workers
+	PermViewFinances   Permission = "view_finances"   // payments and revenue
+	PermManageWorkers  Permission = "manage_workers"  // invite staff, change their roles and status
+	PermManageServices Permission = "manage_services" // service menu and prices
+	PermReplyReviews   Permission = "reply_reviews"
+)
+
+// StaffRolePermissions lists what each staff role may do
+var StaffRolePermissions = map[string][]Permission{
+	StaffRoleManager: {PermManageBookings, PermViewFinances, PermManageWorkers, PermManageServices, PermReplyReviews},
+	StaffRoleCashier: {PermManageBookings, PermViewFinances},
+	StaffRoleWasher:  {},
+}
+
+// IsValidStaffRole reports whether role is a known staff role
+func IsValidStaffRole(role string) bool {
+	_, ok := StaffRolePermissions[role]
+	return ok
+}
+
+// StaffRoleHasPermission reports whether a staff role grants perm.
+// Workers created before staff roles existed have no StaffRole and count as washers.
+func StaffRoleHasPermission(role string, perm Permission) bool {
+	if role == "" {
+		role = StaffRoleWasher
+	}
+	for _, granted := range StaffRolePermissions[role] {
+		if granted == perm {
+			return true
+		}
+	}
+	return false
+}
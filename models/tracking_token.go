@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TrackingToken backs a shareable booking tracking link.
+// Like worker invites, only the hash of the token is stored.
+type TrackingToken struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	BookingID primitive.ObjectID `bson:"booking_id" json:"booking_id"`
+	IssuedTo  primitive.ObjectID `bson:"issued_to" json:"issued_to"`
+	TokenHash string             `bson:"token_hash" json:"-"`
+	Scope     string             `bson:"scope" json:"scope"` // read, write
+	ExpiresAt time.Time          `bson:"expires_at" json:"expires_at"`
+	RevokedAt *time.Time         `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// Tracking token scopes. A write token can also read.
+const (
+	TrackingScopeRead  = "read"
+	TrackingScopeWrite = "write"
+)
+
+// TrackingTokenTTL is how long a tracking link stays valid
+const TrackingTokenTTL = 24 * time.Hour
+
+func (t *TrackingToken) SetDefaults() {
+	t.ID = primitive.NewObjectID()
+	t.CreatedAt = time.Now()
+	t.ExpiresAt = time.Now().Add(TrackingTokenTTL)
+}
+
+// Allows reports whether the token grants the required scope
+func (t *TrackingToken) Allows(scope string) bool {
+	return t.Scope == scope || t.Scope == TrackingScopeWrite
+}
@@ -49,7 +49,8 @@ func (a UserAddress) Validate() error {
 type User struct {
 	ID                  primitive.ObjectID   `bson:"_id,omitempty" json:"id,omitempty"`
 	Name                string               `bson:"name,omitempty" json:"name,omitempty"`
-	CarWashID           *primitive.ObjectID  `bson:"carwash_id,omitempty" json:"carwash_id,omitempty"`
+	CarWashID           *primitive.ObjectID  `bson:"carwash_id,omitempty" json:"carwash_id,omitempty"`   // Workers: their branch. Owners: their first branch
+	BusinessID          *primitive.ObjectID  `bson:"business_id,omitempty" json:"business_id,omitempty"` // Owners: the business that groups their branches
 	Email               string               `bson:"email,omitempty" json:"email,omitempty"`
 	Password            string               `bson:"password,omitempty" json:"password,omitempty"`
 	Phone               string               `bson:"phone,omitempty" json:"phone,omitempty"`
@@ -65,7 +66,9 @@ type User struct {
 	LoyaltyPoints       int                  `bson:"loyalty_points,omitempty" json:"loyalty_points,omitempty"`
 	LastSeen            *time.Time           `bson:"last_seen,omitempty" json:"last_seen,omitempty"`
 	JobRole             string               `bson:"job_role,omitempty" json:"job_role,omitempty"`
-	WorkerStatus        string               `bson:"worker_status,omitempty" json:"worker_status,omitempty"` // active, inactive, on break etc
+	WorkerStatus        string               `bson:"worker_status,omitempty" json:"worker_status,omitempty"`           // active, inactive, on break etc
+	StaffRole           string               `bson:"staff_role,omitempty" json:"staff_role,omitempty"`                 // manager, cashier, washer (workers only)
+	PreferredLanguage   string               `bson:"preferred_language,omitempty" json:"preferred_language,omitempty"` // locale for notifications and emails, e.g. "en", "fr"
 	CreatedAt           time.Time            `bson:"created_at" json:"created_at"`
 	UpdatedAt           time.Time            `bson:"updated_at" json:"updated_at"`
 	ActiveOrders        []primitive.ObjectID `bson:"active_orders,omitempty" json:"active_orders,omitempty"`
@@ -78,6 +81,13 @@ type User struct {
 	LastLocation *GeoPoint `bson:"last_location,omitempty" json:"-"`
 	// Base location for workers (e.g., home or office for trip calculations)
 	BaseLocation *GeoPoint `bson:"base_location,omitempty" json:"base_location,omitempty"`
+
+	// Two-factor authentication (TOTP). Secrets and recovery code hashes never leave the server.
+	TwoFactorEnabled       bool     `bson:"two_factor_enabled,omitempty" json:"two_factor_enabled,omitempty"`
+	TwoFactorSecret        string   `bson:"two_factor_secret,omitempty" json:"-"`
+	TwoFactorPendingSecret string   `bson:"two_factor_pending_secret,omitempty" json:"-"` // set during enrolment until confirmed
+	TwoFactorLastStep      int64    `bson:"two_factor_last_step,omitempty" json:"-"`      // last accepted TOTP step, blocks replays
+	RecoveryCodeHashes     []string `bson:"recovery_code_hashes,omitempty" json:"-"`
 }
 
 func (u User) Validate() error {
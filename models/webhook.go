@@ -0,0 +1,97 @@
+package models
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Webhook events a carwash can subscribe to
+const (
+	WebhookEventBookingCreated       = "booking.created"
+	WebhookEventBookingStatusChanged = "booking.status_changed"
+	WebhookEventOrderCompleted       = "order.completed"
+	WebhookEventPaymentSucceeded     = "payment.succeeded"
+	WebhookEventReviewCreated        = "review.created"
+)
+
+// WebhookEvents lists every event an endpoint can subscribe to
+var WebhookEvents = []string{
+	WebhookEventBookingCreated,
+	WebhookEventBookingStatusChanged,
+	WebhookEventOrderCompleted,
+	WebhookEventPaymentSucceeded,
+	WebhookEventReviewCreated,
+}
+
+// WebhookEndpoint is a URL of a carwash's own system that receives the events it subscribed to.
+// Every delivery is signed with Secret, which is only shown when the endpoint is created or its secret rotated.
+type WebhookEndpoint struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	CarwashID   primitive.ObjectID `bson:"carwash_id" json:"carwash_id"`
+	URL         string             `bson:"url" json:"url"`
+	Description string             `bson:"description,omitempty" json:"description,omitempty"`
+	Secret      string             `bson:"secret" json:"-"`
+	Events      []string           `bson:"events" json:"events"`
+	Active      bool               `bson:"active" json:"active"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+func (w WebhookEndpoint) Validate() error {
+	events := make([]interface{}, len(WebhookEvents))
+	for i, event := range WebhookEvents {
+		events[i] = event
+	}
+	return validation.ValidateStruct(&w,
+		validation.Field(&w.CarwashID, validation.Required),
+		validation.Field(&w.URL, validation.Required, validation.Length(0, 2048)),
+		validation.Field(&w.Description, validation.Length(0, 200)),
+		validation.Field(&w.Events, validation.Required, validation.Each(validation.In(events...))),
+	)
+}
+
+// Subscribed reports whether the endpoint receives an event
+func (w *WebhookEndpoint) Subscribed(event string) bool {
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one event sent to one endpoint, kept as the endpoint's delivery log.
+// Payload is the exact body that was signed, so a redelivery sends the same bytes.
+type WebhookDelivery struct {
+	ID             primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
+	EndpointID     primitive.ObjectID  `bson:"endpoint_id" json:"endpoint_id"`
+	CarwashID      primitive.ObjectID  `bson:"carwash_id" json:"carwash_id"`
+	EventID        string              `bson:"event_id" json:"event_id"` // shared by redeliveries, so receivers can drop duplicates
+	Event          string              `bson:"event" json:"event"`
+	Payload        string              `bson:"payload" json:"payload"`
+	Status         string              `bson:"status" json:"status"` // pending, succeeded, failed
+	Attempts       int                 `bson:"attempts" json:"attempts"`
+	ResponseStatus int                 `bson:"response_status,omitempty" json:"response_status,omitempty"`
+	ResponseBody   string              `bson:"response_body,omitempty" json:"response_body,omitempty"` // truncated
+	LastError      string              `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	DurationMs     int64               `bson:"duration_ms,omitempty" json:"duration_ms,omitempty"`
+	RedeliveryOf   *primitive.ObjectID `bson:"redelivery_of,omitempty" json:"redelivery_of,omitempty"`
+	DeliveredAt    *time.Time          `bson:"delivered_at,omitempty" json:"delivered_at,omitempty"`
+	CreatedAt      time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// JobKindWebhookDelivery sends a webhook delivery to its endpoint
+const JobKindWebhookDelivery = "webhook_delivery"
+
+// WebhookDeliveryMaxAttempts spreads retries over roughly an hour with the scheduler's backoff
+const WebhookDeliveryMaxAttempts = 8
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WorkerInvite is a single-use onboarding link sent to a worker created by a business owner.
+// The raw token is only ever sent to the worker; we keep its hash.
+type WorkerInvite struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	WorkerID   primitive.ObjectID `bson:"worker_id" json:"worker_id"`
+	CarwashID  primitive.ObjectID `bson:"carwash_id" json:"carwash_id"`
+	InvitedBy  primitive.ObjectID `bson:"invited_by" json:"invited_by"`
+	Name       string             `bson:"name" json:"name"`
+	Email      string             `bson:"email,omitempty" json:"email,omitempty"`
+	Phone      string             `bson:"phone,omitempty" json:"phone,omitempty"`
+	StaffRole  string             `bson:"staff_role" json:"staff_role"`
+	TokenHash  string             `bson:"token_hash" json:"-"`
+	Status     string             `bson:"status" json:"status"` // pending, accepted, revoked
+	ExpiresAt  time.Time          `bson:"expires_at" json:"expires_at"`
+	AcceptedAt *time.Time         `bson:"accepted_at,omitempty" json:"accepted_at,omitempty"`
+	RevokedAt  *time.Time         `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// Invite statuses
+const (
+	InviteStatusPending  = "pending"
+	InviteStatusAccepted = "accepted"
+	InviteStatusRevoked  = "revoked"
+)
+
+// WorkerInviteTTL is how long an invite link stays valid
+const WorkerInviteTTL = 72 * time.Hour
+
+func (i *WorkerInvite) SetDefaults() {
+	i.ID = primitive.NewObjectID()
+	i.Status = InviteStatusPending
+	i.CreatedAt = time.Now()
+	i.UpdatedAt = time.Now()
+	i.ExpiresAt = time.Now().Add(WorkerInviteTTL)
+}
+
+// IsExpired reports whether the invite can no longer be accepted
+func (i *WorkerInvite) IsExpired() bool {
+	return time.Now().After(i.ExpiresAt)
+}
@@ -0,0 +1,298 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// In-memory stand-ins for the repositories
+type (
+	fakeUsers     map[primitive.ObjectID]*models.User
+	fakeCarwashes map[primitive.ObjectID]*models.Carwash
+	fakeBookings  map[primitive.ObjectID]*models.Booking
+	fakeOrders    map[primitive.ObjectID]*models.Order
+	fakeBusiness  map[primitive.ObjectID]*models.Business
+)
+
+var errNotFound = errors.New("not found")
+
+func (f fakeUsers) FindUserByID(id primitive.ObjectID) (*models.User, error) {
+	if user, ok := f[id]; ok {
+		return user, nil
+	}
+	return nil, errNotFound
+}
+
+func (f fakeCarwashes) GetCarwashByID(id primitive.ObjectID) (*models.Carwash, error) {
+	if carwash, ok := f[id]; ok {
+		return carwash, nil
+	}
+	return nil, errNotFound
+}
+
+func (f fakeBookings) GetBookingByID(id primitive.ObjectID) (*models.Booking, error) {
+	if booking, ok := f[id]; ok {
+		return booking, nil
+	}
+	return nil, errNotFound
+}
+
+func (f fakeOrders) GetOrderByID(id primitive.ObjectID) (*models.Order, error) {
+	if order, ok := f[id]; ok {
+		return order, nil
+	}
+	return nil, errNotFound
+}
+
+func (f fakeBusiness) FindBusinessByID(id primitive.ObjectID) (*models.Business, error) {
+	if business, ok := f[id]; ok {
+		return business, nil
+	}
+	return nil, errNotFound
+}
+
+// fixture is one carwash with an owner, staff in every role and state, a customer,
+// and a booking and an order at the carwash
+type fixture struct {
+	policy *Policy
+
+	carwash, otherCarwash primitive.ObjectID
+	business              primitive.ObjectID
+	booking, order        primitive.ObjectID
+
+	owner, manager, cashier, washer, invited, inactive, otherStaff, customer, stranger, admin Subject
+}
+
+func newFixture() *fixture {
+	f := &fixture{
+		carwash:      primitive.NewObjectID(),
+		otherCarwash: primitive.NewObjectID(),
+		business:     primitive.NewObjectID(),
+		booking:      primitive.NewObjectID(),
+		order:        primitive.NewObjectID(),
+	}
+	users := fakeUsers{}
+	subject := func(role, accountType string) Subject {
+		return Subject{UserID: primitive.NewObjectID(), Role: role, AccountType: accountType}
+	}
+	staff := func(carwashID primitive.ObjectID, staffRole, status string) Subject {
+		sub := subject(utils.ROLE_WORKER, utils.ACCOUNT_TYPE_CAR_WASH)
+		users[sub.UserID] = &models.User{ID: sub.UserID, Role: sub.Role, CarWashID: &carwashID, StaffRole: staffRole, Status: status}
+		return sub
+	}
+
+	f.owner = subject(utils.ROLE_BUSINESS, utils.ACCOUNT_TYPE_CAR_WASH)
+	f.manager = staff(f.carwash, models.StaffRoleManager, "active")
+	f.cashier = staff(f.carwash, models.StaffRoleCashier, "active")
+	f.washer = staff(f.carwash, models.StaffRoleWasher, "active")
+	f.invited = staff(f.carwash, models.StaffRoleManager, "invited")
+	f.inactive = staff(f.carwash, models.StaffRoleManager, "inactive")
+	f.otherStaff = staff(f.otherCarwash, models.StaffRoleManager, "active")
+	f.customer = subject(utils.ROLE_CAR_OWNER, utils.ACCOUNT_TYPE_CAR_OWNER)
+	f.stranger = subject(utils.ROLE_CAR_OWNER, utils.ACCOUNT_TYPE_CAR_OWNER)
+	f.admin = subject(utils.ROLE_ADMIN, utils.ACCOUNT_TYPE_CAR_OWNER)
+	for _, sub := range []Subject{f.owner, f.customer, f.stranger, f.admin} {
+		users[sub.UserID] = &models.User{ID: sub.UserID, Role: sub.Role, Status: "active"}
+	}
+
+	workerID := f.washer.UserID
+	f.policy = &Policy{
+		userRepo: users,
+		carwashRepo: fakeCarwashes{
+			f.carwash: {ID: f.carwash, OwnerID: f.owner.UserID, BusinessID: &f.business},
+			// Claims the business through its own business_id, but isn't on the branch list
+			f.otherCarwash: {ID: f.otherCarwash, OwnerID: primitive.NewObjectID(), BusinessID: &f.business},
+		},
+		businessRepo: fakeBusiness{
+			f.business: {ID: f.business, OwnerID: f.owner.UserID, BranchIDs: []primitive.ObjectID{f.carwash}},
+		},
+		bookingRepo: fakeBookings{
+			f.booking: {ID: f.booking, UserID: f.customer.UserID, CarwashID: f.carwash, WorkerID: workerID},
+		},
+		orderRepo: fakeOrders{
+			f.order: {ID: f.order, UserID: f.customer.UserID, CarwashID: f.carwash, WorkerID: &workerID},
+		},
+	}
+	return f
+}
+
+type ruleCase struct {
+	name     string
+	sub      Subject
+	resource string
+	want     bool
+}
+
+func runRuleCases(t *testing.T, rule Rule, cases []ruleCase) {
+	t.Helper()
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := rule(tc.sub, tc.resource); got != tc.want {
+				t.Errorf("rule(%s) = %v, want %v", tc.resource, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCarwashOwner(t *testing.T) {
+	f := newFixture()
+	runRuleCases(t, f.policy.CarwashOwner, []ruleCase{
+		{"owner", f.owner, f.carwash.Hex(), true},
+		{"owner of another carwash", f.owner, f.otherCarwash.Hex(), false},
+		{"manager", f.manager, f.carwash.Hex(), false},
+		{"customer", f.customer, f.carwash.Hex(), false},
+		{"unknown carwash", f.owner, primitive.NewObjectID().Hex(), false},
+		{"malformed ID", f.owner, "not-an-id", false},
+	})
+}
+
+func TestCarwashStaff(t *testing.T) {
+	f := newFixture()
+	runRuleCases(t, f.policy.CarwashStaff, []ruleCase{
+		{"owner", f.owner, f.carwash.Hex(), true},
+		{"manager", f.manager, f.carwash.Hex(), true},
+		{"washer", f.washer, f.carwash.Hex(), true},
+		{"invited worker", f.invited, f.carwash.Hex(), false},
+		{"inactive worker", f.inactive, f.carwash.Hex(), false},
+		{"staff of another carwash", f.otherStaff, f.carwash.Hex(), false},
+		{"customer", f.customer, f.carwash.Hex(), false},
+		{"malformed ID", f.manager, "not-an-id", false},
+	})
+}
+
+func TestBusinessMember(t *testing.T) {
+	f := newFixture()
+	runRuleCases(t, f.policy.BusinessMember, []ruleCase{
+		{"owner", f.owner, f.business.Hex(), true},
+		{"branch staff", f.washer, f.business.Hex(), true},
+		{"staff of a carwash claiming the business", f.otherStaff, f.business.Hex(), false},
+		{"customer", f.customer, f.business.Hex(), false},
+		{"unknown business", f.owner, primitive.NewObjectID().Hex(), false},
+		{"malformed ID", f.owner, "not-an-id", false},
+	})
+}
+
+func TestBookingOwner(t *testing.T) {
+	f := newFixture()
+	runRuleCases(t, f.policy.BookingOwner, []ruleCase{
+		{"customer", f.customer, f.booking.Hex(), true},
+		{"another customer", f.stranger, f.booking.Hex(), false},
+		{"carwash owner", f.owner, f.booking.Hex(), false},
+		{"assigned worker", f.washer, f.booking.Hex(), false},
+		{"unknown booking", f.customer, primitive.NewObjectID().Hex(), false},
+		{"malformed ID", f.customer, "not-an-id", false},
+	})
+}
+
+func TestAssignedWorker(t *testing.T) {
+	f := newFixture()
+	unassigned := primitive.NewObjectID()
+	f.policy.bookingRepo.(fakeBookings)[unassigned] = &models.Booking{ID: unassigned, UserID: f.customer.UserID, CarwashID: f.carwash}
+
+	runRuleCases(t, f.policy.AssignedWorker, []ruleCase{
+		{"assigned worker", f.washer, f.booking.Hex(), true},
+		{"other staff", f.manager, f.booking.Hex(), false},
+		{"customer", f.customer, f.booking.Hex(), false},
+		{"no worker assigned", f.washer, unassigned.Hex(), false},
+		{"unknown booking", f.washer, primitive.NewObjectID().Hex(), false},
+	})
+}
+
+func TestOrderOwner(t *testing.T) {
+	f := newFixture()
+	runRuleCases(t, f.policy.OrderOwner, []ruleCase{
+		{"customer", f.customer, f.order.Hex(), true},
+		{"another customer", f.stranger, f.order.Hex(), false},
+		{"carwash owner", f.owner, f.order.Hex(), false},
+		{"unknown order", f.customer, primitive.NewObjectID().Hex(), false},
+		{"malformed ID", f.customer, "not-an-id", false},
+	})
+}
+
+func TestCan(t *testing.T) {
+	f := newFixture()
+	carwash := f.carwash.Hex()
+	cases := []struct {
+		name string
+		sub  Subject
+		perm models.Permission
+		want bool
+	}{
+		{"owner holds every permission", f.owner, models.PermManageWorkers, true},
+		{"manager manages workers", f.manager, models.PermManageWorkers, true},
+		{"cashier views finances", f.cashier, models.PermViewFinances, true},
+		{"cashier can't manage workers", f.cashier, models.PermManageWorkers, false},
+		{"washer can't manage bookings", f.washer, models.PermManageBookings, false},
+		{"invited manager has no permissions yet", f.invited, models.PermManageBookings, false},
+		{"inactive manager loses permissions", f.inactive, models.PermManageBookings, false},
+		{"manager of another carwash", f.otherStaff, models.PermManageBookings, false},
+		{"customer", f.customer, models.PermManageBookings, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := f.policy.Can(tc.perm)(tc.sub, carwash); got != tc.want {
+				t.Errorf("Can(%s) = %v, want %v", tc.perm, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCanOnBooking(t *testing.T) {
+	f := newFixture()
+	rule := f.policy.CanOnBooking(models.PermManageBookings)
+	runRuleCases(t, rule, []ruleCase{
+		{"owner", f.owner, f.booking.Hex(), true},
+		{"cashier", f.cashier, f.booking.Hex(), true},
+		{"washer", f.washer, f.booking.Hex(), false},
+		{"manager of another carwash", f.otherStaff, f.booking.Hex(), false},
+		{"customer", f.customer, f.booking.Hex(), false},
+		{"unknown booking", f.owner, primitive.NewObjectID().Hex(), false},
+	})
+}
+
+func TestCanOnOrder(t *testing.T) {
+	f := newFixture()
+	rule := f.policy.CanOnOrder(models.PermViewFinances)
+	runRuleCases(t, rule, []ruleCase{
+		{"owner", f.owner, f.order.Hex(), true},
+		{"cashier", f.cashier, f.order.Hex(), true},
+		{"washer", f.washer, f.order.Hex(), false},
+		{"manager of another carwash", f.otherStaff, f.order.Hex(), false},
+		{"customer", f.customer, f.order.Hex(), false},
+		{"unknown order", f.owner, primitive.NewObjectID().Hex(), false},
+	})
+}
+
+func TestAllowed(t *testing.T) {
+	f := newFixture()
+	deny := func(Subject, string) bool { return false }
+	allow := func(Subject, string) bool { return true }
+
+	cases := []struct {
+		name  string
+		sub   Subject
+		rules []Rule
+		want  bool
+	}{
+		{"admin passes without rules", f.admin, nil, true},
+		{"admin passes failing rules", f.admin, []Rule{deny}, true},
+		{"no rules deny", f.customer, nil, false},
+		{"every rule fails", f.customer, []Rule{deny, f.policy.CarwashOwner}, false},
+		{"any rule grants", f.customer, []Rule{deny, allow}, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := f.policy.Allowed(tc.sub, f.carwash.Hex(), tc.rules...); got != tc.want {
+				t.Errorf("Allowed = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	if err := f.policy.Authorize(f.customer, f.carwash.Hex(), deny); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Authorize = %v, want ErrForbidden", err)
+	}
+}
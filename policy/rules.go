@@ -0,0 +1,161 @@
+package policy
+
+import (
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// HasRole grants access by role alone, ignoring the resource
+func HasRole(roles ...string) Rule {
+	return func(sub Subject, _ string) bool {
+		for _, role := range roles {
+			if sub.Role == role {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Authenticated grants access to any logged-in caller
+func Authenticated(sub Subject, _ string) bool {
+	return !sub.UserID.IsZero()
+}
+
+// Self: the resource is the caller's own user account
+func (p *Policy) Self(sub Subject, userID string) bool {
+	return sub.UserID.Hex() == userID
+}
+
+// CarwashOwner: the caller is the business owner of the carwash
+func (p *Policy) CarwashOwner(sub Subject, carwashID string) bool {
+	carwash := p.loadCarwash(carwashID)
+	return carwash != nil && carwash.OwnerID == sub.UserID
+}
+
+// CarwashStaff: the caller owns the carwash or is active staff there.
+// Invited, inactive and revoked workers don't count.
+func (p *Policy) CarwashStaff(sub Subject, carwashID string) bool {
+	if p.CarwashOwner(sub, carwashID) {
+		return true
+	}
+	objID, err := primitive.ObjectIDFromHex(carwashID)
+	if err != nil {
+		return false
+	}
+	user, err := p.userRepo.FindUserByID(sub.UserID)
+	return err == nil && user.CarWashID != nil && *user.CarWashID == objID && user.Status == "active"
+}
+
+// BusinessOwner: the caller runs the business (and so every one of its branches)
+func (p *Policy) BusinessOwner(sub Subject, businessID string) bool {
+	business := p.loadBusiness(businessID)
+	return business != nil && business.OwnerID == sub.UserID
+}
+
+// BusinessMember: the caller owns the business or works at one of its branches.
+// What they may see is narrowed to their branch by the permission checks.
+func (p *Policy) BusinessMember(sub Subject, businessID string) bool {
+	business := p.loadBusiness(businessID)
+	if business == nil {
+		return false
+	}
+	if business.OwnerID == sub.UserID {
+		return true
+	}
+	// The business's own branch list decides, not the branch's business_id
+	user, err := p.userRepo.FindUserByID(sub.UserID)
+	return err == nil && user.CarWashID != nil && business.HasBranch(*user.CarWashID)
+}
+
+// BookingOwner: the caller is the customer who made the booking
+func (p *Policy) BookingOwner(sub Subject, bookingID string) bool {
+	booking := p.loadBooking(bookingID)
+	return booking != nil && booking.UserID == sub.UserID
+}
+
+// AssignedWorker: the caller is the worker assigned to the booking
+func (p *Policy) AssignedWorker(sub Subject, bookingID string) bool {
+	booking := p.loadBooking(bookingID)
+	return booking != nil && !booking.WorkerID.IsZero() && booking.WorkerID == sub.UserID
+}
+
+// OrderOwner: the caller is the customer on the order
+func (p *Policy) OrderOwner(sub Subject, orderID string) bool {
+	order := p.loadOrder(orderID)
+	return order != nil && order.UserID == sub.UserID
+}
+
+// OrderAssignedWorker: the caller is the worker assigned to the order
+func (p *Policy) OrderAssignedWorker(sub Subject, orderID string) bool {
+	order := p.loadOrder(orderID)
+	return order != nil && order.WorkerID != nil && *order.WorkerID == sub.UserID
+}
+
+// CarOwner: the caller owns the car
+func (p *Policy) CarOwner(sub Subject, carID string) bool {
+	objID, err := primitive.ObjectIDFromHex(carID)
+	if err != nil {
+		return false
+	}
+	car, err := p.carRepo.GetCarByID(objID)
+	return err == nil && car.OwnerID == sub.UserID
+}
+
+// NotificationOwner: the notification was sent to the caller
+func (p *Policy) NotificationOwner(sub Subject, notificationID string) bool {
+	objID, err := primitive.ObjectIDFromHex(notificationID)
+	if err != nil {
+		return false
+	}
+	notification, err := p.notifRepo.FindByID(objID)
+	return err == nil && notification.UserID == sub.UserID
+}
+
+func (p *Policy) loadCarwash(carwashID string) *models.Carwash {
+	objID, err := primitive.ObjectIDFromHex(carwashID)
+	if err != nil {
+		return nil
+	}
+	carwash, err := p.carwashRepo.GetCarwashByID(objID)
+	if err != nil {
+		return nil
+	}
+	return carwash
+}
+
+func (p *Policy) loadBusiness(businessID string) *models.Business {
+	objID, err := primitive.ObjectIDFromHex(businessID)
+	if err != nil {
+		return nil
+	}
+	business, err := p.businessRepo.FindBusinessByID(objID)
+	if err != nil {
+		return nil
+	}
+	return business
+}
+
+func (p *Policy) loadBooking(bookingID string) *models.Booking {
+	objID, err := primitive.ObjectIDFromHex(bookingID)
+	if err != nil {
+		return nil
+	}
+	booking, err := p.bookingRepo.GetBookingByID(objID)
+	if err != nil {
+		return nil
+	}
+	return booking
+}
+
+func (p *Policy) loadOrder(orderID string) *models.Order {
+	objID, err := primitive.ObjectIDFromHex(orderID)
+	if err != nil {
+		return nil
+	}
+	order, err := p.orderRepo.GetOrderByID(objID)
+	if err != nil {
+		return nil
+	}
+	return order
+}
@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AuditRepository handles database operations for audit events
+type AuditRepository struct {
+	db *mongo.Database
+}
+
+// NewAuditRepository creates a new AuditRepository instance
+func NewAuditRepository(db *mongo.Database) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// CreateEvent records an audit event
+func (ar *AuditRepository) CreateEvent(event *models.AuditEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := ar.db.Collection("audit_events").InsertOne(ctx, event)
+	return err
+}
+
+// ListEvents returns the newest events matching the filter
+func (ar *AuditRepository) ListEvents(filter bson.M, limit int64) ([]models.AuditEvent, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.M{"created_at": -1}).SetLimit(limit)
+	cursor, err := ar.db.Collection("audit_events").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	events := []models.AuditEvent{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
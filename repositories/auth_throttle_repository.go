@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AuthThrottleRepository handles database operations for auth attempt counters
+type AuthThrottleRepository struct {
+	db *mongo.Database
+}
+
+// NewAuthThrottleRepository creates a new AuthThrottleRepository instance
+func NewAuthThrottleRepository(db *mongo.Database) *AuthThrottleRepository {
+	return &AuthThrottleRepository{db: db}
+}
+
+// FindThrottle gets the live counter for a key, or nil if there is none
+func (tr *AuthThrottleRepository) FindThrottle(key string) (*models.AuthThrottle, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var throttle models.AuthThrottle
+	err := tr.db.Collection("auth_throttles").FindOne(ctx, bson.M{
+		"_id":        key,
+		"expires_at": bson.M{"$gt": time.Now()},
+	}).Decode(&throttle)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+// Increment atomically bumps the counter for a key and returns it.
+// A counter whose window has passed starts again from 1.
+func (tr *AuthThrottleRepository) Increment(key string, window time.Duration) (*models.AuthThrottle, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection := tr.db.Collection("auth_throttles")
+
+	// The TTL monitor only runs once a minute, so clear a stale window ourselves
+	if _, err := collection.DeleteOne(ctx, bson.M{"_id": key, "expires_at": bson.M{"$lte": time.Now()}}); err != nil {
+		return nil, err
+	}
+
+	var throttle models.AuthThrottle
+	err := collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": key},
+		bson.M{
+			"$inc": bson.M{"count": 1},
+			"$set": bson.M{"expires_at": time.Now().Add(window)},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&throttle)
+	if err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+// Lock blocks a key until the given time, keeping the record at least that long
+func (tr *AuthThrottleRepository) Lock(key string, until time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := tr.db.Collection("auth_throttles").UpdateOne(ctx,
+		bson.M{"_id": key},
+		bson.M{
+			"$set": bson.M{"locked_until": until},
+			"$max": bson.M{"expires_at": until},
+		},
+	)
+	return err
+}
+
+// Reset clears a key (e.g. after a successful login)
+func (tr *AuthThrottleRepository) Reset(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := tr.db.Collection("auth_throttles").DeleteOne(ctx, bson.M{"_id": key})
+	return err
+}
@@ -185,11 +185,16 @@ func (br *BookingRepository) GetBookingsByDate(carwashID primitive.ObjectID, dat
 
 // GetBookingsByCarwashWithFilters retrieves bookings for a car wash filtered by status and date range
 func (br *BookingRepository) GetBookingsByCarwashWithFilters(carwashID primitive.ObjectID, status string, from, to time.Time) ([]models.Booking, error) {
+	return br.GetBookingsByCarwashesWithFilters([]primitive.ObjectID{carwashID}, status, from, to)
+}
+
+// GetBookingsByCarwashesWithFilters is GetBookingsByCarwashWithFilters across several branches
+func (br *BookingRepository) GetBookingsByCarwashesWithFilters(carwashIDs []primitive.ObjectID, status string, from, to time.Time) ([]models.Booking, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	filter := bson.M{
-		"carwash_id": carwashID,
+		"carwash_id": bson.M{"$in": carwashIDs},
 	}
 	if status != "" && status != "all" {
 		filter["status"] = status
@@ -220,3 +225,83 @@ func (br *BookingRepository) GetBookingsByCarwashWithFilters(carwashID primitive
 
 	return bookings, nil
 }
+
+// BranchBookingCount is the number of bookings a branch has in one status
+type BranchBookingCount struct {
+	CarwashID primitive.ObjectID `bson:"carwash_id"`
+	Status    string             `bson:"status"`
+	Count     int                `bson:"count"`
+}
+
+// CountBookingsByBranch counts bookings per branch and status, optionally within a booking_time range
+func (br *BookingRepository) CountBookingsByBranch(carwashIDs []primitive.ObjectID, from, to time.Time) ([]BranchBookingCount, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	match := bson.M{"carwash_id": bson.M{"$in": carwashIDs}}
+	if !from.IsZero() && !to.IsZero() {
+		match["booking_time"] = bson.M{"$gte": from, "$lte": to}
+	}
+
+	pipeline := bson.A{
+		bson.M{"$match": match},
+		bson.M{"$group": bson.M{
+			"_id":   bson.M{"carwash_id": "$carwash_id", "status": "$status"},
+			"count": bson.M{"$sum": 1},
+		}},
+		bson.M{"$project": bson.M{
+			"_id":        0,
+			"carwash_id": "$_id.carwash_id",
+			"status":     "$_id.status",
+			"count":      1,
+		}},
+	}
+
+	cursor, err := database.BookingCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var counts []BranchBookingCount
+	if err := cursor.All(ctx, &counts); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// MarkArrivalNotified records that the "worker arriving soon" alert went out.
+// Returns false if it was already recorded, so concurrent location pings only alert once.
+func (br *BookingRepository) MarkArrivalNotified(id primitive.ObjectID) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := database.BookingCollection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "arrival_notified_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"arrival_notified_at": time.Now()}},
+	)
+	if err != nil {
+		logrus.Error("Failed to mark arrival notification: ", err)
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// MarkArrivedNotified records that the "worker arrived" alert went out.
+// Returns false if it was already recorded, so only the first on-site ping alerts.
+func (br *BookingRepository) MarkArrivedNotified(id primitive.ObjectID) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := database.BookingCollection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "arrived_notified_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"arrived_notified_at": time.Now()}},
+	)
+	if err != nil {
+		logrus.Error("Failed to mark arrived notification: ", err)
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
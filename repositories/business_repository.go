@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// BusinessRepository handles database operations for businesses
+type BusinessRepository struct {
+	db *mongo.Database
+}
+
+// NewBusinessRepository creates a new BusinessRepository instance
+func NewBusinessRepository(db *mongo.Database) *BusinessRepository {
+	return &BusinessRepository{db: db}
+}
+
+// CreateBusiness inserts a new business
+func (br *BusinessRepository) CreateBusiness(business *models.Business) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := br.db.Collection("businesses").InsertOne(ctx, business)
+	return err
+}
+
+// FindBusinessByID gets a business by its ID
+func (br *BusinessRepository) FindBusinessByID(id primitive.ObjectID) (*models.Business, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var business models.Business
+	if err := br.db.Collection("businesses").FindOne(ctx, bson.M{"_id": id}).Decode(&business); err != nil {
+		return nil, err
+	}
+	return &business, nil
+}
+
+// FindBusinessByOwnerID gets the business an owner runs
+func (br *BusinessRepository) FindBusinessByOwnerID(ownerID primitive.ObjectID) (*models.Business, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var business models.Business
+	if err := br.db.Collection("businesses").FindOne(ctx, bson.M{"owner_id": ownerID}).Decode(&business); err != nil {
+		return nil, err
+	}
+	return &business, nil
+}
+
+// FindBusinessesWithoutBranchList gets businesses created before they kept a list of their branches
+func (br *BusinessRepository) FindBusinessesWithoutBranchList() ([]models.Business, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := br.db.Collection("businesses").Find(ctx, bson.M{"branch_ids": bson.M{"$exists": false}})
+	if err != nil {
+		return nil, err
+	}
+	var businesses []models.Business
+	if err := cursor.All(ctx, &businesses); err != nil {
+		return nil, err
+	}
+	return businesses, nil
+}
+
+// AddBranches adds carwashes to a business's branch list
+func (br *BusinessRepository) AddBranches(id primitive.ObjectID, carwashIDs []primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := br.db.Collection("businesses").UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$addToSet": bson.M{"branch_ids": bson.M{"$each": carwashIDs}},
+			"$set":      bson.M{"updated_at": time.Now()},
+		},
+	)
+	return err
+}
+
+// UpdateBusiness sets fields on a business
+func (br *BusinessRepository) UpdateBusiness(id primitive.ObjectID, update bson.M) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	update["updated_at"] = time.Now()
+	result, err := br.db.Collection("businesses").UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": update})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("business not found")
+	}
+	return nil
+}
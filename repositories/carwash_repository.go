@@ -55,7 +55,7 @@ func (cw *CarWashRepository) GetActiveCarwashes() ([]models.Carwash, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	cursor, err := database.CarwashCollection.Find(ctx, bson.M{"is_active": true})
+	cursor, err := database.CarwashCollection.Find(ctx, withListingApproved(bson.M{"is_active": true}))
 	if err != nil {
 		return nil, err
 	}
@@ -156,6 +156,92 @@ func (cw *CarWashRepository) GetCarwashesByFilter(filter bson.M) ([]models.Carwa
 	return carwashes, nil
 }
 
+// SetApprovalStatus records a platform admin's moderation decision on a listing
+func (cw *CarWashRepository) SetApprovalStatus(id primitive.ObjectID, status, note string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	set := bson.M{
+		"approval_status": status,
+		"approval_note":   note,
+		"updated_at":      time.Now(),
+	}
+	if status == models.CarwashApprovalApproved {
+		set["approved_at"] = time.Now()
+	}
+
+	result, err := database.CarwashCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": set})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("carwash not found")
+	}
+	return nil
+}
+
+// AddVerificationDocument stores a KYC document. A rejected listing goes back to pending_review
+// so the new document gets looked at.
+func (cw *CarWashRepository) AddVerificationDocument(id primitive.ObjectID, doc models.VerificationDocument) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := database.CarwashCollection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$push": bson.M{"verification_documents": doc},
+			"$set":  bson.M{"updated_at": time.Now()},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("carwash not found")
+	}
+
+	_, err = database.CarwashCollection.UpdateOne(ctx,
+		bson.M{"_id": id, "approval_status": models.CarwashApprovalRejected},
+		bson.M{
+			"$set":   bson.M{"approval_status": models.CarwashApprovalPendingReview},
+			"$unset": bson.M{"approval_note": ""},
+		},
+	)
+	return err
+}
+
+// GetCarwashesByIDs returns the carwashes with the given IDs
+func (cw *CarWashRepository) GetCarwashesByIDs(ids []primitive.ObjectID) ([]models.Carwash, error) {
+	return cw.GetCarwashesByFilter(bson.M{"_id": bson.M{"$in": ids}})
+}
+
+// GetOwnerBranches returns the owner's carwashes filed under businessID
+func (cw *CarWashRepository) GetOwnerBranches(ownerID, businessID primitive.ObjectID) ([]models.Carwash, error) {
+	return cw.GetCarwashesByFilter(bson.M{"owner_id": ownerID, "business_id": businessID})
+}
+
+// AttachOwnerCarwashes links an owner's carwashes that have no business yet to businessID
+func (cw *CarWashRepository) AttachOwnerCarwashes(ownerID, businessID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := database.CarwashCollection.UpdateMany(ctx,
+		bson.M{"owner_id": ownerID, "business_id": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"business_id": businessID, "updated_at": time.Now()}},
+	)
+	return err
+}
+
+// withListingApproved limits a customer-facing query to approved listings.
+// Carwashes created before moderation existed have no status and stay visible.
+func withListingApproved(filter bson.M) bson.M {
+	filter["$or"] = bson.A{
+		bson.M{"approval_status": models.CarwashApprovalApproved},
+		bson.M{"approval_status": bson.M{"$exists": false}},
+	}
+	return filter
+}
+
 // 9. Update queue count
 func (cw *CarWashRepository) UpdateQueueCount(id string, count int) error {
 	return cw.UpdateCarwash(id, bson.M{"queue_count": count})
@@ -209,7 +295,7 @@ func (cw *CarWashRepository) findCarwashesInRadius(userLat, userLng, radiusKm fl
 		"has_location": true,
 	}
 
-	carwashes, err := cw.GetCarwashesByFilter(filter)
+	carwashes, err := cw.GetCarwashesByFilter(withListingApproved(filter))
 	if err != nil {
 		return nil, err
 	}
@@ -229,7 +315,7 @@ func (cw *CarWashRepository) findAllCarwashesWithLocation() ([]*models.Carwash,
 		"has_location": true,
 	}
 
-	carwashes, err := cw.GetCarwashesByFilter(filter)
+	carwashes, err := cw.GetCarwashesByFilter(withListingApproved(filter))
 	if err != nil {
 		return nil, err
 	}
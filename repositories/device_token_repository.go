@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DeviceTokenRepository stores push tokens of users' devices
+type DeviceTokenRepository struct {
+	db *mongo.Database
+}
+
+// NewDeviceTokenRepository creates a new DeviceTokenRepository instance
+func NewDeviceTokenRepository(db *mongo.Database) *DeviceTokenRepository {
+	return &DeviceTokenRepository{db: db}
+}
+
+// Register saves a device token, or refreshes it (and its owner) when it is already known
+func (dr *DeviceTokenRepository) Register(device *models.DeviceToken) (*models.DeviceToken, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	var saved models.DeviceToken
+	err := dr.db.Collection("device_tokens").FindOneAndUpdate(ctx,
+		bson.M{"token": device.Token},
+		bson.M{
+			"$set": bson.M{
+				"user_id":      device.UserID,
+				"platform":     device.Platform,
+				"app_version":  device.AppVersion,
+				"last_seen_at": now,
+			},
+			"$setOnInsert": bson.M{"created_at": now},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&saved)
+	if err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// FindByUserID returns every device registered for a user, most recently seen first
+func (dr *DeviceTokenRepository) FindByUserID(userID primitive.ObjectID) ([]models.DeviceToken, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.M{"last_seen_at": -1})
+	cursor, err := dr.db.Collection("device_tokens").Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	devices := []models.DeviceToken{}
+	if err := cursor.All(ctx, &devices); err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
+
+// DeleteUserToken removes one of a user's devices, e.g. on logout
+func (dr *DeviceTokenRepository) DeleteUserToken(userID primitive.ObjectID, token string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := dr.db.Collection("device_tokens").DeleteOne(ctx, bson.M{"user_id": userID, "token": token})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// DeleteToken removes a token the push provider rejected
+func (dr *DeviceTokenRepository) DeleteToken(token string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := dr.db.Collection("device_tokens").DeleteOne(ctx, bson.M{"token": token})
+	return err
+}
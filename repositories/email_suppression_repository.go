@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EmailSuppressionRepository stores addresses that must not be mailed again
+type EmailSuppressionRepository struct {
+	db *mongo.Database
+}
+
+// NewEmailSuppressionRepository creates a new EmailSuppressionRepository instance
+func NewEmailSuppressionRepository(db *mongo.Database) *EmailSuppressionRepository {
+	return &EmailSuppressionRepository{db: db}
+}
+
+// Suppress adds an address, or records another report for one already on the list.
+// A complaint outranks a bounce, so a later bounce report doesn't hide it.
+func (sr *EmailSuppressionRepository) Suppress(address, reason, detail string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	set := bson.M{"detail": detail, "updated_at": now}
+	setOnInsert := bson.M{"created_at": now}
+	if reason == models.EmailSuppressionComplaint {
+		set["reason"] = reason
+	} else {
+		setOnInsert["reason"] = reason
+	}
+
+	_, err := sr.db.Collection("email_suppressions").UpdateOne(ctx,
+		bson.M{"email": address},
+		bson.M{"$set": set, "$setOnInsert": setOnInsert, "$inc": bson.M{"reports": 1}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// IsSuppressed reports whether an address is on the list
+func (sr *EmailSuppressionRepository) IsSuppressed(address string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := sr.db.Collection("email_suppressions").FindOne(ctx, bson.M{"email": address}).Err()
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
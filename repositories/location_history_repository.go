@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LocationHistoryRepository handles the worker location_history time-series collection
+type LocationHistoryRepository struct {
+	db *mongo.Database
+}
+
+// NewLocationHistoryRepository creates a new LocationHistoryRepository instance
+func NewLocationHistoryRepository(db *mongo.Database) *LocationHistoryRepository {
+	return &LocationHistoryRepository{db: db}
+}
+
+// AppendPing stores a single worker position
+func (lr *LocationHistoryRepository) AppendPing(ping models.LocationPing) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if ping.RecordedAt.IsZero() {
+		ping.RecordedAt = time.Now()
+	}
+
+	_, err := lr.db.Collection("location_history").InsertOne(ctx, ping)
+	return err
+}
+
+// GetPingsByBookingID returns every ping for a booking in chronological order
+func (lr *LocationHistoryRepository) GetPingsByBookingID(bookingID primitive.ObjectID) ([]models.LocationPing, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{primitive.E{Key: "recorded_at", Value: 1}})
+	cursor, err := lr.db.Collection("location_history").Find(ctx, bson.M{"meta.booking_id": bookingID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	pings := []models.LocationPing{}
+	if err := cursor.All(ctx, &pings); err != nil {
+		return nil, err
+	}
+	return pings, nil
+}
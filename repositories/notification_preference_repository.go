@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NotificationPreferenceRepository stores per-user notification preferences
+type NotificationPreferenceRepository struct {
+	db *mongo.Database
+}
+
+// NewNotificationPreferenceRepository creates a new NotificationPreferenceRepository instance
+func NewNotificationPreferenceRepository(db *mongo.Database) *NotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{db: db}
+}
+
+// FindOrCreate returns the user's preferences, saving the given defaults the first time
+func (pr *NotificationPreferenceRepository) FindOrCreate(defaults *models.NotificationPreferences) (*models.NotificationPreferences, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var prefs models.NotificationPreferences
+	err := pr.db.Collection("notification_preferences").FindOneAndUpdate(ctx,
+		bson.M{"user_id": defaults.UserID},
+		bson.M{"$setOnInsert": bson.M{
+			"events":            defaults.Events,
+			"quiet_hours":       defaults.QuietHours,
+			"unsubscribe_token": defaults.UnsubscribeToken,
+			"updated_at":        time.Now(),
+		}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&prefs)
+	if err != nil {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+// FindByUnsubscribeToken returns the preferences an unsubscribe link points at
+func (pr *NotificationPreferenceRepository) FindByUnsubscribeToken(token string) (*models.NotificationPreferences, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var prefs models.NotificationPreferences
+	err := pr.db.Collection("notification_preferences").FindOne(ctx, bson.M{"unsubscribe_token": token}).Decode(&prefs)
+	if err != nil {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+// Update saves the user's event and quiet-hours choices
+func (pr *NotificationPreferenceRepository) Update(userID primitive.ObjectID, events map[string]models.ChannelPreferences, quietHours models.QuietHours) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := pr.db.Collection("notification_preferences").UpdateOne(ctx,
+		bson.M{"user_id": userID},
+		bson.M{"$set": bson.M{
+			"events":      events,
+			"quiet_hours": quietHours,
+			"updated_at":  time.Now(),
+		}},
+	)
+	return err
+}
+
+// DisableChannel switches one channel off for one notification type
+func (pr *NotificationPreferenceRepository) DisableChannel(userID primitive.ObjectID, notificationType, channel string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := pr.db.Collection("notification_preferences").UpdateOne(ctx,
+		bson.M{"user_id": userID},
+		bson.M{"$set": bson.M{
+			"events." + notificationType + "." + channel: false,
+			"updated_at": time.Now(),
+		}},
+	)
+	return err
+}
@@ -4,131 +4,163 @@ import (
 	"context"
 	"time"
 
-	"github.com/olabanji12-ojo/CarWashApp/database"
 	"github.com/olabanji12-ojo/CarWashApp/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// CreateNotification creates a new notification in the database
-func CreateNotification(notification models.Notification) (*models.Notification, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	notification.ID = primitive.NewObjectID()
-	notification.CreatedAt = time.Now()
-	notification.IsRead = false
-	notification.EmailSent = false
-
-	result, err := database.NotificationCollection.InsertOne(ctx, notification)
-	if err != nil {
-		return nil, err
-	}
+// NotificationRepository stores users' in-app notifications.
+// New notifications are written by OutboxRepository together with their deliveries.
+type NotificationRepository struct {
+	db *mongo.Database
+}
 
-	notification.ID = result.InsertedID.(primitive.ObjectID)
-	return &notification, nil
+// NewNotificationRepository creates a new NotificationRepository instance
+func NewNotificationRepository(db *mongo.Database) *NotificationRepository {
+	return &NotificationRepository{db: db}
 }
 
-// GetNotificationsByUserID gets all notifications for a specific user
-func GetNotificationsByUserID(userID string, limit int) ([]models.Notification, error) {
-	objID, err := primitive.ObjectIDFromHex(userID)
-	if err != nil {
-		return nil, err
-	}
+// NotificationFilter narrows a user's inbox
+type NotificationFilter struct {
+	Type     string
+	Read     *bool // nil for read and unread
+	Archived bool  // the archive instead of the inbox
+	// Keyset cursor: only notifications older than this one (by created_at, then _id)
+	BeforeTime time.Time
+	BeforeID   primitive.ObjectID
+}
 
+// FindByUser returns up to limit of a user's notifications matching the filter, newest first
+func (nr *NotificationRepository) FindByUser(userID primitive.ObjectID, filter NotificationFilter, limit int64) ([]models.Notification, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Set default limit if not provided
-	if limit <= 0 {
-		limit = 50
+	query := bson.M{"user_id": userID}
+	if filter.Archived {
+		query["archived_at"] = bson.M{"$exists": true}
+	} else {
+		query["archived_at"] = bson.M{"$exists": false}
+	}
+	if filter.Type != "" {
+		query["type"] = filter.Type
+	}
+	if filter.Read != nil {
+		query["is_read"] = *filter.Read
+	}
+	if !filter.BeforeID.IsZero() {
+		query["$or"] = bson.A{
+			bson.M{"created_at": bson.M{"$lt": filter.BeforeTime}},
+			bson.M{"created_at": filter.BeforeTime, "_id": bson.M{"$lt": filter.BeforeID}},
+		}
 	}
 
-	opts := options.Find().SetSort(bson.D{primitive.E{Key: "created_at", Value: -1}}).SetLimit(int64(limit))
-	cursor, err := database.NotificationCollection.Find(ctx, bson.M{"user_id": objID}, opts)
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(limit)
+	cursor, err := nr.db.Collection("notifications").Find(ctx, query, opts)
 	if err != nil {
 		return nil, err
 	}
 	defer cursor.Close(ctx)
 
-	var notifications []models.Notification
-	for cursor.Next(ctx) {
-		var notification models.Notification
-		if err := cursor.Decode(&notification); err == nil {
-			notifications = append(notifications, notification)
-		}
+	notifications := []models.Notification{}
+	if err := cursor.All(ctx, &notifications); err != nil {
+		return nil, err
 	}
-
 	return notifications, nil
 }
 
-// MarkNotificationAsRead marks a notification as read
-func MarkNotificationAsRead(notificationID string) error {
-	objID, err := primitive.ObjectIDFromHex(notificationID)
+// FindByID fetches a single notification
+func (nr *NotificationRepository) FindByID(id primitive.ObjectID) (*models.Notification, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var notification models.Notification
+	err := nr.db.Collection("notifications").FindOne(ctx, bson.M{"_id": id}).Decode(&notification)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return &notification, nil
+}
 
+// MarkAsRead marks a notification as read. The read time starts its retention period.
+func (nr *NotificationRepository) MarkAsRead(id primitive.ObjectID) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	_, err = database.NotificationCollection.UpdateOne(
-		ctx,
-		bson.M{"_id": objID},
-		bson.M{"$set": bson.M{"is_read": true}},
+	_, err := nr.db.Collection("notifications").UpdateOne(ctx,
+		bson.M{"_id": id, "is_read": false},
+		bson.M{"$set": bson.M{"is_read": true, "read_at": time.Now()}},
 	)
+	return err
+}
+
+// MarkAllAsRead marks every unread notification of a user as read
+func (nr *NotificationRepository) MarkAllAsRead(userID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
+	_, err := nr.db.Collection("notifications").UpdateMany(ctx,
+		bson.M{"user_id": userID, "is_read": false},
+		bson.M{"$set": bson.M{"is_read": true, "read_at": time.Now()}},
+	)
 	return err
 }
 
-// MarkNotificationEmailSent marks a notification as email sent
-func MarkNotificationEmailSent(notificationID primitive.ObjectID) error {
+// MarkEmailSent marks a notification as email sent
+func (nr *NotificationRepository) MarkEmailSent(id primitive.ObjectID) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	_, err := database.NotificationCollection.UpdateOne(
-		ctx,
-		bson.M{"_id": notificationID},
+	_, err := nr.db.Collection("notifications").UpdateOne(ctx,
+		bson.M{"_id": id},
 		bson.M{"$set": bson.M{"email_sent": true}},
 	)
-
 	return err
 }
 
-// GetUnreadNotificationCount gets count of unread notifications for a user
-func GetUnreadNotificationCount(userID string) (int64, error) {
-	objID, err := primitive.ObjectIDFromHex(userID)
-	if err != nil {
-		return 0, err
-	}
-
+// CountUnread counts a user's unread notifications in the inbox
+func (nr *NotificationRepository) CountUnread(userID primitive.ObjectID) (int64, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	count, err := database.NotificationCollection.CountDocuments(ctx, bson.M{
-		"user_id": objID,
-		"is_read": false,
+	return nr.db.Collection("notifications").CountDocuments(ctx, bson.M{
+		"user_id":     userID,
+		"is_read":     false,
+		"archived_at": bson.M{"$exists": false},
 	})
-
-	return count, err
 }
 
-// MarkAllNotificationsAsRead marks all notifications as read for a user
-func MarkAllNotificationsAsRead(userID string) error {
-	objID, err := primitive.ObjectIDFromHex(userID)
+// Archive moves notifications of a user out of the inbox. Archiving also marks them read.
+func (nr *NotificationRepository) Archive(userID primitive.ObjectID, ids []primitive.ObjectID) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	filter := bson.M{"user_id": userID, "_id": bson.M{"$in": ids}, "archived_at": bson.M{"$exists": false}}
+	// read_at is only set where it is missing, so archiving doesn't extend retention
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"archived_at": now,
+		"is_read":     true,
+		"read_at":     bson.M{"$ifNull": bson.A{"$read_at", now}},
+	}}}}
+	result, err := nr.db.Collection("notifications").UpdateMany(ctx, filter, update)
 	if err != nil {
-		return err
+		return 0, err
 	}
+	return result.ModifiedCount, nil
+}
 
+// Delete removes notifications of a user
+func (nr *NotificationRepository) Delete(userID primitive.ObjectID, ids []primitive.ObjectID) (int64, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	_, err = database.NotificationCollection.UpdateMany(
-		ctx,
-		bson.M{"user_id": objID, "is_read": false},
-		bson.M{"$set": bson.M{"is_read": true}},
-	)
-
-	return err
+	result, err := nr.db.Collection("notifications").DeleteMany(ctx, bson.M{"user_id": userID, "_id": bson.M{"$in": ids}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
 }
@@ -0,0 +1,214 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// OutboxRepository stores notification delivery jobs
+type OutboxRepository struct {
+	db *mongo.Database
+}
+
+// NewOutboxRepository creates a new OutboxRepository instance
+func NewOutboxRepository(db *mongo.Database) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// CreateNotificationWithJobs saves a notification and its delivery jobs in one transaction,
+// so a notification is never stored without the deliveries it promised.
+// Standalone servers have no transactions; there the writes fall back to running one after another.
+func (or *OutboxRepository) CreateNotificationWithJobs(notification *models.Notification, jobs []*models.NotificationJob) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	notification.ID = primitive.NewObjectID()
+	notification.CreatedAt = time.Now()
+	notification.IsRead = false
+	notification.EmailSent = false
+	for _, job := range jobs {
+		job.NotificationID = &notification.ID
+	}
+
+	session, err := or.db.Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, or.insertNotificationWithJobs(sc, notification, jobs)
+	})
+	if isTransactionUnsupported(err) {
+		return or.insertNotificationWithJobs(ctx, notification, jobs)
+	}
+	return err
+}
+
+func (or *OutboxRepository) insertNotificationWithJobs(ctx context.Context, notification *models.Notification, jobs []*models.NotificationJob) error {
+	if _, err := or.db.Collection("notifications").InsertOne(ctx, notification); err != nil {
+		return err
+	}
+	return or.insertJobs(ctx, jobs)
+}
+
+// EnqueueJobs saves delivery jobs that don't belong to an in-app notification
+func (or *OutboxRepository) EnqueueJobs(jobs []*models.NotificationJob) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return or.insertJobs(ctx, jobs)
+}
+
+func (or *OutboxRepository) insertJobs(ctx context.Context, jobs []*models.NotificationJob) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+	docs := make([]interface{}, len(jobs))
+	for i, job := range jobs {
+		docs[i] = job
+	}
+	_, err := or.db.Collection("notification_jobs").InsertMany(ctx, docs)
+	return err
+}
+
+// ClaimNext leases the oldest due job to a worker and counts the attempt.
+// Jobs whose worker died mid-delivery become claimable again once their lease runs out.
+func (or *OutboxRepository) ClaimNext(workerID string, lease time.Duration) (*models.NotificationJob, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	lockedUntil := now.Add(lease)
+	filter := bson.M{"$or": bson.A{
+		bson.M{"status": models.JobStatusPending, "next_attempt_at": bson.M{"$lte": now}},
+		bson.M{"status": models.JobStatusProcessing, "locked_until": bson.M{"$lt": now}},
+	}}
+	update := bson.M{
+		"$set": bson.M{
+			"status":       models.JobStatusProcessing,
+			"locked_by":    workerID,
+			"locked_until": lockedUntil,
+			"updated_at":   now,
+		},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.M{"next_attempt_at": 1}).
+		SetReturnDocument(options.After)
+
+	var job models.NotificationJob
+	err := or.db.Collection("notification_jobs").FindOneAndUpdate(ctx, filter, update, opts).Decode(&job)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// MarkSent records a successful delivery
+func (or *OutboxRepository) MarkSent(job *models.NotificationJob) error {
+	now := time.Now()
+	return or.release(job, bson.M{
+		"status":     models.JobStatusSent,
+		"sent_at":    now,
+		"last_error": "",
+	})
+}
+
+// MarkRetry puts a failed job back in the queue until nextAttemptAt
+func (or *OutboxRepository) MarkRetry(job *models.NotificationJob, nextAttemptAt time.Time, lastError string) error {
+	return or.release(job, bson.M{
+		"status":          models.JobStatusPending,
+		"next_attempt_at": nextAttemptAt,
+		"last_error":      lastError,
+	})
+}
+
+// MarkDead moves a job to the dead-letter state; it is not tried again unless requeued
+func (or *OutboxRepository) MarkDead(job *models.NotificationJob, lastError string) error {
+	return or.release(job, bson.M{
+		"status":     models.JobStatusDead,
+		"last_error": lastError,
+	})
+}
+
+// release applies the outcome of a delivery, but only while the worker still holds the lease
+func (or *OutboxRepository) release(job *models.NotificationJob, set bson.M) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	set["updated_at"] = time.Now()
+	result, err := or.db.Collection("notification_jobs").UpdateOne(ctx,
+		bson.M{"_id": job.ID, "locked_by": job.LockedBy, "status": models.JobStatusProcessing},
+		bson.M{"$set": set, "$unset": bson.M{"locked_by": "", "locked_until": ""}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("job lease was lost")
+	}
+	return nil
+}
+
+// ListJobs returns the newest jobs in a status, or of every status when it is empty
+func (or *OutboxRepository) ListJobs(status string, limit int64) ([]models.NotificationJob, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+	opts := options.Find().SetSort(bson.M{"created_at": -1}).SetLimit(limit)
+	cursor, err := or.db.Collection("notification_jobs").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	jobs := []models.NotificationJob{}
+	if err := cursor.All(ctx, &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// RequeueDeadJob gives a dead-lettered job a fresh set of attempts
+func (or *OutboxRepository) RequeueDeadJob(jobID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := or.db.Collection("notification_jobs").UpdateOne(ctx,
+		bson.M{"_id": jobID, "status": models.JobStatusDead},
+		bson.M{"$set": bson.M{
+			"status":          models.JobStatusPending,
+			"attempts":        0,
+			"next_attempt_at": time.Now(),
+			"updated_at":      time.Now(),
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("dead job not found")
+	}
+	return nil
+}
+
+// isTransactionUnsupported reports whether the server is a standalone mongod without transactions
+func isTransactionUnsupported(err error) bool {
+	var cmdErr mongo.CommandError
+	return errors.As(err, &cmdErr) && cmdErr.Code == 20 // IllegalOperation
+}
@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PhoneOTPRepository handles database operations for phone login codes
+type PhoneOTPRepository struct {
+	db *mongo.Database
+}
+
+// NewPhoneOTPRepository creates a new PhoneOTPRepository instance
+func NewPhoneOTPRepository(db *mongo.Database) *PhoneOTPRepository {
+	return &PhoneOTPRepository{db: db}
+}
+
+// ReplaceOTP stores a new code for a phone number, discarding any earlier one
+func (pr *PhoneOTPRepository) ReplaceOTP(otp *models.PhoneOTP) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection := pr.db.Collection("phone_otps")
+	if _, err := collection.DeleteMany(ctx, bson.M{"phone": otp.Phone}); err != nil {
+		return err
+	}
+	_, err := collection.InsertOne(ctx, otp)
+	return err
+}
+
+// ConsumeAttempt finds the live code for a phone number and counts one attempt against it
+func (pr *PhoneOTPRepository) ConsumeAttempt(phone string) (*models.PhoneOTP, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var otp models.PhoneOTP
+	err := pr.db.Collection("phone_otps").FindOneAndUpdate(ctx,
+		bson.M{
+			"phone":      phone,
+			"expires_at": bson.M{"$gt": time.Now()},
+			"attempts":   bson.M{"$lt": models.PhoneOTPMaxAttempts},
+		},
+		bson.M{"$inc": bson.M{"attempts": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&otp)
+	if err != nil {
+		return nil, errors.New("code is invalid or expired")
+	}
+	return &otp, nil
+}
+
+// DeleteOTP removes a code once it has been used
+func (pr *PhoneOTPRepository) DeleteOTP(id primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := pr.db.Collection("phone_otps").DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PreAuthTokenRepository handles database operations for pre-auth (2FA pending) tokens
+type PreAuthTokenRepository struct {
+	db *mongo.Database
+}
+
+// NewPreAuthTokenRepository creates a new PreAuthTokenRepository instance
+func NewPreAuthTokenRepository(db *mongo.Database) *PreAuthTokenRepository {
+	return &PreAuthTokenRepository{db: db}
+}
+
+// CreateToken inserts a new pre-auth token
+func (pr *PreAuthTokenRepository) CreateToken(token *models.PreAuthToken) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := pr.db.Collection("pre_auth_tokens").InsertOne(ctx, token)
+	return err
+}
+
+// ConsumeAttempt finds an unexpired token with attempts left and counts one attempt against it
+func (pr *PreAuthTokenRepository) ConsumeAttempt(tokenHash string) (*models.PreAuthToken, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var token models.PreAuthToken
+	err := pr.db.Collection("pre_auth_tokens").FindOneAndUpdate(ctx,
+		bson.M{
+			"token_hash": tokenHash,
+			"expires_at": bson.M{"$gt": time.Now()},
+			"attempts":   bson.M{"$lt": models.PreAuthMaxAttempts},
+		},
+		bson.M{"$inc": bson.M{"attempts": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&token)
+	if err != nil {
+		return nil, errors.New("pre-auth token is invalid or expired")
+	}
+	return &token, nil
+}
+
+// FindActiveToken gets an unexpired token without counting an attempt
+func (pr *PreAuthTokenRepository) FindActiveToken(tokenHash string) (*models.PreAuthToken, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var token models.PreAuthToken
+	err := pr.db.Collection("pre_auth_tokens").FindOne(ctx, bson.M{
+		"token_hash": tokenHash,
+		"expires_at": bson.M{"$gt": time.Now()},
+		"attempts":   bson.M{"$lt": models.PreAuthMaxAttempts},
+	}).Decode(&token)
+	if err != nil {
+		return nil, errors.New("pre-auth token is invalid or expired")
+	}
+	return &token, nil
+}
+
+// DeleteToken removes a token once it has been exchanged for a session
+func (pr *PreAuthTokenRepository) DeleteToken(id primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := pr.db.Collection("pre_auth_tokens").DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
@@ -58,8 +58,8 @@ func (rr *ReviewRepository) GetReviewsByCarwashID(carwashID primitive.ObjectID)
 
 	// Use aggregation pipeline to join with users collection
 	pipeline := bson.A{
-		// Match reviews for this carwash
-		bson.M{"$match": bson.M{"carwash_id": carwashID}},
+		// Match reviews for this carwash, leaving out ones hidden by moderators
+		bson.M{"$match": bson.M{"carwash_id": carwashID, "hidden": bson.M{"$ne": true}}},
 		// Lookup user details
 		bson.M{
 			"$lookup": bson.M{
@@ -107,6 +107,19 @@ func (rr *ReviewRepository) GetReviewsByCarwashID(carwashID primitive.ObjectID)
 	return reviews, nil
 }
 
+// GetReviewByID fetches a single review
+func (rr *ReviewRepository) GetReviewByID(reviewID primitive.ObjectID) (*models.Review, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var review models.Review
+	err := database.ReviewCollection.FindOne(ctx, bson.M{"_id": reviewID}).Decode(&review)
+	if err != nil {
+		return nil, errors.New("review not found")
+	}
+	return &review, nil
+}
+
 // 4. GetReviewByOrderID ensures one review per order
 func (rr *ReviewRepository) GetReviewByOrderID(orderID primitive.ObjectID) (*models.Review, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -126,7 +139,7 @@ func (rr *ReviewRepository) GetAverageRatingForCarwash(carwashID primitive.Objec
 	defer cancel()
 
 	pipeline := bson.A{
-		bson.M{"$match": bson.M{"carwash_id": carwashID}},
+		bson.M{"$match": bson.M{"carwash_id": carwashID, "hidden": bson.M{"$ne": true}}},
 		bson.M{"$group": bson.M{
 			"_id": "$carwash_id",
 			"avg": bson.M{"$avg": "$rating"},
@@ -192,3 +205,26 @@ func (rr *ReviewRepository) AddResponse(reviewID primitive.ObjectID, response st
 
 	return nil
 }
+
+// SetHidden hides or restores a review on the public carwash page
+func (rr *ReviewRepository) SetHidden(reviewID primitive.ObjectID, hidden bool, reason string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{"hidden": true, "hidden_reason": reason, "hidden_at": time.Now(), "updated_at": time.Now()}}
+	if !hidden {
+		update = bson.M{
+			"$set":   bson.M{"updated_at": time.Now()},
+			"$unset": bson.M{"hidden": "", "hidden_reason": "", "hidden_at": ""},
+		}
+	}
+
+	result, err := database.ReviewCollection.UpdateOne(ctx, bson.M{"_id": reviewID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("review not found")
+	}
+	return nil
+}
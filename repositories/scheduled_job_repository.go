@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ScheduledJobRepository stores jobs that run at a set time
+type ScheduledJobRepository struct {
+	db *mongo.Database
+}
+
+// NewScheduledJobRepository creates a new ScheduledJobRepository instance
+func NewScheduledJobRepository(db *mongo.Database) *ScheduledJobRepository {
+	return &ScheduledJobRepository{db: db}
+}
+
+// Schedule saves a job unless one with the same key exists. A cancelled job with the key is
+// brought back, so moving a booking away and back again restores its reminders;
+// one that already ran or is running is left alone.
+func (sr *ScheduledJobRepository) Schedule(job *models.ScheduledJob) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	job.SetDefaults()
+	_, err := sr.db.Collection("scheduled_jobs").UpdateOne(ctx,
+		bson.M{"key": job.Key, "status": bson.M{"$in": bson.A{models.ScheduledJobPending, models.ScheduledJobCancelled}}},
+		bson.M{
+			"$set": bson.M{
+				"status":     models.ScheduledJobPending,
+				"run_at":     job.RunAt,
+				"attempts":   0,
+				"updated_at": job.UpdatedAt,
+			},
+			"$setOnInsert": bson.M{
+				"_id":          job.ID,
+				"kind":         job.Kind,
+				"subject_id":   job.SubjectID,
+				"params":       job.Params,
+				"max_attempts": job.MaxAttempts,
+				"created_at":   job.CreatedAt,
+			},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		// the unique key matched a job that already ran or is running
+		return nil
+	}
+	return err
+}
+
+// CancelPending cancels the jobs of a kind about a subject that haven't started yet
+func (sr *ScheduledJobRepository) CancelPending(kind string, subjectID primitive.ObjectID) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := sr.db.Collection("scheduled_jobs").UpdateMany(ctx,
+		bson.M{"kind": kind, "subject_id": subjectID, "status": models.ScheduledJobPending},
+		bson.M{"$set": bson.M{"status": models.ScheduledJobCancelled, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// ClaimNext leases the earliest due job to a worker and counts the attempt.
+// Jobs whose worker died mid-run become claimable again once their lease runs out.
+func (sr *ScheduledJobRepository) ClaimNext(workerID string, lease time.Duration) (*models.ScheduledJob, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	filter := bson.M{"$or": bson.A{
+		bson.M{"status": models.ScheduledJobPending, "run_at": bson.M{"$lte": now}},
+		bson.M{"status": models.ScheduledJobProcessing, "locked_until": bson.M{"$lt": now}},
+	}}
+	update := bson.M{
+		"$set": bson.M{
+			"status":       models.ScheduledJobProcessing,
+			"locked_by":    workerID,
+			"locked_until": now.Add(lease),
+			"updated_at":   now,
+		},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.M{"run_at": 1}).
+		SetReturnDocument(options.After)
+
+	var job models.ScheduledJob
+	err := sr.db.Collection("scheduled_jobs").FindOneAndUpdate(ctx, filter, update, opts).Decode(&job)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// MarkDone records that a job ran
+func (sr *ScheduledJobRepository) MarkDone(job *models.ScheduledJob) error {
+	return sr.release(job, bson.M{
+		"status":       models.ScheduledJobDone,
+		"completed_at": time.Now(),
+		"last_error":   "",
+	})
+}
+
+// MarkRetry puts a failed job back to run again at runAt
+func (sr *ScheduledJobRepository) MarkRetry(job *models.ScheduledJob, runAt time.Time, lastError string) error {
+	return sr.release(job, bson.M{
+		"status":     models.ScheduledJobPending,
+		"run_at":     runAt,
+		"last_error": lastError,
+	})
+}
+
+// MarkFailed gives up on a job
+func (sr *ScheduledJobRepository) MarkFailed(job *models.ScheduledJob, lastError string) error {
+	return sr.release(job, bson.M{
+		"status":     models.ScheduledJobFailed,
+		"last_error": lastError,
+	})
+}
+
+// release applies the outcome of a run, but only while the worker still holds the lease
+func (sr *ScheduledJobRepository) release(job *models.ScheduledJob, set bson.M) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	set["updated_at"] = time.Now()
+	result, err := sr.db.Collection("scheduled_jobs").UpdateOne(ctx,
+		bson.M{"_id": job.ID, "locked_by": job.LockedBy, "status": models.ScheduledJobProcessing},
+		bson.M{"$set": set, "$unset": bson.M{"locked_by": "", "locked_until": ""}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("job lease was lost")
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// SessionRepository handles database operations for login sessions and refresh tokens
+type SessionRepository struct {
+	db *mongo.Database
+}
+
+// NewSessionRepository creates a new SessionRepository instance
+func NewSessionRepository(db *mongo.Database) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// CreateSession inserts a new session
+func (sr *SessionRepository) CreateSession(session *models.Session) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := sr.db.Collection("sessions").InsertOne(ctx, session)
+	return err
+}
+
+// FindSessionByID gets a session by its ID (the "sid" claim)
+func (sr *SessionRepository) FindSessionByID(sessionID primitive.ObjectID) (*models.Session, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var session models.Session
+	err := sr.db.Collection("sessions").FindOne(ctx, bson.M{"_id": sessionID}).Decode(&session)
+	if err != nil {
+		return nil, errors.New("session not found")
+	}
+	return &session, nil
+}
+
+// FindSessionByTokenHash gets the session whose current refresh token matches
+func (sr *SessionRepository) FindSessionByTokenHash(tokenHash string) (*models.Session, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var session models.Session
+	err := sr.db.Collection("sessions").FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&session)
+	if err != nil {
+		return nil, errors.New("session not found")
+	}
+	return &session, nil
+}
+
+// FindSessionByPreviousTokenHash gets the session a rotated-out refresh token belonged to
+func (sr *SessionRepository) FindSessionByPreviousTokenHash(tokenHash string) (*models.Session, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var session models.Session
+	err := sr.db.Collection("sessions").FindOne(ctx, bson.M{"previous_token_hashes": tokenHash}).Decode(&session)
+	if err != nil {
+		return nil, errors.New("session not found")
+	}
+	return &session, nil
+}
+
+// RotateToken swaps the refresh token of an active session.
+// Matching on the old hash means only one of two concurrent refreshes can win.
+func (sr *SessionRepository) RotateToken(sessionID primitive.ObjectID, oldHash, newHash string, expiresAt time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	filter := bson.M{
+		"_id":        sessionID,
+		"token_hash": oldHash,
+		"revoked_at": bson.M{"$exists": false},
+		"expires_at": bson.M{"$gt": now},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"token_hash":   newHash,
+			"expires_at":   expiresAt,
+			"last_used_at": now,
+		},
+		"$push": bson.M{"previous_token_hashes": oldHash},
+	}
+
+	result, err := sr.db.Collection("sessions").UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("session is no longer active")
+	}
+	return nil
+}
+
+// RevokeSession revokes a single session
+func (sr *SessionRepository) RevokeSession(sessionID primitive.ObjectID, reason string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := sr.db.Collection("sessions").UpdateOne(ctx,
+		bson.M{"_id": sessionID, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": time.Now(), "revoked_reason": reason}},
+	)
+	return err
+}
+
+// RevokeAllUserSessions revokes every active session of a user and returns how many were revoked
+func (sr *SessionRepository) RevokeAllUserSessions(userID primitive.ObjectID, reason string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := sr.db.Collection("sessions").UpdateMany(ctx,
+		bson.M{"user_id": userID, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": time.Now(), "revoked_reason": reason}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
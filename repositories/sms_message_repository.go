@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SMSMessageRepository stores the cost log of sent SMS
+type SMSMessageRepository struct {
+	db *mongo.Database
+}
+
+// NewSMSMessageRepository creates a new SMSMessageRepository instance
+func NewSMSMessageRepository(db *mongo.Database) *SMSMessageRepository {
+	return &SMSMessageRepository{db: db}
+}
+
+// Create records a message
+func (sr *SMSMessageRepository) Create(message *models.SMSMessage) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	message.ID = primitive.NewObjectID()
+	message.CreatedAt = time.Now()
+	_, err := sr.db.Collection("sms_messages").InsertOne(ctx, message)
+	return err
+}
+
+// SpentSince totals the cost and number of messages a carwash sent since the given time
+func (sr *SMSMessageRepository) SpentSince(carwashID primitive.ObjectID, since time.Time) (float64, int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"carwash_id": carwashID,
+			"status":     models.SMSStatusSent,
+			"created_at": bson.M{"$gte": since},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      nil,
+			"spent":    bson.M{"$sum": "$cost"},
+			"messages": bson.M{"$sum": 1},
+		}}},
+	}
+	cursor, err := sr.db.Collection("sms_messages").Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var totals []struct {
+		Spent    float64 `bson:"spent"`
+		Messages int64   `bson:"messages"`
+	}
+	if err := cursor.All(ctx, &totals); err != nil {
+		return 0, 0, err
+	}
+	if len(totals) == 0 {
+		return 0, 0, nil
+	}
+	return totals[0].Spent, totals[0].Messages, nil
+}
+
+// FindByCarwash returns a carwash's messages since the given time, newest first
+func (sr *SMSMessageRepository) FindByCarwash(carwashID primitive.ObjectID, since time.Time, limit int64) ([]models.SMSMessage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.M{"created_at": -1}).SetLimit(limit)
+	cursor, err := sr.db.Collection("sms_messages").Find(ctx,
+		bson.M{"carwash_id": carwashID, "created_at": bson.M{"$gte": since}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	messages := []models.SMSMessage{}
+	if err := cursor.All(ctx, &messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
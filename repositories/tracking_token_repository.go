@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TrackingTokenRepository handles database operations for booking tracking links
+type TrackingTokenRepository struct {
+	db *mongo.Database
+}
+
+// NewTrackingTokenRepository creates a new TrackingTokenRepository instance
+func NewTrackingTokenRepository(db *mongo.Database) *TrackingTokenRepository {
+	return &TrackingTokenRepository{db: db}
+}
+
+// CreateToken inserts a new tracking token
+func (tr *TrackingTokenRepository) CreateToken(token *models.TrackingToken) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := tr.db.Collection("tracking_tokens").InsertOne(ctx, token)
+	return err
+}
+
+// FindActiveToken gets an unexpired, unrevoked token by its hash
+func (tr *TrackingTokenRepository) FindActiveToken(tokenHash string) (*models.TrackingToken, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"token_hash": tokenHash,
+		"expires_at": bson.M{"$gt": time.Now()},
+		"revoked_at": bson.M{"$exists": false},
+	}
+
+	var token models.TrackingToken
+	err := tr.db.Collection("tracking_tokens").FindOne(ctx, filter).Decode(&token)
+	if err != nil {
+		return nil, errors.New("tracking link is invalid or expired")
+	}
+	return &token, nil
+}
+
+// RevokeTokensByBookingID revokes every active token for a booking, optionally limited to one scope
+func (tr *TrackingTokenRepository) RevokeTokensByBookingID(bookingID primitive.ObjectID, scope string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"booking_id": bookingID,
+		"revoked_at": bson.M{"$exists": false},
+	}
+	if scope != "" {
+		filter["scope"] = scope
+	}
+
+	_, err := tr.db.Collection("tracking_tokens").UpdateMany(ctx, filter,
+		bson.M{"$set": bson.M{"revoked_at": time.Now()}},
+	)
+	return err
+}
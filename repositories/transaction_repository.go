@@ -122,3 +122,40 @@ func GetPaymentByReference(reference string) (*models.Payment, error) {
 
 
 
+
+// SumPaidByCarwash totals paid payments per carwash, optionally within a paid_at range
+func SumPaidByCarwash(carwashIDs []primitive.ObjectID, from, to time.Time) (map[primitive.ObjectID]float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	match := bson.M{
+		"carwash_id": bson.M{"$in": carwashIDs},
+		"status":     "paid",
+	}
+	if !from.IsZero() && !to.IsZero() {
+		match["paid_at"] = bson.M{"$gte": from, "$lte": to}
+	}
+
+	cursor, err := database.PaymentCollection.Aggregate(ctx, []bson.M{
+		{"$match": match},
+		{"$group": bson.M{"_id": "$carwash_id", "total": bson.M{"$sum": "$amount"}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		CarwashID primitive.ObjectID `bson:"_id"`
+		Total     float64            `bson:"total"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	totals := make(map[primitive.ObjectID]float64, len(rows))
+	for _, row := range rows {
+		totals[row.CarwashID] = row.Total
+	}
+	return totals, nil
+}
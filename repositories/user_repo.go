@@ -13,6 +13,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type UserRepository struct {
@@ -38,6 +39,15 @@ func (ur *UserRepository) CreateUser(user models.User) error {
 
 }
 
+// DeleteUser removes a user document
+func (ur *UserRepository) DeleteUser(userID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := ur.db.Collection("users").DeleteOne(ctx, bson.M{"_id": userID})
+	return err
+}
+
 // FindUserByEmail searches for a user by their email
 func (ur *UserRepository) FindUserByEmail(email string) (*models.User, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -328,3 +338,141 @@ func (ur *UserRepository) FindUserByResetToken(token string) (*models.User, erro
 
 	return &user, nil
 }
+
+// ConsumeRecoveryCode removes a recovery code hash, reporting whether it was there.
+// The match and removal are one atomic update so a code can only be used once.
+func (ur *UserRepository) ConsumeRecoveryCode(userID primitive.ObjectID, codeHash string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := ur.db.Collection("users").UpdateOne(ctx,
+		bson.M{"_id": userID, "recovery_code_hashes": codeHash},
+		bson.M{"$pull": bson.M{"recovery_code_hashes": codeHash}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// AdvanceTwoFactorStep records the TOTP step just used, only if it is newer than the stored one
+func (ur *UserRepository) AdvanceTwoFactorStep(userID primitive.ObjectID, step int64) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := ur.db.Collection("users").UpdateOne(ctx,
+		bson.M{"_id": userID, "$or": []bson.M{
+			{"two_factor_last_step": bson.M{"$lt": step}},
+			{"two_factor_last_step": bson.M{"$exists": false}},
+		}},
+		bson.M{"$set": bson.M{"two_factor_last_step": step}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// ClearTwoFactor turns 2FA off and removes every secret and recovery code
+func (ur *UserRepository) ClearTwoFactor(userID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := ur.db.Collection("users").UpdateOne(ctx,
+		bson.M{"_id": userID},
+		bson.M{
+			"$unset": bson.M{
+				"two_factor_enabled":        "",
+				"two_factor_secret":         "",
+				"two_factor_pending_secret": "",
+				"two_factor_last_step":      "",
+				"recovery_code_hashes":      "",
+			},
+			"$set": bson.M{"updated_at": time.Now()},
+		},
+	)
+	return err
+}
+
+// FindUserByPhone searches for a user by their phone number (E.164)
+func (ur *UserRepository) FindUserByPhone(phone string) (*models.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var user models.User
+	err := ur.db.Collection("users").FindOne(ctx, bson.M{"phone": phone}).Decode(&user)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindUsersWithUnnormalizedPhone returns the ID and phone of users whose phone isn't stored in E.164 form
+func (ur *UserRepository) FindUsersWithUnnormalizedPhone() ([]models.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	filter := bson.M{"phone": bson.M{
+		"$type": "string",
+		"$ne":   "",
+		"$not":  bson.M{"$regex": `^\+[0-9]{8,15}$`},
+	}}
+	opts := options.Find().SetProjection(bson.M{"_id": 1, "phone": 1})
+	cursor, err := ur.db.Collection("users").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	users := []models.User{}
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// SearchUsers pages through users matching filter, newest first, and returns the total match count.
+// Password hashes and one-time codes are never loaded.
+func (ur *UserRepository) SearchUsers(filter bson.M, skip, limit int64) ([]models.User, int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	total, err := ur.db.Collection("users").CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.M{"created_at": -1}).
+		SetSkip(skip).
+		SetLimit(limit).
+		SetProjection(bson.M{"password": 0, "verification_code": 0, "password_reset_token": 0})
+	cursor, err := ur.db.Collection("users").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	users := []models.User{}
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// AssignOwnerBranch links an owner to their business. carwash_id is only set if the owner has none yet,
+// so adding a branch never replaces the owner's first one.
+func (ur *UserRepository) AssignOwnerBranch(userID, businessID, carwashID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := ur.db.Collection("users").UpdateOne(ctx,
+		bson.M{"_id": userID},
+		bson.A{bson.M{"$set": bson.M{
+			"business_id": businessID,
+			"carwash_id":  bson.M{"$ifNull": bson.A{"$carwash_id", carwashID}},
+			"updated_at":  time.Now(),
+		}}},
+	)
+	return err
+}
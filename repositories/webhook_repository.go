@@ -0,0 +1,207 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WebhookRepository stores carwashes' webhook endpoints and the log of deliveries made to them
+type WebhookRepository struct {
+	db *mongo.Database
+}
+
+// NewWebhookRepository creates a new WebhookRepository instance
+func NewWebhookRepository(db *mongo.Database) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// CreateEndpoint saves a new endpoint
+func (wr *WebhookRepository) CreateEndpoint(endpoint *models.WebhookEndpoint) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := wr.db.Collection("webhook_endpoints").InsertOne(ctx, endpoint)
+	return err
+}
+
+// FindEndpoint returns one of a carwash's endpoints
+func (wr *WebhookRepository) FindEndpoint(carwashID, endpointID primitive.ObjectID) (*models.WebhookEndpoint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var endpoint models.WebhookEndpoint
+	err := wr.db.Collection("webhook_endpoints").FindOne(ctx, bson.M{"_id": endpointID, "carwash_id": carwashID}).Decode(&endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+// FindEndpointByID returns an endpoint regardless of its carwash; used when delivering
+func (wr *WebhookRepository) FindEndpointByID(endpointID primitive.ObjectID) (*models.WebhookEndpoint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var endpoint models.WebhookEndpoint
+	if err := wr.db.Collection("webhook_endpoints").FindOne(ctx, bson.M{"_id": endpointID}).Decode(&endpoint); err != nil {
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+// FindEndpointsByCarwash returns a carwash's endpoints, oldest first
+func (wr *WebhookRepository) FindEndpointsByCarwash(carwashID primitive.ObjectID) ([]models.WebhookEndpoint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.M{"created_at": 1})
+	cursor, err := wr.db.Collection("webhook_endpoints").Find(ctx, bson.M{"carwash_id": carwashID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	endpoints := []models.WebhookEndpoint{}
+	if err := cursor.All(ctx, &endpoints); err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// FindSubscribed returns a carwash's active endpoints that subscribed to an event
+func (wr *WebhookRepository) FindSubscribed(carwashID primitive.ObjectID, event string) ([]models.WebhookEndpoint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := wr.db.Collection("webhook_endpoints").Find(ctx, bson.M{"carwash_id": carwashID, "active": true, "events": event})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var endpoints []models.WebhookEndpoint
+	if err := cursor.All(ctx, &endpoints); err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// UpdateEndpoint sets fields of one of a carwash's endpoints and returns it as updated
+func (wr *WebhookRepository) UpdateEndpoint(carwashID, endpointID primitive.ObjectID, updates bson.M) (*models.WebhookEndpoint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	updates["updated_at"] = time.Now()
+	var endpoint models.WebhookEndpoint
+	err := wr.db.Collection("webhook_endpoints").FindOneAndUpdate(ctx,
+		bson.M{"_id": endpointID, "carwash_id": carwashID},
+		bson.M{"$set": updates},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+// DeleteEndpoint removes one of a carwash's endpoints. Its delivery log is kept.
+func (wr *WebhookRepository) DeleteEndpoint(carwashID, endpointID primitive.ObjectID) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := wr.db.Collection("webhook_endpoints").DeleteOne(ctx, bson.M{"_id": endpointID, "carwash_id": carwashID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// CreateDelivery saves a delivery before it is sent
+func (wr *WebhookRepository) CreateDelivery(delivery *models.WebhookDelivery) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := wr.db.Collection("webhook_deliveries").InsertOne(ctx, delivery)
+	return err
+}
+
+// FindDeliveryByID returns a delivery regardless of its carwash; used when delivering
+func (wr *WebhookRepository) FindDeliveryByID(deliveryID primitive.ObjectID) (*models.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var delivery models.WebhookDelivery
+	if err := wr.db.Collection("webhook_deliveries").FindOne(ctx, bson.M{"_id": deliveryID}).Decode(&delivery); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// FindDelivery returns one of the deliveries made to a carwash's endpoint
+func (wr *WebhookRepository) FindDelivery(carwashID, endpointID, deliveryID primitive.ObjectID) (*models.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var delivery models.WebhookDelivery
+	err := wr.db.Collection("webhook_deliveries").FindOne(ctx,
+		bson.M{"_id": deliveryID, "endpoint_id": endpointID, "carwash_id": carwashID},
+	).Decode(&delivery)
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// FindDeliveries returns an endpoint's most recent deliveries, optionally only those with a status
+func (wr *WebhookRepository) FindDeliveries(carwashID, endpointID primitive.ObjectID, status string, limit int64) ([]models.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"endpoint_id": endpointID, "carwash_id": carwashID}
+	if status != "" {
+		filter["status"] = status
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(limit).
+		SetProjection(bson.M{"payload": 0})
+	cursor, err := wr.db.Collection("webhook_deliveries").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	deliveries := []models.WebhookDelivery{}
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// RecordAttempt stores the outcome of one attempt at a delivery
+func (wr *WebhookRepository) RecordAttempt(delivery *models.WebhookDelivery) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	delivery.UpdatedAt = time.Now()
+	_, err := wr.db.Collection("webhook_deliveries").UpdateOne(ctx,
+		bson.M{"_id": delivery.ID},
+		bson.M{"$set": bson.M{
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"response_status": delivery.ResponseStatus,
+			"response_body":   delivery.ResponseBody,
+			"last_error":      delivery.LastError,
+			"duration_ms":     delivery.DurationMs,
+			"delivered_at":    delivery.DeliveredAt,
+			"updated_at":      delivery.UpdatedAt,
+		}},
+	)
+	return err
+}
@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WorkerInviteRepository handles database operations for worker invites
+type WorkerInviteRepository struct {
+	db *mongo.Database
+}
+
+// NewWorkerInviteRepository creates a new WorkerInviteRepository instance
+func NewWorkerInviteRepository(db *mongo.Database) *WorkerInviteRepository {
+	return &WorkerInviteRepository{db: db}
+}
+
+// CreateInvite inserts a new invite
+func (ir *WorkerInviteRepository) CreateInvite(invite *models.WorkerInvite) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := ir.db.Collection("worker_invites").InsertOne(ctx, invite)
+	return err
+}
+
+// FindInviteByID gets an invite by its ID
+func (ir *WorkerInviteRepository) FindInviteByID(inviteID primitive.ObjectID) (*models.WorkerInvite, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var invite models.WorkerInvite
+	err := ir.db.Collection("worker_invites").FindOne(ctx, bson.M{"_id": inviteID}).Decode(&invite)
+	if err != nil {
+		return nil, errors.New("invite not found")
+	}
+	return &invite, nil
+}
+
+// FindPendingInvitesByCarwashID lists invites that have not been accepted, revoked or expired
+func (ir *WorkerInviteRepository) FindPendingInvitesByCarwashID(carwashID primitive.ObjectID) ([]models.WorkerInvite, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"carwash_id": carwashID,
+		"status":     models.InviteStatusPending,
+		"expires_at": bson.M{"$gt": time.Now()},
+	}
+	opts := options.Find().SetSort(bson.D{primitive.E{Key: "created_at", Value: -1}})
+
+	cursor, err := ir.db.Collection("worker_invites").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	invites := []models.WorkerInvite{}
+	if err := cursor.All(ctx, &invites); err != nil {
+		return nil, err
+	}
+	return invites, nil
+}
+
+// ConsumeInvite atomically marks a pending, unexpired invite as accepted.
+// Matching on status makes the token single-use even under concurrent requests.
+func (ir *WorkerInviteRepository) ConsumeInvite(tokenHash string) (*models.WorkerInvite, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	filter := bson.M{
+		"token_hash": tokenHash,
+		"status":     models.InviteStatusPending,
+		"expires_at": bson.M{"$gt": now},
+	}
+	update := bson.M{"$set": bson.M{
+		"status":      models.InviteStatusAccepted,
+		"accepted_at": now,
+		"updated_at":  now,
+	}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var invite models.WorkerInvite
+	err := ir.db.Collection("worker_invites").FindOneAndUpdate(ctx, filter, update, opts).Decode(&invite)
+	if err != nil {
+		return nil, errors.New("invite is invalid, expired or already used")
+	}
+	return &invite, nil
+}
+
+// RevokeInvite marks a pending invite as revoked
+func (ir *WorkerInviteRepository) RevokeInvite(inviteID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	result, err := ir.db.Collection("worker_invites").UpdateOne(ctx,
+		bson.M{"_id": inviteID, "status": models.InviteStatusPending},
+		bson.M{"$set": bson.M{
+			"status":     models.InviteStatusRevoked,
+			"revoked_at": now,
+			"updated_at": now,
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("invite is not pending")
+	}
+	return nil
+}
+
+// ReissueInvite replaces the token of a pending invite and extends its expiry
+func (ir *WorkerInviteRepository) ReissueInvite(inviteID primitive.ObjectID, tokenHash string, expiresAt time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := ir.db.Collection("worker_invites").UpdateOne(ctx,
+		bson.M{"_id": inviteID, "status": models.InviteStatusPending},
+		bson.M{"$set": bson.M{
+			"token_hash": tokenHash,
+			"expires_at": expiresAt,
+			"updated_at": time.Now(),
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("invite is not pending")
+	}
+	return nil
+}
@@ -51,6 +51,34 @@ func (wr *WorkerRepository) FindWorkersByCarwashID(carwashID primitive.ObjectID)
 	return workers, nil
 }
 
+// FindWorkersByCarwashIDs gets the workers of several branches
+func (wr *WorkerRepository) FindWorkersByCarwashIDs(carwashIDs []primitive.ObjectID) ([]*models.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"role":       "worker",
+		"carwash_id": bson.M{"$in": carwashIDs},
+	}
+
+	cursor, err := wr.db.Collection("users").Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	workers := []*models.User{}
+	for cursor.Next(ctx) {
+		var user models.User
+		if err := cursor.Decode(&user); err != nil {
+			return nil, err
+		}
+		user.Password = ""
+		workers = append(workers, &user)
+	}
+	return workers, nil
+}
+
 // FindWorkerByID gets a specific worker by ID
 func (wr *WorkerRepository) FindWorkerByID(workerID primitive.ObjectID) (*models.User, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -1,17 +1,100 @@
 package routes
 
 import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/gorilla/mux"
 	"github.com/olabanji12-ojo/CarWashApp/controllers"
+	"github.com/olabanji12-ojo/CarWashApp/middleware"
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/policy"
 	"github.com/olabanji12-ojo/CarWashApp/repositories"
 	"github.com/olabanji12-ojo/CarWashApp/services"
+	"github.com/olabanji12-ojo/CarWashApp/services/email"
+	"github.com/olabanji12-ojo/CarWashApp/services/events"
 	"github.com/olabanji12-ojo/CarWashApp/services/geocoding"
+	"github.com/olabanji12-ojo/CarWashApp/services/push"
+	"github.com/olabanji12-ojo/CarWashApp/services/routing"
+	"github.com/olabanji12-ojo/CarWashApp/services/sms"
+	"github.com/olabanji12-ojo/CarWashApp/services/tokens"
+	"github.com/olabanji12-ojo/CarWashApp/services/tracking"
+	"github.com/olabanji12-ojo/CarWashApp/utils"
+	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func InitAuthService(db *mongo.Database) *controllers.AuthController {
-	authService := services.NewAuthService(*repositories.NewUserRepository(db))
-	return &controllers.AuthController{AuthService: authService}
+// InitPolicy builds the shared authorization policy used by routes and services
+func InitPolicy(db *mongo.Database) *policy.Policy {
+	return policy.NewPolicy(
+		repositories.NewUserRepository(db),
+		repositories.NewCarWashRepository(db),
+		repositories.NewBookingRepository(db),
+		repositories.NewOrderRepository(db),
+		repositories.NewReviewRepository(db),
+		repositories.NewCarRepository(db),
+		repositories.NewBusinessRepository(db),
+		repositories.NewNotificationRepository(db),
+	)
+}
+
+// InitSessionService builds the session service shared by login flows and AuthMiddleware
+func InitSessionService(db *mongo.Database, issuer *tokens.Issuer) *services.SessionService {
+	return services.NewSessionService(repositories.NewSessionRepository(db), repositories.NewUserRepository(db), issuer)
+}
+
+// InitTwoFactorService builds the 2FA service shared by the login flows
+func InitTwoFactorService(db *mongo.Database, throttleService *services.AuthThrottleService) *services.TwoFactorService {
+	return services.NewTwoFactorService(
+		repositories.NewUserRepository(db),
+		repositories.NewCarWashRepository(db),
+		repositories.NewPreAuthTokenRepository(db),
+		throttleService,
+	)
+}
+
+// InitAuthThrottleService builds the login/rate-limit tracker shared by the auth flows
+func InitAuthThrottleService(db *mongo.Database) *services.AuthThrottleService {
+	return services.NewAuthThrottleService(repositories.NewAuthThrottleRepository(db), repositories.NewAuditRepository(db))
+}
+
+// InitSMSSender picks the SMS provider: the HTTP gateway at SMS_GATEWAY_URL when set,
+// otherwise messages are only logged
+func InitSMSSender() sms.SMSSender {
+	endpoint := os.Getenv("SMS_GATEWAY_URL")
+	if endpoint == "" {
+		return sms.NewConsoleSender()
+	}
+	logrus.Println("✅ SMS gateway sender initialized")
+	return sms.NewHTTPSender(endpoint, os.Getenv("SMS_API_KEY"), os.Getenv("SMS_SENDER_ID"))
+}
+
+// InitSMSService builds the SMS service shared by login codes and notifications.
+// SMS_COST_PER_SEGMENT prices messages the gateway doesn't report a cost for;
+// SMS_MONTHLY_BUDGET is the budget of carwashes that haven't set their own.
+func InitSMSService(db *mongo.Database) *services.SMSService {
+	costPerSegment, err := strconv.ParseFloat(os.Getenv("SMS_COST_PER_SEGMENT"), 64)
+	if err != nil || costPerSegment < 0 {
+		costPerSegment = 4
+	}
+	budget, err := strconv.ParseFloat(os.Getenv("SMS_MONTHLY_BUDGET"), 64)
+	if err != nil || budget < 0 {
+		budget = 2000
+	}
+	return services.NewSMSService(InitSMSSender(), repositories.NewSMSMessageRepository(db), repositories.NewCarWashRepository(db), costPerSegment, budget)
+}
+
+func InitAuthService(db *mongo.Database, sessionService *services.SessionService, twoFactorService *services.TwoFactorService, throttleService *services.AuthThrottleService) *controllers.AuthController {
+	authService := services.NewAuthService(*repositories.NewUserRepository(db), throttleService)
+	return controllers.NewAuthController(authService, sessionService, twoFactorService)
+}
+
+func InitPhoneAuthService(db *mongo.Database, smsService *services.SMSService, sessionService *services.SessionService, twoFactorService *services.TwoFactorService, throttleService *services.AuthThrottleService) *controllers.PhoneAuthController {
+	phoneLoginService := services.NewPhoneLoginService(repositories.NewUserRepository(db), repositories.NewPhoneOTPRepository(db), smsService, throttleService)
+	return controllers.NewPhoneAuthController(phoneLoginService, sessionService, twoFactorService)
 }
 
 func InitUserService(db *mongo.Database) *controllers.UserController {
@@ -20,12 +103,13 @@ func InitUserService(db *mongo.Database) *controllers.UserController {
 	return controllers.NewUserController(userService)
 }
 
-func InitWorkerService(db *mongo.Database) *controllers.WorkerController {
+func InitWorkerService(db *mongo.Database, authz *policy.Policy, sessionService *services.SessionService, twoFactorService *services.TwoFactorService, smsService *services.SMSService) *controllers.WorkerController {
 	userRepo := repositories.NewUserRepository(db)
 	workerRepo := repositories.NewWorkerRepository(db)
-	workerService := services.NewWorkerService(userRepo, workerRepo)
+	inviteRepo := repositories.NewWorkerInviteRepository(db)
+	workerService := services.NewWorkerService(userRepo, workerRepo, inviteRepo, smsService, authz)
 	userService := services.NewUserService(userRepo)
-	return controllers.NewWorkerController(workerService, userService)
+	return controllers.NewWorkerController(workerService, userService, sessionService, twoFactorService)
 }
 
 func InitCarService(db *mongo.Database) *controllers.CarController {
@@ -33,7 +117,19 @@ func InitCarService(db *mongo.Database) *controllers.CarController {
 	return &controllers.CarController{CarService: carService}
 }
 
-func InitCarWashService(db *mongo.Database, geocoder geocoding.Geocoder) *controllers.CarWashController {
+// InitBusinessService builds the service that groups carwash branches under a business
+func InitBusinessService(db *mongo.Database, authz *policy.Policy) *services.BusinessService {
+	return services.NewBusinessService(
+		repositories.NewBusinessRepository(db),
+		repositories.NewCarWashRepository(db),
+		repositories.NewBookingRepository(db),
+		repositories.NewWorkerRepository(db),
+		repositories.NewUserRepository(db),
+		authz,
+	)
+}
+
+func InitCarWashService(db *mongo.Database, geocoder geocoding.Geocoder, businessService *services.BusinessService, smsService *services.SMSService) *controllers.CarWashController {
 	carwashRepo := repositories.NewCarWashRepository(db)
 	bookingRepo := repositories.NewBookingRepository(db)
 	carwashService := services.NewCarWashService(*carwashRepo, *bookingRepo, geocoder)
@@ -42,18 +138,176 @@ func InitCarWashService(db *mongo.Database, geocoder geocoding.Geocoder) *contro
 	userRepo := repositories.NewUserRepository(db)
 	userService := services.NewUserService(userRepo)
 
-	return controllers.NewCarWashController(carwashService, userService)
+	return controllers.NewCarWashController(carwashService, userService, businessService, smsService)
+}
+
+// InitEmailSender picks the email provider: the HTTP API at EMAIL_API_URL when set (for hosts that
+// block SMTP ports), otherwise SMTP when SMTP_USERNAME, SMTP_PASSWORD and FROM_EMAIL are set,
+// otherwise emails are only logged
+func InitEmailSender() email.EmailSender {
+	from := email.From{Email: os.Getenv("FROM_EMAIL"), Name: os.Getenv("FROM_NAME")}
+	if from.Name == "" {
+		from.Name = "CarWash App"
+	}
+
+	if endpoint := os.Getenv("EMAIL_API_URL"); endpoint != "" {
+		logrus.Println("✅ Email API sender initialized")
+		return email.NewHTTPSender(endpoint, os.Getenv("EMAIL_API_KEY"), from)
+	}
+
+	config := email.SMTPConfig{
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     os.Getenv("SMTP_PORT"),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     from,
+	}
+	if config.Username == "" || config.Password == "" || from.Email == "" {
+		return email.NewConsoleSender()
+	}
+	if config.Host == "" {
+		config.Host = "smtp.gmail.com"
+	}
+	if config.Port == "" {
+		config.Port = "587"
+	}
+	logrus.Println("✅ SMTP email sender initialized")
+	return email.NewSMTPSender(config)
 }
 
-func InitBookingService(db *mongo.Database, geocoder geocoding.Geocoder) *controllers.BookingController {
+// InitEmailService builds the service every email goes through; it skips addresses that bounced
+// or complained. EMAIL_WEBHOOK_SECRET authenticates the provider's bounce/complaint webhook.
+func InitEmailService(db *mongo.Database) *services.EmailService {
+	secret := os.Getenv("EMAIL_WEBHOOK_SECRET")
+	if secret == "" {
+		logrus.Warn("⚠️ EMAIL_WEBHOOK_SECRET is not set; bounce and complaint reports will be refused")
+	}
+	return services.NewEmailService(InitEmailSender(), repositories.NewEmailSuppressionRepository(db), secret)
+}
+
+// InitNotificationService builds the notification service. It follows each user's preferences
+// and sends emails, push and SMS through the outbox.
+func InitNotificationService(db *mongo.Database, emailService *services.EmailService, pushSender push.PushSender, smsService *services.SMSService) *services.NotificationService {
+	return services.NewNotificationService(
+		repositories.NewUserRepository(db),
+		repositories.NewNotificationRepository(db),
+		repositories.NewOutboxRepository(db),
+		repositories.NewNotificationPreferenceRepository(db),
+		repositories.NewDeviceTokenRepository(db),
+		emailService,
+		pushSender,
+		smsService,
+	)
+}
+
+// InitPushSender picks the push provider: FCM when FCM_PROJECT_ID and FCM_CREDENTIALS_FILE
+// are set, otherwise notifications are only logged
+func InitPushSender() push.PushSender {
+	projectID := os.Getenv("FCM_PROJECT_ID")
+	credentialsFile := os.Getenv("FCM_CREDENTIALS_FILE")
+	if projectID == "" || credentialsFile == "" {
+		return push.NewFakeSender()
+	}
+
+	credentials, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		logrus.Fatal("❌ Failed to read FCM credentials: ", err)
+	}
+	sender, err := push.NewFCMSender(context.Background(), projectID, credentials)
+	if err != nil {
+		logrus.Fatal("❌ Failed to initialise FCM: ", err)
+	}
+	if endpoint := os.Getenv("FCM_ENDPOINT"); endpoint != "" {
+		// FCM-compatible gateway; reuses the authenticated client
+		sender = push.NewFCMSenderWithClient(endpoint, sender.Client())
+	}
+	logrus.Println("✅ FCM push sender initialized")
+	return sender
+}
+
+// InitOutboxWorker builds the pool that delivers queued notifications
+func InitOutboxWorker(db *mongo.Database, notificationService *services.NotificationService) *services.OutboxWorker {
+	concurrency, _ := strconv.Atoi(os.Getenv("OUTBOX_WORKERS"))
+	if concurrency < 1 {
+		concurrency = 4
+	}
+	worker := services.NewOutboxWorker(repositories.NewOutboxRepository(db), concurrency)
+	worker.Handle(models.ChannelEmail, notificationService.DeliverEmail)
+	worker.Handle(models.ChannelPush, notificationService.DeliverPush)
+	worker.Handle(models.ChannelSMS, notificationService.DeliverSMS)
+	return worker
+}
+
+// InitBookingReminderService builds the booking reminder service. BOOKING_REMINDER_OFFSETS
+// lists how long before a booking to remind its customer, e.g. "24h,1h" (the default).
+func InitBookingReminderService(db *mongo.Database, notificationService *services.NotificationService) *services.BookingReminderService {
+	offsets := services.DefaultReminderOffsets
+	if raw := os.Getenv("BOOKING_REMINDER_OFFSETS"); raw != "" {
+		offsets = nil
+		for _, part := range strings.Split(raw, ",") {
+			offset, err := time.ParseDuration(strings.TrimSpace(part))
+			if err != nil || offset <= 0 {
+				logrus.Fatalf("❌ Invalid BOOKING_REMINDER_OFFSETS entry %q", part)
+			}
+			offsets = append(offsets, offset)
+		}
+	}
+	return services.NewBookingReminderService(
+		repositories.NewScheduledJobRepository(db),
+		repositories.NewBookingRepository(db),
+		repositories.NewCarWashRepository(db),
+		notificationService,
+		offsets,
+	)
+}
+
+// InitWebhookService builds the service that sends carwashes' events to their webhooks.
+// WEBHOOK_ALLOW_PRIVATE_URLS=true accepts http and private-network URLs, for local development only.
+func InitWebhookService(db *mongo.Database) *services.WebhookService {
+	allowPrivate, _ := strconv.ParseBool(os.Getenv("WEBHOOK_ALLOW_PRIVATE_URLS"))
+	if allowPrivate {
+		logrus.Warn("⚠️ Webhooks may be sent to private network addresses")
+	}
+	return services.NewWebhookService(repositories.NewWebhookRepository(db), repositories.NewScheduledJobRepository(db), allowPrivate)
+}
+
+// InitScheduler builds the pool that runs scheduled jobs such as booking reminders and webhook deliveries
+func InitScheduler(db *mongo.Database, reminderService *services.BookingReminderService, webhookService *services.WebhookService) *services.Scheduler {
+	concurrency, _ := strconv.Atoi(os.Getenv("SCHEDULER_WORKERS"))
+	if concurrency < 1 {
+		concurrency = 2
+	}
+	scheduler := services.NewScheduler(repositories.NewScheduledJobRepository(db), concurrency)
+	scheduler.Handle(models.JobKindBookingReminder, reminderService.HandleReminder)
+	scheduler.Handle(models.JobKindWebhookDelivery, webhookService.HandleDelivery)
+	return scheduler
+}
+
+// InitEventBus builds the in-process domain event bus and subscribes what reacts to
+// bookings, orders, payments and reviews
+func InitEventBus(notificationService *services.NotificationService, reminderService *services.BookingReminderService, webhookService *services.WebhookService) events.Bus {
+	bus := events.NewLocalBus()
+	notificationService.Subscribe(bus)
+	reminderService.Subscribe(bus)
+	webhookService.Subscribe(bus)
+	return bus
+}
+
+func InitBookingService(db *mongo.Database, geocoder geocoding.Geocoder, tracker tracking.Broker, authz *policy.Policy, notificationService *services.NotificationService, bus events.Bus) *controllers.BookingController {
 	userRepo := repositories.NewUserRepository(db)
-	notificationService := services.NewNotificationService(userRepo)
 
 	bookingService := services.NewBookingService(
 		*repositories.NewBookingRepository(db),
 		*repositories.NewCarWashRepository(db),
 		*userRepo,
+		*repositories.NewCarRepository(db),
 		notificationService,
+		tracker,
+		routing.NewStraightLineRouter(),
+		*repositories.NewLocationHistoryRepository(db),
+		*repositories.NewTrackingTokenRepository(db),
+		authz,
+		bus,
 	)
 
 	// We also need CarWashService for GetAvailableSlots
@@ -64,54 +318,134 @@ func InitBookingService(db *mongo.Database, geocoder geocoding.Geocoder) *contro
 	return controllers.NewBookingController(bookingService, carwashService)
 }
 
-func InitOrderService(db *mongo.Database) *controllers.OrderController {
-	orderService := services.NewOrderService(*repositories.NewOrderRepository(db))
+func InitOrderService(db *mongo.Database, bus events.Bus) *controllers.OrderController {
+	orderService := services.NewOrderService(*repositories.NewOrderRepository(db), bus)
 	return &controllers.OrderController{OrderService: orderService}
 }
 
-func InitReviewService(db *mongo.Database) *controllers.ReviewController {
-	reviewService := services.NewReviewService(*repositories.NewReviewRepository(db))
+func InitReviewService(db *mongo.Database, bus events.Bus) *controllers.ReviewController {
+	reviewService := services.NewReviewService(*repositories.NewReviewRepository(db), bus)
 	return controllers.NewReviewController(reviewService)
 }
 
-func InitRoutes(router *mux.Router, db *mongo.Database, geocoder geocoding.Geocoder) {
-	AuthRoutes(router, InitAuthService(db))
+// InitAdminService builds the moderation service. It also audits requests made under impersonation.
+func InitAdminService(db *mongo.Database, sessionService *services.SessionService) *services.AdminService {
+	return services.NewAdminService(
+		repositories.NewUserRepository(db),
+		repositories.NewCarWashRepository(db),
+		repositories.NewReviewRepository(db),
+		repositories.NewAuditRepository(db),
+		repositories.NewOutboxRepository(db),
+		sessionService,
+	)
+}
+
+// InitRoutes wires every route. It returns the notification outbox worker and the job scheduler,
+// which the caller starts and drains on shutdown.
+func InitRoutes(router *mux.Router, db *mongo.Database, geocoder geocoding.Geocoder, issuer *tokens.Issuer) (*services.OutboxWorker, *services.Scheduler) {
+	// AuthMiddleware verifies signatures with the issuer's key set and rejects
+	// revoked sessions and suspended users through the session service
+	middleware.SetTokenVerifier(issuer)
+	sessionService := InitSessionService(db, issuer)
+	middleware.SetSessionValidator(sessionService)
+
+	WellKnownRoutes(router, controllers.NewJWKSController(issuer.Keys()))
+
+	// Every email, including login and invite emails, goes through the email service
+	emailService := InitEmailService(db)
+	utils.SetEmailSender(emailService)
+	EmailRoutes(router, controllers.NewEmailController(emailService))
+
+	throttleService := InitAuthThrottleService(db)
+	twoFactorService := InitTwoFactorService(db, throttleService)
+	smsService := InitSMSService(db)
+	AuthRoutes(router, InitAuthService(db, sessionService, twoFactorService, throttleService))
+	PhoneAuthRoutes(router, InitPhoneAuthService(db, smsService, sessionService, twoFactorService, throttleService))
+	TwoFactorRoutes(router, controllers.NewTwoFactorController(twoFactorService, sessionService))
+
+	// Every protected route checks ownership/role through the same policy
+	authz := InitPolicy(db)
+
+	// Notifications are saved together with their pending deliveries; the outbox worker sends them
+	notificationService := InitNotificationService(db, emailService, InitPushSender(), smsService)
+	outboxWorker := InitOutboxWorker(db, notificationService)
+
+	// Reminders and webhook deliveries are scheduled jobs
+	reminderService := InitBookingReminderService(db, notificationService)
+	webhookService := InitWebhookService(db)
+	scheduler := InitScheduler(db, reminderService, webhookService)
+
+	// Services publish domain events; notifications, reminders and webhooks subscribe to them
+	bus := InitEventBus(notificationService, reminderService, webhookService)
+
+	// One-off backfill of phone numbers saved before they were normalized; a no-op once done
+	go func() {
+		if _, err := services.NewUserService(repositories.NewUserRepository(db)).NormalizeStoredPhones(); err != nil {
+			logrus.Errorf("Failed to normalize stored phone numbers: %v", err)
+		}
+	}()
 
 	// Initialize UserRouter and set up user routes
 	userController := InitUserService(db)
-	userRouter := NewUserRouter(userController)
+	userRouter := NewUserRouter(userController, authz)
 	userRouter.UserRoutes(router)
 
 	// Initialize CarRouter and set up car routes
 	carController := InitCarService(db)
-	carRouter := NewCarRouter(*carController)
+	carRouter := NewCarRouter(*carController, authz)
 	carRouter.CarRoutes(router)
 
 	// Initialize CarWashRouter and set up car wash routes (now with geocoder)
-	carwashController := InitCarWashService(db, geocoder)
-	carwashRouter := NewCarWashRouter(*carwashController)
+	businessService := InitBusinessService(db, authz)
+
+	// One-off backfill of the branch lists of businesses created before they kept one; a no-op once done
+	go func() {
+		if _, err := businessService.BackfillBranchLists(); err != nil {
+			logrus.Errorf("Failed to backfill business branch lists: %v", err)
+		}
+	}()
+	carwashController := InitCarWashService(db, geocoder, businessService, smsService)
+	carwashRouter := NewCarWashRouter(*carwashController, authz)
 	carwashRouter.CarwashRoutes(router)
 
 	// Initialize BookingRouter and set up booking routes
-	bookingController := InitBookingService(db, geocoder)
-	bookingRouter := NewBookingRouter(*bookingController)
+	// One tracking broker per process fans live location/status updates out to SSE clients
+	tracker := tracking.NewLocalBroker()
+	bookingController := InitBookingService(db, geocoder, tracker, authz, notificationService, bus)
+	bookingRouter := NewBookingRouter(*bookingController, authz)
 	bookingRouter.BookingRoutes(router)
 
 	// Initialize OrderRouter and set up order routes
-	orderController := InitOrderService(db)
-	OrderRouter := NewOrderRouter(orderController)
+	orderController := InitOrderService(db, bus)
+	OrderRouter := NewOrderRouter(orderController, authz)
 	OrderRouter.OrderRoutes(router)
 
 	// Initialize ReviewRouter and set up review routes
-	reviewController := InitReviewService(db)
-	ReviewRouter := NewReviewRouter(*reviewController)
+	reviewController := InitReviewService(db, bus)
+	ReviewRouter := NewReviewRouter(*reviewController, authz)
 	ReviewRouter.ReviewRoutes(router)
 
 	// Initialize WorkerRouter and set up worker routes
-	workerController := InitWorkerService(db)
-	workerRouter := NewWorkerRouter(workerController)
+	workerController := InitWorkerService(db, authz, sessionService, twoFactorService, smsService)
+	workerRouter := NewWorkerRouter(workerController, authz)
 	workerRouter.WorkerRoutes(router)
 
-	PaymentRoutes(router)
-	NotificationRoutes(router) // Notification system
+	// Push tokens of the caller's phones and browsers
+	DeviceRoutes(router, controllers.NewDeviceController(services.NewDeviceService(repositories.NewDeviceTokenRepository(db))))
+
+	// Businesses group an owner's carwash branches
+	BusinessRoutes(router, controllers.NewBusinessController(businessService), authz)
+
+	// Platform admin moderation; requests made while impersonating a user land in the audit log
+	adminService := InitAdminService(db, sessionService)
+	middleware.SetImpersonationAuditor(adminService)
+	AdminRoutes(router, controllers.NewAdminController(adminService), authz)
+
+	// Carwashes' own systems receive their events through webhooks
+	WebhookRoutes(router, controllers.NewWebhookController(webhookService), authz)
+
+	PaymentRoutes(router, controllers.NewPaymentController(services.NewPaymentService(repositories.NewOrderRepository(db), bus)), authz)
+	NotificationRoutes(router, controllers.NewNotificationController(notificationService), authz) // Notification system
+
+	return outboxWorker, scheduler
 }
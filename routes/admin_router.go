@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/olabanji12-ojo/CarWashApp/controllers"
+	"github.com/olabanji12-ojo/CarWashApp/middleware"
+	"github.com/olabanji12-ojo/CarWashApp/policy"
+	"github.com/olabanji12-ojo/CarWashApp/utils"
+)
+
+// AdminRoutes sets up the platform moderation API. Every route is limited to platform admins.
+func AdminRoutes(router *mux.Router, adminController *controllers.AdminController, authz *policy.Policy) {
+	admin := router.PathPrefix("/api/admin").Subrouter()
+	admin.Use(middleware.AuthMiddleware)
+	admin.Use(authz.Require("", policy.HasRole(utils.ROLE_ADMIN)))
+
+	// Users
+	admin.HandleFunc("/users", adminController.ListUsers).Methods("GET")
+	admin.HandleFunc("/users/{id}", adminController.GetUser).Methods("GET")
+	admin.HandleFunc("/users/{id}/status", adminController.SetUserStatus).Methods("PATCH")
+	admin.HandleFunc("/users/{id}/impersonate", adminController.ImpersonateUser).Methods("POST")
+
+	// Carwash listings
+	admin.HandleFunc("/carwashes", adminController.ListCarwashes).Methods("GET")
+	admin.HandleFunc("/carwashes/{id}/verification", adminController.GetCarwashVerification).Methods("GET")
+	admin.HandleFunc("/carwashes/{id}/approval", adminController.SetCarwashApproval).Methods("PATCH")
+
+	// Reviews
+	admin.HandleFunc("/reviews/{id}/visibility", adminController.SetReviewVisibility).Methods("PATCH")
+
+	// Audit trail
+	admin.HandleFunc("/audit-events", adminController.ListAuditEvents).Methods("GET")
+
+	// Notification templates
+	admin.HandleFunc("/templates", adminController.ListTemplates).Methods("GET")
+	admin.HandleFunc("/templates/{event}/preview", adminController.PreviewTemplate).Methods("GET", "POST")
+
+	// Notification outbox
+	admin.HandleFunc("/outbox", adminController.ListOutboxJobs).Methods("GET")
+	admin.HandleFunc("/outbox/{id}/retry", adminController.RetryOutboxJob).Methods("POST")
+}
@@ -4,55 +4,67 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/olabanji12-ojo/CarWashApp/controllers"
 	"github.com/olabanji12-ojo/CarWashApp/middleware"
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/policy"
 )
 
 type BookingRouter struct {
 	bookingController controllers.BookingController
+	authz             *policy.Policy
 }
 
-func NewBookingRouter(bookingController controllers.BookingController) *BookingRouter {
-	return &BookingRouter{bookingController: bookingController}
+func NewBookingRouter(bookingController controllers.BookingController, authz *policy.Policy) *BookingRouter {
+	return &BookingRouter{bookingController: bookingController, authz: authz}
 }
 
 func (br *BookingRouter) BookingRoutes(router *mux.Router) {
 	// Public routes (no auth)
 	publicBooking := router.PathPrefix("/api/bookings").Subrouter()
 	publicBooking.HandleFunc("/carwash/{carwash_id}/slots", br.bookingController.GetAvailableSlotsHandler).Methods("GET")
-	publicBooking.HandleFunc("/track/{id}", br.bookingController.GetPublicBookingHandler).Methods("GET")
-	publicBooking.HandleFunc("/track/{id}/location", br.bookingController.UpdateWorkerLocationHandler).Methods("PATCH")
-	publicBooking.HandleFunc("/track/{id}/status", br.bookingController.UpdateBookingStatusHandler).Methods("PATCH")
+	// Tracking links are keyed by share token, never by booking ID
+	publicBooking.HandleFunc("/track/{token}", br.bookingController.GetPublicBookingHandler).Methods("GET")
+	publicBooking.HandleFunc("/track/{token}/stream", br.bookingController.StreamBookingTrackingHandler).Methods("GET")
+	publicBooking.HandleFunc("/track/{token}/location", br.bookingController.UpdateTrackedLocationHandler).Methods("PATCH")
+	publicBooking.HandleFunc("/track/{token}/status", br.bookingController.UpdateTrackedStatusHandler).Methods("PATCH")
 
 	// Protected routes (require auth)
 	protectedBooking := router.PathPrefix("/api/bookings").Subrouter()
 	protectedBooking.Use(middleware.AuthMiddleware)
+	authz := br.authz
 
 	// POST /api/bookings
 	protectedBooking.HandleFunc("", br.bookingController.CreateBookingHandler).Methods("POST")
 
 	// GET /api/bookings/{id}
-	protectedBooking.HandleFunc("/{id}", br.bookingController.GetBookingByIDHandler).Methods("GET")
+	protectedBooking.Handle("/{id}", authz.Guard("id", br.bookingController.GetBookingByIDHandler, authz.BookingOwner, authz.CanOnBooking(models.PermManageBookings), authz.AssignedWorker)).Methods("GET")
 
 	// GET /api/bookings/user/me
 	protectedBooking.HandleFunc("/user/me", br.bookingController.GetMyBookingsHandler).Methods("GET")
 
 	// GET /api/bookings/carwash/{carwash_id}
-	protectedBooking.HandleFunc("/carwash/{carwash_id}", br.bookingController.GetBookingsByCarwashHandler).Methods("GET")
+	protectedBooking.Handle("/carwash/{carwash_id}", authz.Guard("carwash_id", br.bookingController.GetBookingsByCarwashHandler, authz.Can(models.PermManageBookings))).Methods("GET")
 
 	// PUT /api/bookings/{id}
-	protectedBooking.HandleFunc("/{id}", br.bookingController.UpdateBookingHandler).Methods("PUT")
+	protectedBooking.Handle("/{id}", authz.Guard("id", br.bookingController.UpdateBookingHandler, authz.BookingOwner, authz.CanOnBooking(models.PermManageBookings))).Methods("PUT")
 
 	// PATCH /api/bookings/{id}/status
-	protectedBooking.HandleFunc("/{id}/status", br.bookingController.UpdateBookingStatusHandler).Methods("PATCH")
+	protectedBooking.Handle("/{id}/status", authz.Guard("id", br.bookingController.UpdateBookingStatusHandler, authz.CanOnBooking(models.PermManageBookings), authz.AssignedWorker)).Methods("PATCH")
 
 	// PATCH /api/bookings/{id}/location
-	protectedBooking.HandleFunc("/{id}/location", br.bookingController.UpdateWorkerLocationHandler).Methods("PATCH")
+	protectedBooking.Handle("/{id}/location", authz.Guard("id", br.bookingController.UpdateWorkerLocationHandler, authz.AssignedWorker)).Methods("PATCH")
+
+	// POST /api/bookings/{id}/tracking-links (scope rules are checked in BookingService)
+	protectedBooking.HandleFunc("/{id}/tracking-links", br.bookingController.CreateTrackingLinkHandler).Methods("POST")
+
+	// GET /api/bookings/{id}/path
+	protectedBooking.Handle("/{id}/path", authz.Guard("id", br.bookingController.GetTravelPathHandler, authz.BookingOwner, authz.CanOnBooking(models.PermManageBookings), authz.AssignedWorker)).Methods("GET")
 
 	// DELETE /api/bookings/{id}
-	protectedBooking.HandleFunc("/{id}", br.bookingController.CancelBookingHandler).Methods("DELETE")
+	protectedBooking.Handle("/{id}", authz.Guard("id", br.bookingController.CancelBookingHandler, authz.BookingOwner, authz.CanOnBooking(models.PermManageBookings))).Methods("DELETE")
 
 	// GET /api/bookings/carwash/{carwash_id}/date?date=YYYY-MM-DD
-	protectedBooking.HandleFunc("/carwash/{carwash_id}/date", br.bookingController.GetBookingsByDateHandler).Methods("GET")
+	protectedBooking.Handle("/carwash/{carwash_id}/date", authz.Guard("carwash_id", br.bookingController.GetBookingsByDateHandler, authz.Can(models.PermManageBookings))).Methods("GET")
 
 	// GET /api/bookings/carwash/{carwash_id}/filter
-	protectedBooking.HandleFunc("/carwash/{carwash_id}/filter", br.bookingController.GetBookingsByCarwashWithFiltersHandler).Methods("GET")
+	protectedBooking.Handle("/carwash/{carwash_id}/filter", authz.Guard("carwash_id", br.bookingController.GetBookingsByCarwashWithFiltersHandler, authz.Can(models.PermManageBookings))).Methods("GET")
 }
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/olabanji12-ojo/CarWashApp/controllers"
+	"github.com/olabanji12-ojo/CarWashApp/middleware"
+	"github.com/olabanji12-ojo/CarWashApp/policy"
+)
+
+// BusinessRoutes sets up the business (multi-branch) routes.
+// Owners see every branch; staff are narrowed to their own branch by BusinessService.
+func BusinessRoutes(router *mux.Router, businessController *controllers.BusinessController, authz *policy.Policy) {
+	business := router.PathPrefix("/api/businesses").Subrouter()
+	business.Use(middleware.AuthMiddleware)
+
+	business.HandleFunc("/me", businessController.GetMyBusiness).Methods("GET")
+	business.Handle("/{id}", authz.Guard("id", businessController.UpdateBusiness, authz.BusinessOwner)).Methods("PUT")
+	business.Handle("/{id}/branches", authz.Guard("id", businessController.ListBranches, authz.BusinessMember)).Methods("GET")
+	business.Handle("/{id}/bookings", authz.Guard("id", businessController.ListBookings, authz.BusinessMember)).Methods("GET")
+	business.Handle("/{id}/workers", authz.Guard("id", businessController.ListWorkers, authz.BusinessMember)).Methods("GET")
+	business.Handle("/{id}/reports", authz.Guard("id", businessController.GetReport, authz.BusinessMember)).Methods("GET")
+}
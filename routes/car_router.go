@@ -4,20 +4,23 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/olabanji12-ojo/CarWashApp/controllers"
 	"github.com/olabanji12-ojo/CarWashApp/middleware"
+	"github.com/olabanji12-ojo/CarWashApp/policy"
 )
 
 
 type CarRouter struct {
 	carController controllers.CarController
+	authz         *policy.Policy
 }
 
 
-func NewCarRouter(carController controllers.CarController) *CarRouter {
-	return &CarRouter{carController: carController}
+func NewCarRouter(carController controllers.CarController, authz *policy.Policy) *CarRouter {
+	return &CarRouter{carController: carController, authz: authz}
 }
 
 func (cr *CarRouter)CarRoutes(router *mux.Router) {
 	car := router.PathPrefix("/api/cars").Subrouter()
+	authz := cr.authz
 
 	// Apply auth middleware to all car routes 
     
@@ -25,14 +28,13 @@ func (cr *CarRouter)CarRoutes(router *mux.Router) {
     
 	car.HandleFunc("/", cr.carController.CreateCarHandler).Methods("POST") //        
 	car.HandleFunc("/my", cr.carController.GetMyCarsHandler).Methods("GET") // tested      
-	car.HandleFunc("/{carID}", cr.carController.GetCarByIDHandler).Methods("GET") // tested
+	car.Handle("/{carID}", authz.Guard("carID", cr.carController.GetCarByIDHandler, authz.CarOwner)).Methods("GET") // tested
     
-	car.HandleFunc("/update/{carID}", cr.carController.UpdateCarHandler).Methods("PUT") // tested 
-	car.HandleFunc("/{carID}", cr.carController.DeleteCarHandler).Methods("DELETE") //  
+	car.Handle("/update/{carID}", authz.Guard("carID", cr.carController.UpdateCarHandler, authz.CarOwner)).Methods("PUT") // tested 
+	car.Handle("/{carID}", authz.Guard("carID", cr.carController.DeleteCarHandler, authz.CarOwner)).Methods("DELETE") //  
 
-	car.HandleFunc("/{carID}/default", cr.carController.SetDefaultCarHandler).Methods("PATCH") // tested
+	car.Handle("/{carID}/default", authz.Guard("carID", cr.carController.SetDefaultCarHandler, authz.CarOwner)).Methods("PATCH") // tested
     
 	
 }
 
-
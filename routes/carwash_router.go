@@ -4,18 +4,23 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/olabanji12-ojo/CarWashApp/controllers"
 	"github.com/olabanji12-ojo/CarWashApp/middleware"
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/policy"
+	"github.com/olabanji12-ojo/CarWashApp/utils"
 )
 
 type CarWashRouter struct {
 	carwashController controllers.CarWashController
+	authz             *policy.Policy
 }
 
-func NewCarWashRouter(carwashController controllers.CarWashController) *CarWashRouter {
-	return &CarWashRouter{carwashController: carwashController}
+func NewCarWashRouter(carwashController controllers.CarWashController, authz *policy.Policy) *CarWashRouter {
+	return &CarWashRouter{carwashController: carwashController, authz: authz}
 }
 
 func (cwr *CarWashRouter) CarwashRoutes(parentRouter *mux.Router) {
 	carWashController := cwr.carwashController
+	authz := cwr.authz
 
 	// Create a subrouter for /api/carwashes
 	router := parentRouter.PathPrefix("/api/carwashes").Subrouter()
@@ -31,14 +36,19 @@ func (cwr *CarWashRouter) CarwashRoutes(parentRouter *mux.Router) {
 	protected := router.PathPrefix("").Subrouter()
 	protected.Use(middleware.AuthMiddleware)
 
-	protected.HandleFunc("", carWashController.CreateCarwashHandler).Methods("POST", "OPTIONS")
-	protected.HandleFunc("/{id}", carWashController.UpdateCarwashHandler).Methods("PUT", "OPTIONS")
-	protected.HandleFunc("/{id}/status", carWashController.SetCarwashStatusHandler).Methods("PUT", "OPTIONS")
-	protected.HandleFunc("/{id}/complete-onboarding", carWashController.CompleteOnboarding).Methods("POST", "OPTIONS")
-	protected.HandleFunc("/{id}/photos", carWashController.UploadCarwashPhotoHandler).Methods("POST", "OPTIONS")
-	protected.HandleFunc("/{carwashid}/services", carWashController.CreateServiceHandler).Methods("POST", "OPTIONS")
-	protected.HandleFunc("/{carwashid}/services/{serviceid}", carWashController.UpdateServiceHandler).Methods("PUT", "OPTIONS")
-	protected.HandleFunc("/{carwashid}/services/{serviceid}", carWashController.DeleteServiceHandler).Methods("DELETE", "OPTIONS")
-	protected.HandleFunc("/owner/{owner_id}", carWashController.GetCarwashesByOwnerIDHandler).Methods("GET", "OPTIONS")
-	protected.HandleFunc("/{id}/location", carWashController.UpdateCarwashLocationHandler).Methods("PUT", "OPTIONS")
+	protected.Handle("", authz.Guard("", carWashController.CreateCarwashHandler, policy.HasRole(utils.ROLE_BUSINESS))).Methods("POST", "OPTIONS")
+	protected.Handle("/{id}", authz.Guard("id", carWashController.UpdateCarwashHandler, authz.CarwashOwner)).Methods("PUT", "OPTIONS")
+	protected.Handle("/{id}/status", authz.Guard("id", carWashController.SetCarwashStatusHandler, authz.CarwashOwner)).Methods("PUT", "OPTIONS")
+	protected.Handle("/{id}/worker-2fa", authz.Guard("id", carWashController.SetWorkerTwoFactorHandler, authz.CarwashOwner)).Methods("PUT", "OPTIONS")
+	protected.Handle("/{id}/sms", authz.Guard("id", carWashController.GetSMSUsageHandler, authz.CarwashOwner)).Methods("GET", "OPTIONS")
+	protected.Handle("/{id}/sms-budget", authz.Guard("id", carWashController.SetSMSBudgetHandler, authz.CarwashOwner)).Methods("PUT", "OPTIONS")
+	protected.Handle("/{id}/complete-onboarding", authz.Guard("id", carWashController.CompleteOnboarding, authz.CarwashOwner)).Methods("POST", "OPTIONS")
+	protected.Handle("/{id}/documents", authz.Guard("id", carWashController.UploadVerificationDocumentHandler, authz.CarwashOwner)).Methods("POST", "OPTIONS")
+	protected.Handle("/{id}/verification", authz.Guard("id", carWashController.GetVerificationHandler, authz.CarwashOwner)).Methods("GET", "OPTIONS")
+	protected.Handle("/{id}/photos", authz.Guard("id", carWashController.UploadCarwashPhotoHandler, authz.CarwashOwner)).Methods("POST", "OPTIONS")
+	protected.Handle("/{carwashid}/services", authz.Guard("carwashid", carWashController.CreateServiceHandler, authz.Can(models.PermManageServices))).Methods("POST", "OPTIONS")
+	protected.Handle("/{carwashid}/services/{serviceid}", authz.Guard("carwashid", carWashController.UpdateServiceHandler, authz.Can(models.PermManageServices))).Methods("PUT", "OPTIONS")
+	protected.Handle("/{carwashid}/services/{serviceid}", authz.Guard("carwashid", carWashController.DeleteServiceHandler, authz.Can(models.PermManageServices))).Methods("DELETE", "OPTIONS")
+	protected.Handle("/owner/{owner_id}", authz.Guard("owner_id", carWashController.GetCarwashesByOwnerIDHandler, authz.Self)).Methods("GET", "OPTIONS")
+	protected.Handle("/{id}/location", authz.Guard("id", carWashController.UpdateCarwashLocationHandler, authz.CarwashOwner)).Methods("PUT", "OPTIONS")
 }
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/olabanji12-ojo/CarWashApp/controllers"
+	"github.com/olabanji12-ojo/CarWashApp/middleware"
+)
+
+// DeviceRoutes sets up push token registration. Every route acts on the caller's own devices.
+func DeviceRoutes(router *mux.Router, deviceController *controllers.DeviceController) {
+	devices := router.PathPrefix("/api/devices").Subrouter()
+	devices.Use(middleware.AuthMiddleware)
+
+	devices.HandleFunc("", deviceController.ListDevices).Methods("GET")
+	devices.HandleFunc("", deviceController.RegisterDevice).Methods("POST")
+	devices.HandleFunc("", deviceController.UnregisterDevice).Methods("DELETE")
+}
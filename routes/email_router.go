@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/olabanji12-ojo/CarWashApp/controllers"
+)
+
+// EmailRoutes sets up the email provider's webhook. It authenticates with a shared secret
+// rather than a user session.
+func EmailRoutes(router *mux.Router, emailController *controllers.EmailController) {
+	router.HandleFunc("/api/email/events", emailController.HandleEmailEvents).Methods("POST")
+}
@@ -1,27 +1,36 @@
 package routes
 
 import (
-     
 	"github.com/gorilla/mux"
 	"github.com/olabanji12-ojo/CarWashApp/controllers"
 	"github.com/olabanji12-ojo/CarWashApp/middleware"
-
+	"github.com/olabanji12-ojo/CarWashApp/policy"
 )
 
 // NotificationRoutes sets up all routes for notification-related actions
-func NotificationRoutes(router *mux.Router) {
+func NotificationRoutes(router *mux.Router, notificationController *controllers.NotificationController, authz *policy.Policy) {
+	// Unsubscribe links in emails work without logging in; the token identifies the user.
+	// GET only shows a confirmation page, POST (the page's button or one-click) unsubscribes.
+	router.HandleFunc("/api/notifications/unsubscribe", notificationController.ConfirmUnsubscribe).Methods("GET")
+	router.HandleFunc("/api/notifications/unsubscribe", notificationController.UnsubscribeFromEmails).Methods("POST")
+
 	notifications := router.PathPrefix("/api/notifications").Subrouter()
 
 	// All notification routes require authentication
 	notifications.Use(middleware.AuthMiddleware)
-    
+
 	// User notification routes
-	notifications.HandleFunc("", controllers.GetUserNotifications).Methods("GET")                    // Get user notifications
-	notifications.HandleFunc("/unread-count", controllers.GetUnreadNotificationCount).Methods("GET") // Get unread count
-	notifications.HandleFunc("/{id}/read", controllers.MarkNotificationAsRead).Methods("PUT")        // Mark specific as read
-	notifications.HandleFunc("/mark-all-read", controllers.MarkAllNotificationsAsRead).Methods("PUT") // Mark all as read
-    
+	notifications.HandleFunc("", notificationController.GetUserNotifications).Methods("GET")                                                     // Inbox page; ?type=&read=&archived=&cursor=&limit=
+	notifications.HandleFunc("/unread-count", notificationController.GetUnreadNotificationCount).Methods("GET")                                  // Get unread count
+	notifications.Handle("/{id}/read", authz.Guard("id", notificationController.MarkNotificationAsRead, authz.NotificationOwner)).Methods("PUT") // Mark specific as read
+	notifications.Handle("/{id}", authz.Guard("id", notificationController.DeleteNotification, authz.NotificationOwner)).Methods("DELETE")       // Delete one
+	notifications.HandleFunc("/mark-all-read", notificationController.MarkAllNotificationsAsRead).Methods("PUT")                                 // Mark all as read
+	notifications.HandleFunc("/archive", notificationController.ArchiveNotifications).Methods("POST")                                            // Archive several
+	notifications.HandleFunc("/delete", notificationController.DeleteNotifications).Methods("POST")                                              // Delete several
+	notifications.HandleFunc("/preferences", notificationController.GetNotificationPreferences).Methods("GET")                                   // Channels per type, quiet hours
+	notifications.HandleFunc("/preferences", notificationController.UpdateNotificationPreferences).Methods("PUT")                                // Change them
+
 	// Development/testing route
-	notifications.HandleFunc("/test", controllers.TestNotification).Methods("POST") // Send test notification
+	notifications.HandleFunc("/test", notificationController.TestNotification).Methods("POST") // Send test notification
 
 }
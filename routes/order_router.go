@@ -6,6 +6,8 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/olabanji12-ojo/CarWashApp/controllers"
 	"github.com/olabanji12-ojo/CarWashApp/middleware"
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/policy"
 	// "github.com/olabanji12-ojo/CarWashApp/services"
 
 )
@@ -13,10 +15,11 @@ import (
 
 type OrderRouter struct {
 	orderController *controllers.OrderController
+	authz           *policy.Policy
 }
 
-func NewOrderRouter(orderController *controllers.OrderController) *OrderRouter {
-	return &OrderRouter{orderController: orderController}
+func NewOrderRouter(orderController *controllers.OrderController, authz *policy.Policy) *OrderRouter {
+	return &OrderRouter{orderController: orderController, authz: authz}
 }
 
 
@@ -27,24 +30,25 @@ func(or *OrderRouter) OrderRoutes(router *mux.Router) {
 
 	orderRouter := router.PathPrefix("/api/orders").Subrouter()
 	orderRouter.Use(middleware.AuthMiddleware)
+	authz := or.authz
 
 	//  Create order from approved booking
 	
-	orderRouter.HandleFunc("/booking/{booking_id}", or.orderController.CreateOrderHandler).Methods("POST") // tested
+	orderRouter.Handle("/booking/{booking_id}", authz.Guard("booking_id", or.orderController.CreateOrderHandler, authz.CanOnBooking(models.PermManageBookings))).Methods("POST") // tested
 	//  Get specific order
-	orderRouter.HandleFunc("/order/{order_id}", or.orderController.GetOrderByIDHandler).Methods("GET") // tested 
+	orderRouter.Handle("/order/{order_id}", authz.Guard("order_id", or.orderController.GetOrderByIDHandler, authz.OrderOwner, authz.CanOnOrder(models.PermManageBookings), authz.OrderAssignedWorker)).Methods("GET") // tested 
 
 	//  Get logged-in user's orders (car owner)
 	orderRouter.HandleFunc("/my", or.orderController.GetUserOrdersHandler).Methods("GET") // tested
 
 	//  Get business orders (business user)
-	orderRouter.HandleFunc("/business", or.orderController.GetCarwashOrdersHandler).Methods("GET") // tested
+	orderRouter.Handle("/business", authz.Guard("", or.orderController.GetCarwashOrdersHandler, authz.CanAtOwnCarwash(models.PermManageBookings))).Methods("GET") // tested
 
 	//  Update order status (e.g. completed, in_progress)
-	orderRouter.HandleFunc("/{order_id}/status", or.orderController.UpdateOrderStatusHandler).Methods("PATCH") // tested
+	orderRouter.Handle("/{order_id}/status", authz.Guard("order_id", or.orderController.UpdateOrderStatusHandler, authz.CanOnOrder(models.PermManageBookings), authz.OrderAssignedWorker)).Methods("PATCH") // tested
 
 	//  Assign a worker (optional)
-	orderRouter.HandleFunc("/{order_id}/assign", or.orderController.AssignWorkerHandler).Methods("PATCH") // to be built later
+	orderRouter.Handle("/{order_id}/assign", authz.Guard("order_id", or.orderController.AssignWorkerHandler, authz.CanOnOrder(models.PermManageBookings))).Methods("PATCH") // to be built later
 
 
 }
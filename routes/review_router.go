@@ -7,14 +7,17 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/olabanji12-ojo/CarWashApp/controllers"
 	"github.com/olabanji12-ojo/CarWashApp/middleware"
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/policy"
 )
 
 type ReviewRouter struct {
 	reviewController controllers.ReviewController
+	authz            *policy.Policy
 }
 
-func NewReviewRouter(reviewController controllers.ReviewController) *ReviewRouter {
-	return &ReviewRouter{reviewController: reviewController}
+func NewReviewRouter(reviewController controllers.ReviewController, authz *policy.Policy) *ReviewRouter {
+	return &ReviewRouter{reviewController: reviewController, authz: authz}
 }
 
 func (rr *ReviewRouter) ReviewRoutes(router *mux.Router) {
@@ -23,9 +26,9 @@ func (rr *ReviewRouter) ReviewRoutes(router *mux.Router) {
 	review.Use(middleware.AuthMiddleware)
 
 	// 🔐 Authenticated routes
-	review.HandleFunc("", rr.reviewController.LeaveReviewHandler).Methods("POST")              // tested
-	review.HandleFunc("/user", rr.reviewController.GetReviewsByUserHandler).Methods("GET")     // tested
-	review.HandleFunc("/{id}/reply", rr.reviewController.ReplyToReviewHandler).Methods("POST") // New reply route
+	review.HandleFunc("", rr.reviewController.LeaveReviewHandler).Methods("POST")                                                                               // tested
+	review.HandleFunc("/user", rr.reviewController.GetReviewsByUserHandler).Methods("GET")                                                                      // tested
+	review.Handle("/{id}/reply", rr.authz.Guard("id", rr.reviewController.ReplyToReviewHandler, rr.authz.CanOnReview(models.PermReplyReviews))).Methods("POST") // New reply route
 
 	// 🌐 Public access
 	review.HandleFunc("/order/{id}", rr.reviewController.GetReviewByOrderIDHandler).Methods("GET")                // tested
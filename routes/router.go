@@ -6,7 +6,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/olabanji12-ojo/CarWashApp/controllers"
-	// "github.com/olabanji12-ojo/CarWashApp/middleware"
+	"github.com/olabanji12-ojo/CarWashApp/middleware"
 )
 
 func AuthRoutes(router *mux.Router, authService *controllers.AuthController) {
@@ -33,9 +33,15 @@ func AuthRoutes(router *mux.Router, authService *controllers.AuthController) {
 	// GET /api/auth/google/callback
 	auth.HandleFunc("/google/callback", authService.GoogleCallbackHandler).Methods("GET")
 
+	// POST /api/auth/refresh
+	auth.HandleFunc("/refresh", authService.RefreshTokenHandler).Methods("POST")
+
 	// POST /api/auth/logout
 	auth.HandleFunc("/logout", authService.LogoutHandler).Methods("POST")
 
+	// POST /api/auth/logout-all (revokes every session of the caller)
+	auth.Handle("/logout-all", middleware.AuthMiddleware(http.HandlerFunc(authService.LogoutAllHandler))).Methods("POST")
+
 	// POST /api/auth/verify
 	auth.HandleFunc("/verify", authService.VerifyEmailHandler).Methods("POST")
 
@@ -43,9 +49,42 @@ func AuthRoutes(router *mux.Router, authService *controllers.AuthController) {
 	auth.HandleFunc("/resend-verification", authService.ResendVerificationEmailHandler).Methods("POST")
 
 	// POST /api/auth/forgot-password
-	auth.HandleFunc("/forgot-password", controllers.ForgotPasswordHandler).Methods("POST")
+	auth.HandleFunc("/forgot-password", authService.ForgotPasswordHandler).Methods("POST")
 
 	// POST /api/auth/reset-password
-	auth.HandleFunc("/reset-password", controllers.ResetPasswordHandler).Methods("POST")
-
-}
\ No newline at end of file
+	auth.HandleFunc("/reset-password", authService.ResetPasswordHandler).Methods("POST")
+
+}
+
+// WellKnownRoutes serves discovery documents such as the JWKS used to verify access tokens
+func WellKnownRoutes(router *mux.Router, jwksController *controllers.JWKSController) {
+	// GET /.well-known/jwks.json
+	router.HandleFunc("/.well-known/jwks.json", jwksController.JWKSHandler).Methods("GET")
+}
+
+// TwoFactorRoutes covers 2FA management for logged-in users and the second login step
+func TwoFactorRoutes(router *mux.Router, twoFactorController *controllers.TwoFactorController) {
+	twoFactor := router.PathPrefix("/api/auth/2fa").Subrouter()
+
+	// Second login step (authenticated by the pre-auth token in the body)
+	twoFactor.HandleFunc("/verify", twoFactorController.VerifyLoginHandler).Methods("POST")
+	twoFactor.HandleFunc("/enroll/setup", twoFactorController.EnrollSetupHandler).Methods("POST")
+	twoFactor.HandleFunc("/enroll/confirm", twoFactorController.EnrollConfirmHandler).Methods("POST")
+
+	// Managing 2FA on an existing session
+	twoFactor.Handle("/setup", middleware.AuthMiddleware(http.HandlerFunc(twoFactorController.SetupHandler))).Methods("POST")
+	twoFactor.Handle("/confirm", middleware.AuthMiddleware(http.HandlerFunc(twoFactorController.ConfirmHandler))).Methods("POST")
+	twoFactor.Handle("/disable", middleware.AuthMiddleware(http.HandlerFunc(twoFactorController.DisableHandler))).Methods("POST")
+	twoFactor.Handle("/recovery-codes", middleware.AuthMiddleware(http.HandlerFunc(twoFactorController.RegenerateRecoveryCodesHandler))).Methods("POST")
+}
+
+// PhoneAuthRoutes covers passwordless login with SMS codes
+func PhoneAuthRoutes(router *mux.Router, phoneAuthController *controllers.PhoneAuthController) {
+	phone := router.PathPrefix("/api/auth/phone").Subrouter()
+
+	// POST /api/auth/phone/request
+	phone.HandleFunc("/request", phoneAuthController.RequestCodeHandler).Methods("POST")
+
+	// POST /api/auth/phone/verify
+	phone.HandleFunc("/verify", phoneAuthController.VerifyCodeHandler).Methods("POST")
+}
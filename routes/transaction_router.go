@@ -4,19 +4,21 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/olabanji12-ojo/CarWashApp/controllers"
 	"github.com/olabanji12-ojo/CarWashApp/middleware"
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/policy"
 )
 
 //  Register payment-related routes here
-func PaymentRoutes(router *mux.Router) {
+func PaymentRoutes(router *mux.Router, paymentController *controllers.PaymentController, authz *policy.Policy) {
 
 
 	payment := router.PathPrefix("/api/payments").Subrouter()
 	payment.Use(middleware.AuthMiddleware) // Protect all routes
 
-	payment.HandleFunc("", controllers.CreatePaymentHandler).Methods("POST") // tested
-	payment.HandleFunc("/payment/{id}", controllers.GetPaymentByIDHandler).Methods("GET") // testing
-	payment.HandleFunc("/user", controllers.GetPaymentsByUserHandler).Methods("GET") // tested
-	payment.HandleFunc("/carwash/{id}", controllers.GetPaymentsByCarwashHandler).Methods("GET") // tested
+	payment.Handle("/order/{id}", authz.Guard("id", paymentController.CreatePaymentHandler, authz.OrderOwner)).Methods("POST") // id is the order ID
+	payment.Handle("/payment/{id}", authz.Guard("id", paymentController.GetPaymentByIDHandler, authz.OrderOwner, authz.CanOnOrder(models.PermViewFinances))).Methods("GET") // testing (id is the order ID)
+	payment.HandleFunc("/user", paymentController.GetPaymentsByUserHandler).Methods("GET") // tested
+	payment.Handle("/carwash/{id}", authz.Guard("id", paymentController.GetPaymentsByCarwashHandler, authz.Can(models.PermViewFinances))).Methods("GET") // tested
 
 }
 
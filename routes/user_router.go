@@ -3,51 +3,55 @@ package routes
 import (
 	"github.com/gorilla/mux"
 	"github.com/olabanji12-ojo/CarWashApp/controllers"
-	// "github.com/olabanji12-ojo/CarWashApp/middleware"
+	"github.com/olabanji12-ojo/CarWashApp/middleware"
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/policy"
 )
 
 // UserRouter handles user-related routing
 type UserRouter struct {
 	userController *controllers.UserController
+	authz          *policy.Policy
 }
 
 // NewUserRouter creates a new UserRouter instance
-func NewUserRouter(userController *controllers.UserController) *UserRouter {
-	return &UserRouter{userController: userController}
+func NewUserRouter(userController *controllers.UserController, authz *policy.Policy) *UserRouter {
+	return &UserRouter{userController: userController, authz: authz}
 }
 
 // UserRoutes sets up all user-related routes
 func (ur *UserRouter) UserRoutes(router *mux.Router) {
-	
+	uc := ur.userController
+	authz := ur.authz
+
+	// Public profile (no auth)
+	publicRouter := router.PathPrefix("/api/user").Subrouter()
+	publicRouter.HandleFunc("/{id}/public", uc.GetPublicUser).Methods("GET") // tested
+
 	// User Routes
 	userRouter := router.PathPrefix("/api/user").Subrouter()
-
-	// Apply auth middleware to all user routes if needed
-	// userRouter.Use(middleware.AuthMiddleware)
+	userRouter.Use(middleware.AuthMiddleware)
 
 	// Basic user operations
-	userRouter.HandleFunc("/{id}", ur.userController.GetUserProfile).Methods("GET")                 // tested
-	userRouter.HandleFunc("/callback/me", ur.userController.GetCurrentUser).Methods("GET")          // tested
-	userRouter.HandleFunc("/{id}", ur.userController.UpdateUserProfile).Methods("PUT")              // tested
-	userRouter.HandleFunc("/{id}", ur.userController.DeleteUser).Methods("DELETE")                  // tested
-	userRouter.HandleFunc("/{id}/role", ur.userController.GetUserRole).Methods("GET")               // tested
-	userRouter.HandleFunc("/{id}/loyalty", ur.userController.GetLoyaltyPoints).Methods("GET")       // tested
-	userRouter.HandleFunc("/{id}/public", ur.userController.GetPublicUser).Methods("GET")           // tested
+	userRouter.HandleFunc("/callback/me", uc.GetCurrentUser).Methods("GET")                                           // tested
+	userRouter.Handle("/{id}", authz.Guard("id", uc.GetUserProfile, authz.Self, authz.CanOnWorker(models.PermManageWorkers))).Methods("GET") // tested
+	userRouter.Handle("/{id}", authz.Guard("id", uc.UpdateUserProfile, authz.Self)).Methods("PUT")                    // tested
+	userRouter.Handle("/{id}", authz.Guard("id", uc.DeleteUser, authz.Self)).Methods("DELETE")                        // tested
+	userRouter.Handle("/{id}/role", authz.Guard("id", uc.GetUserRole, authz.Self)).Methods("GET")                     // tested
+	userRouter.Handle("/{id}/loyalty", authz.Guard("id", uc.GetLoyaltyPoints, authz.Self)).Methods("GET")             // tested
 
 	// Address management routes
-	userRouter.HandleFunc("/{id}/addresses", ur.userController.GetUserAddresses).Methods("GET")                       // new
-	userRouter.HandleFunc("/{id}/addresses", ur.userController.AddUserAddress).Methods("POST")                        // MVP
-	userRouter.HandleFunc("/{id}/addresses/{address_id}", ur.userController.UpdateUserAddress).Methods("PUT")         // new
-	userRouter.HandleFunc("/{id}/addresses/{address_id}", ur.userController.DeleteUserAddress).Methods("DELETE")      // MVP
-	userRouter.HandleFunc("/{id}/addresses/{address_id}/default", ur.userController.SetDefaultAddress).Methods("PATCH") // new
+	userRouter.Handle("/{id}/addresses", authz.Guard("id", uc.GetUserAddresses, authz.Self)).Methods("GET")                         // new
+	userRouter.Handle("/{id}/addresses", authz.Guard("id", uc.AddUserAddress, authz.Self)).Methods("POST")                          // MVP
+	userRouter.Handle("/{id}/addresses/{address_id}", authz.Guard("id", uc.UpdateUserAddress, authz.Self)).Methods("PUT")           // new
+	userRouter.Handle("/{id}/addresses/{address_id}", authz.Guard("id", uc.DeleteUserAddress, authz.Self)).Methods("DELETE")        // MVP
+	userRouter.Handle("/{id}/addresses/{address_id}/default", authz.Guard("id", uc.SetDefaultAddress, authz.Self)).Methods("PATCH") // new
 
 	// Profile photo routes
-	userRouter.HandleFunc("/{id}/photo", ur.userController.UploadProfilePhoto).Methods("POST")      // MVP
-	userRouter.HandleFunc("/{id}/photo", ur.userController.DeleteProfilePhoto).Methods("DELETE")    // MVP
+	userRouter.Handle("/{id}/photo", authz.Guard("id", uc.UploadProfilePhoto, authz.Self)).Methods("POST")   // MVP
+	userRouter.Handle("/{id}/photo", authz.Guard("id", uc.DeleteProfilePhoto, authz.Self)).Methods("DELETE") // MVP
 
 	//  Business Route for Workers
 	// businessRouter := router.PathPrefix("/api/business").Subrouter()
 	// businessRouter.HandleFunc("/{id}/workers", ur.userController.GetWorkersForBusiness).Methods("GET") // pending
-    
-	
 }
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/olabanji12-ojo/CarWashApp/controllers"
+	"github.com/olabanji12-ojo/CarWashApp/middleware"
+	"github.com/olabanji12-ojo/CarWashApp/policy"
+)
+
+// WebhookRoutes sets up a carwash's webhook endpoints and their delivery log. Owner only.
+func WebhookRoutes(router *mux.Router, webhookController *controllers.WebhookController, authz *policy.Policy) {
+	webhooks := router.PathPrefix("/api/carwashes/{id}/webhooks").Subrouter()
+	webhooks.Use(middleware.AuthMiddleware)
+
+	webhooks.Handle("", authz.Guard("id", webhookController.ListWebhooks, authz.CarwashOwner)).Methods("GET", "OPTIONS")
+	webhooks.Handle("", authz.Guard("id", webhookController.CreateWebhook, authz.CarwashOwner)).Methods("POST", "OPTIONS")
+	webhooks.Handle("/{webhookID}", authz.Guard("id", webhookController.GetWebhook, authz.CarwashOwner)).Methods("GET", "OPTIONS")
+	webhooks.Handle("/{webhookID}", authz.Guard("id", webhookController.UpdateWebhook, authz.CarwashOwner)).Methods("PATCH", "OPTIONS")
+	webhooks.Handle("/{webhookID}", authz.Guard("id", webhookController.DeleteWebhook, authz.CarwashOwner)).Methods("DELETE", "OPTIONS")
+	webhooks.Handle("/{webhookID}/rotate-secret", authz.Guard("id", webhookController.RotateWebhookSecret, authz.CarwashOwner)).Methods("POST", "OPTIONS")
+	webhooks.Handle("/{webhookID}/deliveries", authz.Guard("id", webhookController.ListWebhookDeliveries, authz.CarwashOwner)).Methods("GET", "OPTIONS")
+	webhooks.Handle("/{webhookID}/deliveries/{deliveryID}", authz.Guard("id", webhookController.GetWebhookDelivery, authz.CarwashOwner)).Methods("GET", "OPTIONS")
+	webhooks.Handle("/{webhookID}/deliveries/{deliveryID}/redeliver", authz.Guard("id", webhookController.RedeliverWebhook, authz.CarwashOwner)).Methods("POST", "OPTIONS")
+}
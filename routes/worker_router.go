@@ -4,39 +4,57 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/olabanji12-ojo/CarWashApp/controllers"
 	"github.com/olabanji12-ojo/CarWashApp/middleware"
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/policy"
 )
 
 // WorkerRouter handles worker-related routing
 type WorkerRouter struct {
 	workerController *controllers.WorkerController
+	authz            *policy.Policy
 }
 
 // NewWorkerRouter creates a new WorkerRouter instance
-func NewWorkerRouter(workerController *controllers.WorkerController) *WorkerRouter {
-	return &WorkerRouter{workerController: workerController}
+func NewWorkerRouter(workerController *controllers.WorkerController, authz *policy.Policy) *WorkerRouter {
+	return &WorkerRouter{workerController: workerController, authz: authz}
 }
 
 // WorkerRoutes sets up all worker-related routes
 func (wr *WorkerRouter) WorkerRoutes(router *mux.Router) {
+	// Public routes (no auth) - invited workers don't have a password yet
+	publicRouter := router.PathPrefix("/api/workers").Subrouter()
+	publicRouter.HandleFunc("/invites/accept", wr.workerController.AcceptWorkerInvite).Methods("POST")
+
 	subRouter := router.PathPrefix("/api/workers").Subrouter()
 
 	// Apply auth middleware to all worker routes
 	subRouter.Use(middleware.AuthMiddleware)
+	wc := wr.workerController
+	authz := wr.authz
 
 	// Worker CRUD operations
-	subRouter.HandleFunc("/create", wr.workerController.CreateWorkersForBusiness).Methods("POST")
-	subRouter.HandleFunc("/business/{id}", wr.workerController.GetWorkersForBusiness).Methods("GET")
+	subRouter.Handle("/create", authz.Guard("", wc.CreateWorkersForBusiness, authz.CanAtOwnCarwash(models.PermManageWorkers))).Methods("POST")
+	subRouter.Handle("/business/{id}", authz.Guard("id", wc.GetWorkersForBusiness, authz.Can(models.PermManageWorkers), authz.Can(models.PermManageBookings))).Methods("GET")
+
+	// Staff roles (WorkerService also stops non-owners from granting manager)
+	subRouter.Handle("/{id}/staff-role", authz.Guard("id", wc.UpdateStaffRole, authz.CanOnWorker(models.PermManageWorkers))).Methods("PATCH")
+
+	// Worker invite management (carwash permissions are checked in WorkerService)
+	subRouter.HandleFunc("/invites", wr.workerController.ListWorkerInvites).Methods("GET")
+	subRouter.HandleFunc("/invites/{id}", wr.workerController.RevokeWorkerInvite).Methods("DELETE")
+	subRouter.HandleFunc("/invites/{id}/resend", wr.workerController.ResendWorkerInvite).Methods("POST")
 
 	// Worker status management
-	subRouter.HandleFunc("/status/{id}", wr.workerController.UpdateWorkerStatus).Methods("PATCH")
-	subRouter.HandleFunc("/work-status/{id}", wr.workerController.UpdateWorkerWorkStatus).Methods("PATCH")
+	subRouter.Handle("/status/{id}", authz.Guard("id", wc.UpdateWorkerStatus, authz.CanOnWorker(models.PermManageWorkers))).Methods("PATCH")
+	subRouter.Handle("/work-status/{id}", authz.Guard("id", wc.UpdateWorkerWorkStatus, authz.Self, authz.CanOnWorker(models.PermManageWorkers))).Methods("PATCH")
 
 	// Worker assignment functionality
-	subRouter.HandleFunc("/available/{id}", wr.workerController.GetAvailableWorkersForBusiness).Methods("GET")
+	subRouter.Handle("/available/{id}", authz.Guard("id", wc.GetAvailableWorkersForBusiness, authz.Can(models.PermManageBookings))).Methods("GET")
+	// Assign/remove take IDs in the body; WorkerService checks them against the policy
 	subRouter.HandleFunc("/assign", wr.workerController.AssignWorkerToOrder).Methods("POST")
 	subRouter.HandleFunc("/remove", wr.workerController.RemoveWorkerFromOrder).Methods("POST")
 
 	// Worker profile management
-	subRouter.HandleFunc("/{id}", wr.workerController.UpdateWorkerHandler).Methods("PUT", "OPTIONS")
-	subRouter.HandleFunc("/{id}/photo", wr.workerController.UploadWorkerPhotoHandler).Methods("POST", "OPTIONS")
+	subRouter.Handle("/{id}", authz.Guard("id", wc.UpdateWorkerHandler, authz.Self, authz.CanOnWorker(models.PermManageWorkers))).Methods("PUT", "OPTIONS")
+	subRouter.Handle("/{id}/photo", authz.Guard("id", wc.UploadWorkerPhotoHandler, authz.Self, authz.CanOnWorker(models.PermManageWorkers))).Methods("POST", "OPTIONS")
 }
@@ -0,0 +1,394 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/repositories"
+	"github.com/olabanji12-ojo/CarWashApp/templates"
+	"github.com/olabanji12-ojo/CarWashApp/utils"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UserSearch filters the admin user list
+type UserSearch struct {
+	Query  string // matched against name, email and phone
+	Role   string
+	Status string
+	Page   int
+	Limit  int
+}
+
+// UserPage is one page of the admin user list
+type UserPage struct {
+	Users []models.User `json:"users"`
+	Total int64         `json:"total"`
+	Page  int           `json:"page"`
+	Limit int           `json:"limit"`
+}
+
+// AdminService backs the platform moderation API. Every change it makes is written to the audit log.
+type AdminService struct {
+	userRepo       *repositories.UserRepository
+	carwashRepo    *repositories.CarWashRepository
+	reviewRepo     *repositories.ReviewRepository
+	auditRepo      *repositories.AuditRepository
+	outboxRepo     *repositories.OutboxRepository
+	sessionService *SessionService
+}
+
+// NewAdminService creates a new AdminService instance
+func NewAdminService(userRepo *repositories.UserRepository, carwashRepo *repositories.CarWashRepository, reviewRepo *repositories.ReviewRepository, auditRepo *repositories.AuditRepository, outboxRepo *repositories.OutboxRepository, sessionService *SessionService) *AdminService {
+	return &AdminService{
+		userRepo:       userRepo,
+		carwashRepo:    carwashRepo,
+		reviewRepo:     reviewRepo,
+		auditRepo:      auditRepo,
+		outboxRepo:     outboxRepo,
+		sessionService: sessionService,
+	}
+}
+
+// SearchUsers lists users matching the search, newest first
+func (as *AdminService) SearchUsers(search UserSearch) (*UserPage, error) {
+	if search.Page < 1 {
+		search.Page = 1
+	}
+	if search.Limit < 1 || search.Limit > 100 {
+		search.Limit = 20
+	}
+
+	filter := bson.M{}
+	if search.Role != "" {
+		filter["role"] = search.Role
+	}
+	if search.Status != "" {
+		filter["status"] = search.Status
+	}
+	if q := strings.TrimSpace(search.Query); q != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(q), Options: "i"}
+		filter["$or"] = bson.A{
+			bson.M{"name": pattern},
+			bson.M{"email": pattern},
+			bson.M{"phone": pattern},
+		}
+	}
+
+	skip := int64((search.Page - 1) * search.Limit)
+	users, total, err := as.userRepo.SearchUsers(filter, skip, int64(search.Limit))
+	if err != nil {
+		logrus.Error("Failed to search users: ", err)
+		return nil, errors.New("failed to search users")
+	}
+	return &UserPage{Users: users, Total: total, Page: search.Page, Limit: search.Limit}, nil
+}
+
+// GetUser returns one user's account without credentials
+func (as *AdminService) GetUser(userID string) (*models.User, error) {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+	user, err := as.userRepo.FindUserByID(objID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	user.Password = ""
+	user.VerificationCode = ""
+	user.PasswordResetToken = ""
+	return user, nil
+}
+
+// SetUserStatus suspends or reactivates an account. Suspending also ends all of its sessions.
+func (as *AdminService) SetUserStatus(adminID, userID, status, reason, ip string) error {
+	if status != "active" && status != "suspended" {
+		return errors.New("status must be active or suspended")
+	}
+	adminObjID, err := primitive.ObjectIDFromHex(adminID)
+	if err != nil {
+		return errors.New("invalid admin ID")
+	}
+	user, err := as.GetUser(userID)
+	if err != nil {
+		return err
+	}
+	if user.ID == adminObjID {
+		return errors.New("you cannot change your own account status")
+	}
+	if status == "suspended" && strings.TrimSpace(reason) == "" {
+		return errors.New("a reason is required to suspend a user")
+	}
+
+	if err := as.userRepo.UpdateUserByID(user.ID, bson.M{"status": status}); err != nil {
+		return errors.New("failed to update user status")
+	}
+
+	action := models.AuditUserReactivated
+	if status == "suspended" {
+		action = models.AuditUserSuspended
+		if _, err := as.sessionService.LogoutAll(userID, models.SessionRevokedSuspended); err != nil {
+			logrus.Error("Failed to revoke sessions of suspended user: ", err)
+		}
+	}
+
+	as.audit(&models.AuditEvent{
+		Action:       action,
+		ActorID:      &adminObjID,
+		TargetUserID: &user.ID,
+		IPAddress:    ip,
+		Details:      map[string]interface{}{"reason": reason, "previous_status": user.Status},
+	})
+	logrus.Infof("🛡️ Admin %s set user %s to %s", adminID, userID, status)
+	return nil
+}
+
+// ListCarwashes returns listings in one approval state, or all listings when status is empty
+func (as *AdminService) ListCarwashes(approvalStatus string) ([]models.Carwash, error) {
+	filter := bson.M{}
+	if approvalStatus != "" {
+		filter["approval_status"] = approvalStatus
+	}
+	carwashes, err := as.carwashRepo.GetCarwashesByFilter(filter)
+	if err != nil {
+		return nil, errors.New("failed to list carwashes")
+	}
+	if carwashes == nil {
+		carwashes = []models.Carwash{}
+	}
+	return carwashes, nil
+}
+
+// GetCarwashVerification returns a listing's review state and its KYC documents
+func (as *AdminService) GetCarwashVerification(carwashID string) (*CarwashVerification, error) {
+	objID, err := primitive.ObjectIDFromHex(carwashID)
+	if err != nil {
+		return nil, errors.New("invalid carwash ID")
+	}
+	carwash, err := as.carwashRepo.GetCarwashByID(objID)
+	if err != nil {
+		return nil, errors.New("carwash not found")
+	}
+	return newCarwashVerification(carwash), nil
+}
+
+// SetCarwashApproval approves a listing after KYC review, rejects it, or takes it down.
+// Rejecting or deactivating needs a reason the owner can act on.
+func (as *AdminService) SetCarwashApproval(adminID, carwashID, status, note, ip string) error {
+	switch status {
+	case models.CarwashApprovalApproved:
+	case models.CarwashApprovalRejected, models.CarwashApprovalDeactivated:
+		if strings.TrimSpace(note) == "" {
+			return errors.New("a reason is required to reject or deactivate a listing")
+		}
+	default:
+		return errors.New("approval status must be approved, rejected or deactivated")
+	}
+	adminObjID, err := primitive.ObjectIDFromHex(adminID)
+	if err != nil {
+		return errors.New("invalid admin ID")
+	}
+	carwashObjID, err := primitive.ObjectIDFromHex(carwashID)
+	if err != nil {
+		return errors.New("invalid carwash ID")
+	}
+	carwash, err := as.carwashRepo.GetCarwashByID(carwashObjID)
+	if err != nil {
+		return errors.New("carwash not found")
+	}
+	if status == models.CarwashApprovalApproved && len(carwash.VerificationDocuments) == 0 {
+		return errors.New("the owner has not submitted any verification documents")
+	}
+
+	if err := as.carwashRepo.SetApprovalStatus(carwashObjID, status, note); err != nil {
+		return err
+	}
+
+	as.audit(&models.AuditEvent{
+		Action:       models.AuditCarwashApproval,
+		ActorID:      &adminObjID,
+		TargetUserID: &carwash.OwnerID,
+		IPAddress:    ip,
+		Details: map[string]interface{}{
+			"carwash_id":      carwashID,
+			"previous_status": carwash.ApprovalStatus,
+			"status":          status,
+			"note":            note,
+		},
+	})
+	logrus.Infof("🛡️ Admin %s set carwash %s to %s", adminID, carwashID, status)
+	return nil
+}
+
+// SetReviewVisibility hides an abusive review from the carwash page or restores it
+func (as *AdminService) SetReviewVisibility(adminID, reviewID string, hidden bool, reason, ip string) error {
+	if hidden && strings.TrimSpace(reason) == "" {
+		return errors.New("a reason is required to hide a review")
+	}
+	adminObjID, err := primitive.ObjectIDFromHex(adminID)
+	if err != nil {
+		return errors.New("invalid admin ID")
+	}
+	reviewObjID, err := primitive.ObjectIDFromHex(reviewID)
+	if err != nil {
+		return errors.New("invalid review ID")
+	}
+	review, err := as.reviewRepo.GetReviewByID(reviewObjID)
+	if err != nil {
+		return err
+	}
+
+	if err := as.reviewRepo.SetHidden(reviewObjID, hidden, reason); err != nil {
+		return err
+	}
+
+	action := models.AuditReviewUnhidden
+	if hidden {
+		action = models.AuditReviewHidden
+	}
+	as.audit(&models.AuditEvent{
+		Action:       action,
+		ActorID:      &adminObjID,
+		TargetUserID: &review.UserID,
+		IPAddress:    ip,
+		Details:      map[string]interface{}{"review_id": reviewID, "carwash_id": review.CarwashID.Hex(), "reason": reason},
+	})
+	return nil
+}
+
+// Impersonate opens a short session as another user for support.
+// Admin accounts cannot be impersonated, and every request made with the session is audited.
+func (as *AdminService) Impersonate(adminID, userID, reason string, client ClientInfo) (*TokenPair, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, errors.New("a reason is required to impersonate a user")
+	}
+	adminObjID, err := primitive.ObjectIDFromHex(adminID)
+	if err != nil {
+		return nil, errors.New("invalid admin ID")
+	}
+	user, err := as.GetUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.ID == adminObjID || user.Role == utils.ROLE_ADMIN {
+		return nil, errors.New("admin accounts cannot be impersonated")
+	}
+	if user.Status == "suspended" {
+		return nil, errors.New("account suspended")
+	}
+
+	pair, err := as.sessionService.IssueImpersonationSession(adminObjID, user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	as.audit(&models.AuditEvent{
+		Action:       models.AuditImpersonationStarted,
+		ActorID:      &adminObjID,
+		TargetUserID: &user.ID,
+		IPAddress:    client.IPAddress,
+		Details:      map[string]interface{}{"reason": reason, "session_id": pair.SessionID},
+	})
+	logrus.Warnf("🕵️ Admin %s is impersonating user %s", adminID, userID)
+	return pair, nil
+}
+
+// RecordImpersonatedRequest implements middleware.ImpersonationAuditor
+func (as *AdminService) RecordImpersonatedRequest(impersonatorID, userID, method, path, ipAddress string) {
+	event := &models.AuditEvent{
+		Action:    models.AuditImpersonatedRequest,
+		IPAddress: ipAddress,
+		Details:   map[string]interface{}{"method": method, "path": path},
+	}
+	if objID, err := primitive.ObjectIDFromHex(impersonatorID); err == nil {
+		event.ActorID = &objID
+	}
+	if objID, err := primitive.ObjectIDFromHex(userID); err == nil {
+		event.TargetUserID = &objID
+	}
+	as.audit(event)
+}
+
+// ListAuditEvents returns the newest audit events, optionally narrowed by action, actor or target user
+func (as *AdminService) ListAuditEvents(action, actorID, targetUserID string, limit int64) ([]models.AuditEvent, error) {
+	if limit < 1 || limit > 500 {
+		limit = 100
+	}
+	filter := bson.M{}
+	if action != "" {
+		filter["action"] = action
+	}
+	if actorID != "" {
+		objID, err := primitive.ObjectIDFromHex(actorID)
+		if err != nil {
+			return nil, errors.New("invalid actor ID")
+		}
+		filter["actor_id"] = objID
+	}
+	if targetUserID != "" {
+		objID, err := primitive.ObjectIDFromHex(targetUserID)
+		if err != nil {
+			return nil, errors.New("invalid user ID")
+		}
+		filter["target_user_id"] = objID
+	}
+	return as.auditRepo.ListEvents(filter, limit)
+}
+
+// ListOutboxJobs returns the newest notification deliveries, e.g. the dead-lettered ones
+func (as *AdminService) ListOutboxJobs(status string, limit int64) ([]models.NotificationJob, error) {
+	if limit < 1 || limit > 500 {
+		limit = 100
+	}
+	jobs, err := as.outboxRepo.ListJobs(status, limit)
+	if err != nil {
+		logrus.Error("Failed to list outbox jobs: ", err)
+		return nil, errors.New("failed to list outbox jobs")
+	}
+	return jobs, nil
+}
+
+// RetryOutboxJob sends a dead-lettered delivery back to the queue
+func (as *AdminService) RetryOutboxJob(jobID string) error {
+	objID, err := primitive.ObjectIDFromHex(jobID)
+	if err != nil {
+		return errors.New("invalid job ID")
+	}
+	return as.outboxRepo.RequeueDeadJob(objID)
+}
+
+// TemplateCatalog describes the notification templates admins can preview
+type TemplateCatalog struct {
+	Version string            `json:"version"`
+	Locales []string          `json:"locales"`
+	Events  []templates.Event `json:"events"`
+}
+
+// ListTemplates returns the events and locales of the current template version
+func (as *AdminService) ListTemplates() *TemplateCatalog {
+	renderer := templates.Default()
+	return &TemplateCatalog{
+		Version: renderer.Version(),
+		Locales: renderer.Locales(),
+		Events:  renderer.Events(),
+	}
+}
+
+// PreviewTemplate renders an event in a locale. Missing data falls back to sample values.
+func (as *AdminService) PreviewTemplate(event, locale string, data map[string]interface{}) (*templates.Message, error) {
+	sample := templates.SampleData(templates.Event(event))
+	for key, value := range data {
+		sample[key] = value
+	}
+	return templates.Render(templates.Event(event), locale, sample)
+}
+
+func (as *AdminService) audit(event *models.AuditEvent) {
+	event.SetDefaults()
+	if err := as.auditRepo.CreateEvent(event); err != nil {
+		logrus.Errorf("Failed to record %s audit event: %v", event.Action, err)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/olabanji12-ojo/CarWashApp/database"
 	"github.com/olabanji12-ojo/CarWashApp/models"
 	"github.com/olabanji12-ojo/CarWashApp/repositories"
+	"github.com/olabanji12-ojo/CarWashApp/templates"
 	"github.com/olabanji12-ojo/CarWashApp/utils"
 	// "fmt"
 )
@@ -28,10 +29,22 @@ import (
 
 type AuthService struct {
 	userRepository repositories.UserRepository
+	throttle       *AuthThrottleService
 }
 
-func NewAuthService(userRepository repositories.UserRepository) *AuthService {
-	return &AuthService{userRepository: userRepository}
+func NewAuthService(userRepository repositories.UserRepository, throttle *AuthThrottleService) *AuthService {
+	return &AuthService{userRepository: userRepository, throttle: throttle}
+}
+
+// Actions rate-limited by ThrottleEmailRequest
+const (
+	EmailActionForgotPassword     = "forgot_password"
+	EmailActionResendVerification = "resend_verification"
+)
+
+// ThrottleEmailRequest rate-limits endpoints that send email, per address and per IP
+func (as *AuthService) ThrottleEmailRequest(action, email, ip string) error {
+	return as.throttle.AllowMessageRequest(action, email, ip)
 }
 
 func (as *AuthService) RegisterUser(input models.User) (*models.User, error) {
@@ -44,6 +57,15 @@ func (as *AuthService) RegisterUser(input models.User) (*models.User, error) {
 		return nil, errors.New("user with this email already exists")
 	}
 
+	// Language for notifications and emails; left empty to use the default
+	if input.PreferredLanguage != "" {
+		locale, err := templates.ValidateLocale(input.PreferredLanguage)
+		if err != nil {
+			return nil, err
+		}
+		input.PreferredLanguage = locale
+	}
+
 	// 2. Hash the password
 	hashedPassword, err := utils.HashPassword(input.Password)
 	if err != nil {
@@ -64,7 +86,7 @@ func (as *AuthService) RegisterUser(input models.User) (*models.User, error) {
 		Name:               input.Name,
 		Email:              input.Email,
 		Password:           hashedPassword,
-		Phone:              input.Phone,
+		Phone:              normalizedPhone(input.Phone),
 		Role:               input.Role,
 		AccountType:        input.AccountType,
 		Status:             "active",
@@ -72,6 +94,7 @@ func (as *AuthService) RegisterUser(input models.User) (*models.User, error) {
 		VerificationCode:   verificationToken,
 		VerificationExpiry: time.Now().Add(24 * time.Hour),
 		ProfilePhoto:       input.ProfilePhoto,
+		PreferredLanguage:  input.PreferredLanguage,
 		CreatedAt:          time.Now(),
 		UpdatedAt:          time.Now(),
 	}
@@ -96,7 +119,7 @@ func (as *AuthService) RegisterUser(input models.User) (*models.User, error) {
 	// 6. Send verification email (async)
 	go func() {
 		logrus.Infof("📧 [Email] Attempting to send verification email to %s", newUser.Email)
-		if err := utils.SendVerificationEmail(newUser.Email, newUser.Name, verificationToken); err != nil {
+		if err := utils.SendVerificationEmail(newUser.Email, newUser.Name, verificationToken, newUser.PreferredLanguage); err != nil {
 			logrus.Errorf("❌ [Email] Failed to send verification email: %v", err)
 		} else {
 			logrus.Infof("✅ [Email] Verification email sent successfully to %s", newUser.Email)
@@ -107,31 +130,43 @@ func (as *AuthService) RegisterUser(input models.User) (*models.User, error) {
 	return &newUser, nil
 }
 
-func (as *AuthService) LoginUser(email, password string) (string, *models.User, error) {
-	_, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+// LoginUser checks credentials. The caller opens a session with SessionService once any
+// further checks (e.g. email verification) pass. Repeated failures from the same account
+// or IP are slowed down and eventually locked out. For accounts with 2FA the failure count
+// is only cleared once TwoFactorService.VerifyChallenge accepts a code.
+func (as *AuthService) LoginUser(email, password, ip string) (*models.User, error) {
+	// 0. Refuse while the account or IP is backing off
+	if err := as.throttle.CheckLogin(email, ip); err != nil {
+		return nil, err
+	}
 
 	// 1. Find user by email
 	user, err := as.userRepository.FindUserByEmail(email)
 	if err != nil {
-		return "", nil, errors.New("user not found")
+		as.throttle.RecordLoginFailure(email, ip, nil)
+		return nil, errors.New("user not found")
 	}
 
-	// 2. Check password
-	if err := utils.CheckPasswordHash(password, user.Password); err != nil {
-		return "", nil, errors.New("invalid password")
+	// 2. Invited workers must set a password through their invite link first
+	if user.Status == "invited" {
+		return nil, errors.New("account not activated. Please use the invite link sent to you to set your password")
 	}
 
-	// 3. Generate JWT token
-	token, err := utils.GenerateToken(user.ID.Hex(), user.Email, user.Role, user.AccountType)
-	if err != nil {
-		logrus.Error("Error generating token: ", err)
-		return "", nil, err
+	// 3. Check password
+	if err := utils.CheckPasswordHash(password, user.Password); err != nil {
+		as.throttle.RecordLoginFailure(email, ip, &user.ID)
+		return nil, errors.New("invalid password")
+	}
+	if !user.TwoFactorEnabled {
+		as.throttle.RecordLoginSuccess(email)
 	}
 
-	// fmt.Println(user)
+	// 4. Suspended accounts can't start new sessions
+	if user.Status == "suspended" {
+		return nil, errors.New("account suspended")
+	}
 
-	return token, user, nil
+	return user, nil
 }
 
 func (as *AuthService) VerifyEmail(email, token string) error {
@@ -210,7 +245,7 @@ func (as *AuthService) ResendVerificationEmail(email string) error {
 
 	// 5. Send verification email (async)
 	go func() {
-		if err := utils.SendVerificationEmail(user.Email, user.Name, verificationToken); err != nil {
+		if err := utils.SendVerificationEmail(user.Email, user.Name, verificationToken, user.PreferredLanguage); err != nil {
 			logrus.Error("Failed to resend verification email: ", err)
 		}
 	}()
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/repositories"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TooManyAttemptsError is returned while a login or email request is throttled
+type TooManyAttemptsError struct {
+	RetryAfter time.Duration
+}
+
+func (e *TooManyAttemptsError) Error() string {
+	return fmt.Sprintf("too many attempts, try again in %s", e.RetryAfter.Round(time.Second))
+}
+
+// backoffPolicy decides how long a key is blocked after repeated failures.
+// After freeAttempts failures each further one blocks for baseDelay, doubling up to maxDelay;
+// at lockoutAfter failures the key is locked out for lockoutFor and the lockout is audited.
+type backoffPolicy struct {
+	freeAttempts int
+	baseDelay    time.Duration
+	maxDelay     time.Duration
+	lockoutAfter int
+	lockoutFor   time.Duration
+	window       time.Duration
+}
+
+var (
+	accountLoginPolicy = backoffPolicy{freeAttempts: 3, baseDelay: 2 * time.Second, maxDelay: 5 * time.Minute, lockoutAfter: 10, lockoutFor: 30 * time.Minute, window: time.Hour}
+	ipLoginPolicy      = backoffPolicy{freeAttempts: 20, baseDelay: time.Second, maxDelay: 5 * time.Minute, lockoutAfter: 100, lockoutFor: time.Hour, window: time.Hour}
+	// Counted per account across pre-auth tokens, so logging in again doesn't buy fresh guesses
+	twoFactorPolicy = backoffPolicy{freeAttempts: 5, baseDelay: 30 * time.Second, maxDelay: 15 * time.Minute, lockoutAfter: 10, lockoutFor: 12 * time.Hour, window: 24 * time.Hour}
+)
+
+// Rate limits for endpoints that send an email or SMS
+const (
+	MessageRequestsPerRecipient = 3
+	MessageRequestsPerIP        = 10
+	MessageRequestWindow        = time.Hour
+)
+
+// AuthThrottleService tracks failed logins per account and per IP, and rate-limits
+// auth endpoints that send an email or SMS
+type AuthThrottleService struct {
+	throttleRepo *repositories.AuthThrottleRepository
+	auditRepo    *repositories.AuditRepository
+}
+
+// NewAuthThrottleService creates a new AuthThrottleService instance
+func NewAuthThrottleService(throttleRepo *repositories.AuthThrottleRepository, auditRepo *repositories.AuditRepository) *AuthThrottleService {
+	return &AuthThrottleService{throttleRepo: throttleRepo, auditRepo: auditRepo}
+}
+
+func accountLoginKey(email string) string {
+	return "login:account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipLoginKey(ip string) string {
+	return "login:ip:" + ip
+}
+
+func twoFactorKey(userID primitive.ObjectID) string {
+	return "2fa:account:" + userID.Hex()
+}
+
+// CheckLogin rejects a login attempt while the account or the IP is blocked
+func (ts *AuthThrottleService) CheckLogin(email, ip string) error {
+	for _, key := range []string{accountLoginKey(email), ipLoginKey(ip)} {
+		throttle, err := ts.throttleRepo.FindThrottle(key)
+		if err != nil {
+			// Fail open: a throttle outage shouldn't lock everyone out
+			logrus.Warn("Failed to read login throttle: ", err)
+			continue
+		}
+		if throttle != nil && throttle.IsLocked() {
+			return &TooManyAttemptsError{RetryAfter: time.Until(*throttle.LockedUntil)}
+		}
+	}
+	return nil
+}
+
+// RecordLoginFailure counts a failed login against the account and the IP.
+// userID is nil when the email doesn't belong to anyone.
+func (ts *AuthThrottleService) RecordLoginFailure(email, ip string, userID *primitive.ObjectID) {
+	ts.recordFailure(accountLoginKey(email), accountLoginPolicy, func(count int) *models.AuditEvent {
+		return &models.AuditEvent{
+			Action:       models.AuditAccountLocked,
+			TargetUserID: userID,
+			IPAddress:    ip,
+			Details:      map[string]interface{}{"email": strings.ToLower(strings.TrimSpace(email)), "failed_attempts": count},
+		}
+	})
+	ts.recordFailure(ipLoginKey(ip), ipLoginPolicy, func(count int) *models.AuditEvent {
+		return &models.AuditEvent{
+			Action:    models.AuditIPLocked,
+			IPAddress: ip,
+			Details:   map[string]interface{}{"failed_attempts": count},
+		}
+	})
+}
+
+// RecordLoginSuccess clears the account's failure count. The IP count is left to expire
+// so one valid account can't be used to keep guessing others.
+func (ts *AuthThrottleService) RecordLoginSuccess(email string) {
+	if err := ts.throttleRepo.Reset(accountLoginKey(email)); err != nil {
+		logrus.Warn("Failed to reset login throttle: ", err)
+	}
+}
+
+// CheckSecondFactor rejects a 2FA code while the account is backing off from wrong codes
+func (ts *AuthThrottleService) CheckSecondFactor(userID primitive.ObjectID) error {
+	throttle, err := ts.throttleRepo.FindThrottle(twoFactorKey(userID))
+	if err != nil {
+		logrus.Warn("Failed to read 2FA throttle: ", err)
+		return nil
+	}
+	if throttle != nil && throttle.IsLocked() {
+		return &TooManyAttemptsError{RetryAfter: time.Until(*throttle.LockedUntil)}
+	}
+	return nil
+}
+
+// RecordSecondFactorFailure counts a wrong TOTP or recovery code against the account
+func (ts *AuthThrottleService) RecordSecondFactorFailure(userID primitive.ObjectID) {
+	ts.recordFailure(twoFactorKey(userID), twoFactorPolicy, func(count int) *models.AuditEvent {
+		return &models.AuditEvent{
+			Action:       models.AuditTwoFactorLocked,
+			TargetUserID: &userID,
+			Details:      map[string]interface{}{"failed_attempts": count},
+		}
+	})
+}
+
+// RecordSecondFactorSuccess clears the account's 2FA failure count
+func (ts *AuthThrottleService) RecordSecondFactorSuccess(userID primitive.ObjectID) {
+	if err := ts.throttleRepo.Reset(twoFactorKey(userID)); err != nil {
+		logrus.Warn("Failed to reset 2FA throttle: ", err)
+	}
+}
+
+// AllowMessageRequest rate-limits an action that sends a message, per recipient
+// (email address or phone number) and per IP
+func (ts *AuthThrottleService) AllowMessageRequest(action, recipient, ip string) error {
+	keys := map[string]int{
+		action + ":" + strings.ToLower(strings.TrimSpace(recipient)): MessageRequestsPerRecipient,
+		action + ":ip:" + ip: MessageRequestsPerIP,
+	}
+
+	for key, limit := range keys {
+		throttle, err := ts.throttleRepo.Increment(key, MessageRequestWindow)
+		if err != nil {
+			logrus.Warn("Failed to update rate limit: ", err)
+			continue
+		}
+		if throttle.Count > limit {
+			return &TooManyAttemptsError{RetryAfter: time.Until(throttle.ExpiresAt)}
+		}
+	}
+	return nil
+}
+
+func (ts *AuthThrottleService) recordFailure(key string, policy backoffPolicy, lockoutEvent func(count int) *models.AuditEvent) {
+	throttle, err := ts.throttleRepo.Increment(key, policy.window)
+	if err != nil {
+		logrus.Warn("Failed to record login failure: ", err)
+		return
+	}
+
+	delay := policy.delayFor(throttle.Count)
+	if delay == 0 {
+		return
+	}
+	if err := ts.throttleRepo.Lock(key, time.Now().Add(delay)); err != nil {
+		logrus.Warn("Failed to apply login backoff: ", err)
+		return
+	}
+
+	if throttle.Count >= policy.lockoutAfter {
+		event := lockoutEvent(throttle.Count)
+		event.SetDefaults()
+		event.Details["locked_for_minutes"] = int(delay.Minutes())
+		if err := ts.auditRepo.CreateEvent(event); err != nil {
+			logrus.Error("Failed to record lockout audit event: ", err)
+		}
+		logrus.Warnf("🔒 %s locked for %s after %d failed attempts", key, delay, throttle.Count)
+	}
+}
+
+// delayFor returns how long to block after the given number of failures
+func (p backoffPolicy) delayFor(failures int) time.Duration {
+	if failures >= p.lockoutAfter {
+		return p.lockoutFor
+	}
+	if failures <= p.freeAttempts {
+		return 0
+	}
+	delay := time.Duration(float64(p.baseDelay) * math.Pow(2, float64(failures-p.freeAttempts-1)))
+	if delay > p.maxDelay {
+		return p.maxDelay
+	}
+	return delay
+}
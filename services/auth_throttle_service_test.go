@@ -0,0 +1,32 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffPolicyDelayFor(t *testing.T) {
+	cases := []struct {
+		name     string
+		policy   backoffPolicy
+		failures int
+		want     time.Duration
+	}{
+		{"login free attempt", accountLoginPolicy, 3, 0},
+		{"login first backoff", accountLoginPolicy, 4, 2 * time.Second},
+		{"login doubling", accountLoginPolicy, 6, 8 * time.Second},
+		{"login before lockout", accountLoginPolicy, 9, 64 * time.Second},
+		{"login lockout", accountLoginPolicy, 10, 30 * time.Minute},
+		{"2FA free attempts", twoFactorPolicy, 5, 0},
+		{"2FA first backoff", twoFactorPolicy, 6, 30 * time.Second},
+		{"2FA doubling", twoFactorPolicy, 9, 4 * time.Minute},
+		{"2FA lockout", twoFactorPolicy, 10, 12 * time.Hour},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.policy.delayFor(tc.failures); got != tc.want {
+				t.Errorf("delayFor(%d) = %s, want %s", tc.failures, got, tc.want)
+			}
+		})
+	}
+}
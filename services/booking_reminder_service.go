@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/repositories"
+	"github.com/olabanji12-ojo/CarWashApp/services/events"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DefaultReminderOffsets are how long before a booking its customer is reminded
+var DefaultReminderOffsets = []time.Duration{24 * time.Hour, time.Hour}
+
+// BookingReminderService schedules "your wash is coming up" reminders as scheduled jobs
+// and sends them through the notification pipeline when they fall due.
+type BookingReminderService struct {
+	jobRepo             *repositories.ScheduledJobRepository
+	bookingRepo         *repositories.BookingRepository
+	carwashRepo         *repositories.CarWashRepository
+	notificationService *NotificationService
+	offsets             []time.Duration // longest first
+}
+
+// NewBookingReminderService creates a new BookingReminderService instance
+func NewBookingReminderService(
+	jobRepo *repositories.ScheduledJobRepository,
+	bookingRepo *repositories.BookingRepository,
+	carwashRepo *repositories.CarWashRepository,
+	notificationService *NotificationService,
+	offsets []time.Duration,
+) *BookingReminderService {
+	sorted := append([]time.Duration(nil), offsets...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] > sorted[j] })
+	return &BookingReminderService{
+		jobRepo:             jobRepo,
+		bookingRepo:         bookingRepo,
+		carwashRepo:         carwashRepo,
+		notificationService: notificationService,
+		offsets:             sorted,
+	}
+}
+
+// ScheduleReminders queues a reminder per offset. Offsets that have already passed are skipped,
+// and scheduling the same booking time again is a no-op.
+func (rs *BookingReminderService) ScheduleReminders(booking *models.Booking) {
+	now := time.Now()
+	for _, offset := range rs.offsets {
+		runAt := booking.BookingTime.Add(-offset)
+		if !runAt.After(now) {
+			continue
+		}
+
+		job := &models.ScheduledJob{
+			Kind:      models.JobKindBookingReminder,
+			Key:       fmt.Sprintf("%s:%s:%s:%d", models.JobKindBookingReminder, booking.ID.Hex(), offset, booking.BookingTime.Unix()),
+			SubjectID: booking.ID,
+			Params: map[string]string{
+				"offset":       offset.String(),
+				"booking_time": booking.BookingTime.UTC().Format(time.RFC3339),
+			},
+			RunAt: runAt,
+		}
+		if err := rs.jobRepo.Schedule(job); err != nil {
+			logrus.Errorf("Failed to schedule %s reminder for booking %s: %v", offset, booking.ID.Hex(), err)
+		}
+	}
+}
+
+// CancelReminders drops the reminders of a booking that haven't gone out yet
+func (rs *BookingReminderService) CancelReminders(bookingID primitive.ObjectID) {
+	cancelled, err := rs.jobRepo.CancelPending(models.JobKindBookingReminder, bookingID)
+	if err != nil {
+		logrus.Errorf("Failed to cancel reminders for booking %s: %v", bookingID.Hex(), err)
+		return
+	}
+	if cancelled > 0 {
+		logrus.Infof("⏰ Cancelled %d reminder(s) for booking %s", cancelled, bookingID.Hex())
+	}
+}
+
+// RescheduleReminders replaces a booking's reminders after its time changed
+func (rs *BookingReminderService) RescheduleReminders(booking *models.Booking) {
+	rs.CancelReminders(booking.ID)
+	rs.ScheduleReminders(booking)
+}
+
+// Subscribe keeps reminders in step with bookings: they are scheduled when a booking is made,
+// moved when it is rescheduled and dropped once it is cancelled or completed
+func (rs *BookingReminderService) Subscribe(bus events.Bus) {
+	events.On(bus, func(e events.BookingCreated) {
+		rs.ScheduleReminders(e.Booking)
+	})
+	events.On(bus, func(e events.BookingStatusChanged) {
+		if e.Booking.Status == "cancelled" || e.Booking.Status == "completed" {
+			rs.CancelReminders(e.Booking.ID)
+		}
+	})
+	events.On(bus, func(e events.BookingRescheduled) {
+		if e.Booking.Status != "cancelled" && e.Booking.Status != "completed" {
+			rs.RescheduleReminders(e.Booking)
+		}
+	})
+}
+
+// HandleReminder is the scheduler handler for booking reminders. The booking is read again
+// first, so a reminder that outlived a cancellation or reschedule is dropped quietly.
+func (rs *BookingReminderService) HandleReminder(ctx context.Context, job *models.ScheduledJob) error {
+	booking, err := rs.bookingRepo.GetBookingByID(job.SubjectID)
+	if err != nil {
+		return err
+	}
+
+	if booking.Status == "cancelled" || booking.Status == "completed" {
+		return nil
+	}
+	if booking.BookingTime.UTC().Format(time.RFC3339) != job.Params["booking_time"] {
+		return nil // rescheduled; the new time has its own reminders
+	}
+
+	lead := time.Until(booking.BookingTime)
+	if lead <= 0 {
+		return nil
+	}
+	// After downtime several reminders can fall due at once; only the closest one is sent
+	offset, _ := time.ParseDuration(job.Params["offset"])
+	for _, other := range rs.offsets {
+		if other < offset && lead <= other {
+			return nil
+		}
+	}
+
+	carwashName := "the carwash"
+	if carwash, err := rs.carwashRepo.GetCarwashByID(booking.CarwashID); err == nil {
+		carwashName = carwash.Name
+	}
+	return rs.notificationService.SendBookingReminder(booking, carwashName, lead)
+}
+
+// reminderLead splits the time left before a booking into whole hours, or minutes when under an hour
+func reminderLead(lead time.Duration) (hours, minutes int) {
+	if lead >= time.Hour {
+		return int(math.Round(lead.Hours())), 0
+	}
+	return 0, int(math.Max(1, math.Round(lead.Minutes())))
+}
@@ -4,12 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"strings"
 
 	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/policy"
 	"github.com/olabanji12-ojo/CarWashApp/repositories"
+	"github.com/olabanji12-ojo/CarWashApp/services/events"
+	"github.com/olabanji12-ojo/CarWashApp/services/geocoding"
+	"github.com/olabanji12-ojo/CarWashApp/services/routing"
+	"github.com/olabanji12-ojo/CarWashApp/services/tracking"
 	"github.com/olabanji12-ojo/CarWashApp/utils"
 	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson"
@@ -20,15 +26,44 @@ type BookingService struct {
 	bookingRepository   repositories.BookingRepository
 	carWashRepository   repositories.CarWashRepository
 	userRepository      repositories.UserRepository
+	carRepository       repositories.CarRepository
 	notificationService *NotificationService
+	tracker             tracking.Broker
+	router              routing.Router
+	historyRepository   repositories.LocationHistoryRepository
+	trackingTokenRepo   repositories.TrackingTokenRepository
+	authz               *policy.Policy
+	bus                 events.Bus
 }
 
-func NewBookingService(bookingRepository repositories.BookingRepository, carWashRepository repositories.CarWashRepository, userRepository repositories.UserRepository, notificationService *NotificationService) *BookingService {
+// arrivalAlertMinutes is how close (by ETA) the worker must be before the customer is told they're almost there
+const arrivalAlertMinutes = 5
+
+// onSiteRadiusKm is how close a ping must be to the customer's location to count as "on site"
+const onSiteRadiusKm = 0.1
+
+// workerSteps are the statuses the assigned worker moves a booking through, in order
+var workerSteps = []string{"confirmed", "in_progress", "arrived", "completed"}
+
+// ErrInvalidTrackedStatus is returned when the assigned worker tries anything but their next steps
+var ErrInvalidTrackedStatus = errors.New("a worker can only move a booking forward to in_progress, arrived or completed")
+
+// ErrInvalidBookingStatus is returned for a status that isn't one of models.BookingStatuses
+var ErrInvalidBookingStatus = errors.New("invalid booking status")
+
+func NewBookingService(bookingRepository repositories.BookingRepository, carWashRepository repositories.CarWashRepository, userRepository repositories.UserRepository, carRepository repositories.CarRepository, notificationService *NotificationService, tracker tracking.Broker, router routing.Router, historyRepository repositories.LocationHistoryRepository, trackingTokenRepo repositories.TrackingTokenRepository, authz *policy.Policy, bus events.Bus) *BookingService {
 	return &BookingService{
 		bookingRepository:   bookingRepository,
 		carWashRepository:   carWashRepository,
 		userRepository:      userRepository,
+		carRepository:       carRepository,
 		notificationService: notificationService,
+		tracker:             tracker,
+		router:              router,
+		historyRepository:   historyRepository,
+		trackingTokenRepo:   trackingTokenRepo,
+		authz:               authz,
+		bus:                 bus,
 	}
 }
 
@@ -54,76 +89,10 @@ func (bs *BookingService) CreateBooking(userID string, input models.Booking) (*m
 		return nil, errors.New("carwash not found")
 	}
 
-	// Get the weekday from booking time (use full name to match open_hours keys)
-	weekday := strings.ToLower(input.BookingTime.Weekday().String()) // "monday", "tuesday", etc
-
-	logrus.Infof("Checking open hours for carwash %s on %s (derived from %v)", input.CarwashID, weekday, input.BookingTime)
-	logrus.Infof("Carwash OpenHours keys: %v", carwash.OpenHours)
-
-	timeRange, ok := carwash.OpenHours[weekday]
-	if !ok {
-		logrus.Warnf("Carwash %s is not open on %s. Available days: %v", input.CarwashID, weekday, carwash.OpenHours)
-		return nil, errors.New("carwash is not open on this day")
-	}
-
-	// Convert time strings to time.Time
-	layout := "15:04"
-	bookingHour := input.BookingTime.Format(layout)
-
-	bookingParsed, err := time.Parse(layout, bookingHour)
-	if err != nil {
-		return nil, errors.New("invalid booking time format")
-	}
-
-	startParsed, err := time.Parse(layout, timeRange.Start)
+	// Step 4: The slot must be open, and not already taken by the user or filled up
+	bookingsForDay, err := bs.checkSlot(carwash, ownerID, input.BookingTime, primitive.NilObjectID)
 	if err != nil {
-		return nil, errors.New("invalid start time format")
-	}
-
-	endParsed, err := time.Parse(layout, timeRange.End)
-	if err != nil {
-		return nil, errors.New("invalid end time format")
-	}
-
-	// Compare booking time with open hours
-	if bookingParsed.Before(startParsed) || bookingParsed.After(endParsed) {
-		return nil, errors.New("booking time is outside of open hours")
-	}
-
-	// Step 4: Check for existing bookings on same date/time
-	bookingsForDay, err := bs.bookingRepository.GetBookingsByDate(input.CarwashID, input.BookingTime)
-	if err != nil {
-		return nil, errors.New("could not fetch bookings for that time")
-	}
-
-	maxCars := carwash.MaxCarsPerSlot
-	if maxCars <= 0 {
-		maxCars = 1
-	}
-
-	currentCarsInSlot := 0
-	inputTimeStr := input.BookingTime.UTC().Truncate(time.Minute).Format("2006-01-02 15:04")
-	logrus.Infof("[CreateDebug] User: %s, Slot: %s, Max: %d", userID, inputTimeStr, maxCars)
-
-	for _, b := range bookingsForDay {
-		bTimeStr := b.BookingTime.UTC().Truncate(time.Minute).Format("2006-01-02 15:04")
-
-		// If same user already has a pending/confirmed booking for this slot, block them
-		if b.UserID.Hex() == userID && bTimeStr == inputTimeStr && b.Status != "cancelled" && b.Status != "completed" {
-			return nil, errors.New("you already have a booking for this time slot")
-		}
-
-		if b.Status != "confirmed" {
-			continue
-		}
-
-		if bTimeStr == inputTimeStr {
-			currentCarsInSlot++
-		}
-	}
-
-	if currentCarsInSlot >= maxCars {
-		return nil, errors.New("selected time slot is already fully booked")
+		return nil, err
 	}
 
 	// Step 5: Create new booking
@@ -188,27 +157,17 @@ func (bs *BookingService) CreateBooking(userID string, input models.Booking) (*m
 		return nil, err
 	}
 
-	// Step 7: Trigger notifications (Both Business Owner & Customer)
-	go func() {
-		user, err := bs.userRepository.FindUserByID(ownerID)
-		if err == nil && bs.notificationService != nil {
-			// Notify Business Owner (In-app + Email)
-			if !carwash.OwnerID.IsZero() {
-				bs.notificationService.SendNewBookingToBusiness(carwash.OwnerID, user.Name, "New Booking")
-			} else {
-				logrus.Warnf("Carwash %s has no OwnerID, cannot send notification", carwash.Name)
-			}
-
-			// Notify Customer (Email)
-			bookingTimeStr := newBooking.BookingTime.Format("Jan 2, 2006 at 3:04 PM")
-			err = utils.SendBookingConfirmationEmail(user.Email, user.Name, carwash.Name, bookingTimeStr)
-			if err != nil {
-				logrus.Errorf("Failed to send booking confirmation email to customer: %v", err)
-			} else {
-				logrus.Infof("Booking confirmation email sent to customer: %s", user.Email)
-			}
-		}
-	}()
+	// Step 7: Let subscribers react (notifications, reminders, webhooks...)
+	var customerName string
+	if user, err := bs.userRepository.FindUserByID(ownerID); err == nil {
+		customerName = user.Name
+	}
+	bs.bus.Publish(events.BookingCreated{
+		Booking:      &newBooking,
+		Carwash:      carwash,
+		CustomerName: customerName,
+		OccurredAt:   time.Now(),
+	})
 
 	return &newBooking, nil
 
@@ -256,7 +215,34 @@ func (bs *BookingService) GetBookingsByCarwashID(carwashID string) ([]models.Boo
 	return bs.enrichBookingsWithCustomerDetails(bookings)
 }
 
+// ChangeBookingStatus sets a booking's status on behalf of the caller. Carwash staff who manage
+// bookings may set any status; the assigned worker only moves it forward like a tracking link does.
+func (bs *BookingService) ChangeBookingStatus(requester policy.Subject, bookingID, newStatus, verificationCode string) error {
+	if !models.IsBookingStatus(newStatus) {
+		return ErrInvalidBookingStatus
+	}
+	if bs.authz.Allowed(requester, bookingID, bs.authz.CanOnBooking(models.PermManageBookings)) {
+		return bs.UpdateBookingStatus(bookingID, newStatus, verificationCode)
+	}
+	if !bs.authz.Allowed(requester, bookingID, bs.authz.AssignedWorker) {
+		return policy.ErrForbidden
+	}
+
+	objID, err := primitive.ObjectIDFromHex(bookingID)
+	if err != nil {
+		return errors.New("invalid booking ID")
+	}
+	booking, err := bs.bookingRepository.GetBookingByID(objID)
+	if err != nil {
+		return errors.New("booking not found")
+	}
+	return bs.UpdateTrackedStatus(booking, newStatus, verificationCode)
+}
+
 func (bs *BookingService) UpdateBookingStatus(bookingID string, newStatus string, verificationCode string) error {
+	if !models.IsBookingStatus(newStatus) {
+		return ErrInvalidBookingStatus
+	}
 	objID, err := primitive.ObjectIDFromHex(bookingID)
 	if err != nil {
 		return errors.New("invalid booking ID")
@@ -285,6 +271,15 @@ func (bs *BookingService) UpdateBookingStatus(bookingID string, newStatus string
 		return err
 	}
 
+	previousStatus := booking.Status
+	booking.Status = newStatus
+	bs.publishTracking(bs.statusEvent(booking))
+
+	// The worker's link stops working once the job is over
+	if newStatus == "completed" || newStatus == "cancelled" {
+		bs.revokeWriteLinks(objID)
+	}
+
 	// Step 2: Generate Verification Code if confirmed and missing
 	if newStatus == "confirmed" && booking.VerificationCode == "" {
 		code, _ := utils.GenerateNumericCode(4)
@@ -292,38 +287,28 @@ func (bs *BookingService) UpdateBookingStatus(bookingID string, newStatus string
 		bs.bookingRepository.UpdateBooking(objID, updates)
 	}
 
-	// Trigger Notifications (Async)
-	go func() {
-		if bs.notificationService == nil {
-			return
-		}
-
-		// Fetch carwash name
-		var carwashName string
-		cw, err := bs.carWashRepository.GetCarwashByID(booking.CarwashID)
-		if err == nil {
-			carwashName = cw.Name
-		} else {
-			carwashName = "The Carwash"
-		}
-
-		if newStatus == "confirmed" {
-			// In-App + Email (Hybrid Strategy)
-			bs.notificationService.SendBookingAccepted(booking, carwashName)
-		} else if newStatus == "cancelled" {
-			bs.notificationService.SendBookingRejected(booking, "Cancelled by business")
-		} else if newStatus == "completed" {
-			// In-App Only (Hybrid Strategy)
-			title := "Wash Completed"
-			message := fmt.Sprintf("Your service at %s is marked as completed. Please rate your experience!", carwashName)
-			// false = No Email
-			bs.notificationService.CreateNotification(booking.UserID, title, message, "booking", false)
-		}
-	}()
+	if previousStatus != newStatus {
+		bs.publishStatusChanged(booking, previousStatus, events.SourceStatusUpdate)
+	}
 
 	return nil
 }
 
+// publishStatusChanged tells subscribers a booking's status changed
+func (bs *BookingService) publishStatusChanged(booking *models.Booking, previousStatus, source string) {
+	carwashName := "The Carwash"
+	if cw, err := bs.carWashRepository.GetCarwashByID(booking.CarwashID); err == nil {
+		carwashName = cw.Name
+	}
+	bs.bus.Publish(events.BookingStatusChanged{
+		Booking:        booking,
+		PreviousStatus: previousStatus,
+		Source:         source,
+		CarwashName:    carwashName,
+		OccurredAt:     time.Now(),
+	})
+}
+
 func (bs *BookingService) CancelBooking(bookingID string) error {
 	objID, err := primitive.ObjectIDFromHex(bookingID)
 	if err != nil {
@@ -351,7 +336,16 @@ func (bs *BookingService) CancelBooking(bookingID string) error {
 		return errors.New("cancellation not allowed within 24 hours of appointment")
 	}
 
-	return bs.bookingRepository.CancelBooking(objID)
+	if err := bs.bookingRepository.CancelBooking(objID); err != nil {
+		return err
+	}
+
+	previousStatus := booking.Status
+	booking.Status = "cancelled"
+	bs.publishTracking(bs.statusEvent(booking))
+	bs.revokeWriteLinks(objID)
+	bs.publishStatusChanged(booking, previousStatus, events.SourceCancellation)
+	return nil
 }
 
 func (bs *BookingService) GetBookingsByDate(carwashID string, date time.Time) ([]models.Booking, error) {
@@ -363,7 +357,9 @@ func (bs *BookingService) GetBookingsByDate(carwashID string, date time.Time) ([
 	return bs.bookingRepository.GetBookingsByDate(objID, date)
 }
 
-func (bs *BookingService) UpdateBooking(userID, bookingID string, updates map[string]interface{}) error {
+// UpdateBooking edits a booking's time, notes, address note or car. A new time must be a free,
+// open slot like a new booking's; a new car must be the customer's.
+func (bs *BookingService) UpdateBooking(userID, bookingID string, input map[string]interface{}) error {
 	_, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
 		return errors.New("invalid user ID")
@@ -374,12 +370,163 @@ func (bs *BookingService) UpdateBooking(userID, bookingID string, updates map[st
 		return errors.New("invalid booking ID")
 	}
 
-	// Optional: check ownership here if needed
+	// Keep the booking as it was, to validate the edit and tell subscribers what changed
+	before, err := bs.bookingRepository.GetBookingByID(bookingObjID)
+	if err != nil {
+		return errors.New("booking not found")
+	}
+	if before.Status == "completed" || before.Status == "cancelled" {
+		return errors.New("a completed or cancelled booking can't be changed")
+	}
+
+	// Status, worker, carwash and customer change through their own flows (CancelBooking,
+	// UpdateBookingStatus, assignment), never through an edit
+	updates := bson.M{}
+	for field, value := range input {
+		switch field {
+		case "notes", "address_note":
+			text, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("%s must be a string", field)
+			}
+			updates[field] = text
+		case "booking_time":
+			// A new booking time arrives as an RFC 3339 string; store it as a date
+			str, _ := value.(string)
+			bookingTime, err := time.Parse(time.RFC3339, str)
+			if err != nil {
+				return errors.New("booking_time must be an RFC 3339 timestamp")
+			}
+			if !bookingTime.Equal(before.BookingTime) {
+				carwash, err := bs.carWashRepository.GetCarwashByID(before.CarwashID)
+				if err != nil {
+					return errors.New("carwash not found")
+				}
+				if _, err := bs.checkSlot(carwash, before.UserID, bookingTime, before.ID); err != nil {
+					return err
+				}
+			}
+			updates[field] = bookingTime
+		case "car_id":
+			str, _ := value.(string)
+			carID, err := primitive.ObjectIDFromHex(str)
+			if err != nil {
+				return errors.New("invalid car ID")
+			}
+			car, err := bs.carRepository.GetCarByID(carID)
+			if err != nil || car.OwnerID != before.UserID {
+				return errors.New("car not found")
+			}
+			updates[field] = carID
+		default:
+			return fmt.Errorf("%s cannot be changed", field)
+		}
+	}
+	if len(updates) == 0 {
+		return errors.New("no valid fields to update")
+	}
 
 	// Add updatedAt
 	updates["updated_at"] = time.Now()
 
-	return bs.bookingRepository.UpdateBooking(bookingObjID, bson.M(updates))
+	if err := bs.bookingRepository.UpdateBooking(bookingObjID, updates); err != nil {
+		return err
+	}
+
+	booking, err := bs.bookingRepository.GetBookingByID(bookingObjID)
+	if err != nil {
+		logrus.Errorf("Failed to reload booking %s after update: %v", bookingID, err)
+		return nil
+	}
+	if !booking.BookingTime.Equal(before.BookingTime) {
+		bs.bus.Publish(events.BookingRescheduled{
+			Booking:             booking,
+			PreviousBookingTime: before.BookingTime,
+			OccurredAt:          time.Now(),
+		})
+	}
+	return nil
+}
+
+// checkSlot checks that a customer can take a slot: it is in the future and within the carwash's
+// open hours, the customer has no other booking then, and the slot isn't full. exclude is a
+// booking being moved, which doesn't count against its new slot. Returns the carwash's bookings that day.
+func (bs *BookingService) checkSlot(carwash *models.Carwash, customerID primitive.ObjectID, bookingTime time.Time, exclude primitive.ObjectID) ([]models.Booking, error) {
+	if !bookingTime.After(time.Now()) {
+		return nil, errors.New("booking time must be in the future")
+	}
+
+	// Get the weekday from booking time (use full name to match open_hours keys)
+	weekday := strings.ToLower(bookingTime.Weekday().String()) // "monday", "tuesday", etc
+
+	logrus.Infof("Checking open hours for carwash %s on %s (derived from %v)", carwash.ID.Hex(), weekday, bookingTime)
+
+	timeRange, ok := carwash.OpenHours[weekday]
+	if !ok {
+		logrus.Warnf("Carwash %s is not open on %s. Available days: %v", carwash.ID.Hex(), weekday, carwash.OpenHours)
+		return nil, errors.New("carwash is not open on this day")
+	}
+
+	// Convert time strings to time.Time
+	layout := "15:04"
+	bookingParsed, err := time.Parse(layout, bookingTime.Format(layout))
+	if err != nil {
+		return nil, errors.New("invalid booking time format")
+	}
+
+	startParsed, err := time.Parse(layout, timeRange.Start)
+	if err != nil {
+		return nil, errors.New("invalid start time format")
+	}
+
+	endParsed, err := time.Parse(layout, timeRange.End)
+	if err != nil {
+		return nil, errors.New("invalid end time format")
+	}
+
+	// Compare booking time with open hours
+	if bookingParsed.Before(startParsed) || bookingParsed.After(endParsed) {
+		return nil, errors.New("booking time is outside of open hours")
+	}
+
+	// Check for existing bookings on same date/time
+	bookingsForDay, err := bs.bookingRepository.GetBookingsByDate(carwash.ID, bookingTime)
+	if err != nil {
+		return nil, errors.New("could not fetch bookings for that time")
+	}
+
+	maxCars := carwash.MaxCarsPerSlot
+	if maxCars <= 0 {
+		maxCars = 1
+	}
+
+	currentCarsInSlot := 0
+	inputTimeStr := bookingTime.UTC().Truncate(time.Minute).Format("2006-01-02 15:04")
+
+	for _, b := range bookingsForDay {
+		if b.ID == exclude {
+			continue
+		}
+		bTimeStr := b.BookingTime.UTC().Truncate(time.Minute).Format("2006-01-02 15:04")
+
+		// If same user already has a pending/confirmed booking for this slot, block them
+		if b.UserID == customerID && bTimeStr == inputTimeStr && b.Status != "cancelled" && b.Status != "completed" {
+			return nil, errors.New("you already have a booking for this time slot")
+		}
+
+		if b.Status != "confirmed" {
+			continue
+		}
+
+		if bTimeStr == inputTimeStr {
+			currentCarsInSlot++
+		}
+	}
+
+	if currentCarsInSlot >= maxCars {
+		return nil, errors.New("selected time slot is already fully booked")
+	}
+	return bookingsForDay, nil
 }
 
 // Get Bookings with filter
@@ -496,5 +643,342 @@ func (bs *BookingService) UpdateWorkerLocation(id string, lat, lng float64) erro
 		"updated_at":      time.Now(),
 	}
 
-	return bs.bookingRepository.UpdateBooking(bookingID, updates)
+	if err := bs.bookingRepository.UpdateBooking(bookingID, updates); err != nil {
+		return err
+	}
+
+	// Push the new position to anyone watching the tracking page
+	booking, err := bs.bookingRepository.GetBookingByID(bookingID)
+	if err != nil {
+		logrus.Warnf("[Tracking] Location saved but booking %s could not be reloaded: %v", id, err)
+		return nil
+	}
+
+	// Keep the full trail for disputes/mileage; losing a ping shouldn't fail the update
+	ping := models.LocationPing{
+		Meta:       models.LocationPingMeta{BookingID: booking.ID, WorkerID: booking.WorkerID},
+		Location:   location,
+		RecordedAt: time.Now(),
+	}
+	if err := bs.historyRepository.AppendPing(ping); err != nil {
+		logrus.Warnf("[Tracking] Failed to record location history for booking %s: %v", id, err)
+	}
+
+	// The route estimate is the slow part, so it's made once for the ETA event and the arrival alert
+	estimate := bs.EstimateArrival(booking)
+	bs.publishTracking(bs.locationEvent(booking))
+	if estimate != nil {
+		bs.publishTracking(bs.etaEvent(booking, estimate))
+	}
+	bs.notifyIfArrivingSoon(booking, estimate)
+	bs.notifyIfArrived(booking)
+
+	return nil
+}
+
+// GetTravelPath returns the worker's recorded route for a booking as a GeoJSON LineString,
+// with the distance travelled and the time spent at the customer's location
+func (bs *BookingService) GetTravelPath(id string) (*models.TravelPath, error) {
+	bookingID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("invalid booking ID format")
+	}
+
+	booking, err := bs.bookingRepository.GetBookingByID(bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	pings, err := bs.historyRepository.GetPingsByBookingID(bookingID)
+	if err != nil {
+		return nil, errors.New("failed to load location history")
+	}
+
+	path := &models.TravelPath{
+		Type: "Feature",
+		Geometry: models.TravelPathGeometry{
+			Type:        "LineString",
+			Coordinates: [][]float64{},
+		},
+		Properties: models.TravelPathProperties{
+			BookingID: booking.ID.Hex(),
+			PingCount: len(pings),
+		},
+	}
+	if !booking.WorkerID.IsZero() {
+		path.Properties.WorkerID = booking.WorkerID.Hex()
+	}
+	if len(pings) == 0 {
+		return path, nil
+	}
+
+	var prev []float64
+	var lastOnSite *time.Time
+	for i := range pings {
+		coords := pings[i].Location.Coordinates
+		if len(coords) < 2 {
+			continue
+		}
+		path.Geometry.Coordinates = append(path.Geometry.Coordinates, coords)
+
+		// Coordinates are [longitude, latitude]
+		if prev != nil {
+			path.Properties.DistanceKm += utils.CalculateDistance(prev[1], prev[0], coords[1], coords[0])
+		}
+		prev = coords
+
+		if booking.UserLocation != nil && len(booking.UserLocation.Coordinates) >= 2 {
+			dest := booking.UserLocation.Coordinates
+			if utils.CalculateDistance(coords[1], coords[0], dest[1], dest[0]) <= onSiteRadiusKm {
+				recordedAt := pings[i].RecordedAt
+				if path.Properties.ArrivedAt == nil {
+					path.Properties.ArrivedAt = &recordedAt
+				}
+				lastOnSite = &recordedAt
+			}
+		}
+	}
+
+	startedAt := pings[0].RecordedAt
+	endedAt := pings[len(pings)-1].RecordedAt
+	path.Properties.StartedAt = &startedAt
+	path.Properties.EndedAt = &endedAt
+	path.Properties.DistanceKm = math.Round(path.Properties.DistanceKm*100) / 100
+
+	if path.Properties.ArrivedAt != nil && lastOnSite != nil {
+		path.Properties.TimeOnSiteMinutes = math.Round(lastOnSite.Sub(*path.Properties.ArrivedAt).Minutes()*10) / 10
+	}
+
+	return path, nil
+}
+
+// EstimateArrival computes the ETA from the worker's latest position to the customer's location.
+// Returns nil when either location is unknown (e.g. slot bookings or no ping yet).
+func (bs *BookingService) EstimateArrival(booking *models.Booking) *routing.Estimate {
+	if bs.router == nil || booking.WorkerLocation == nil || booking.UserLocation == nil ||
+		len(booking.WorkerLocation.Coordinates) < 2 || len(booking.UserLocation.Coordinates) < 2 {
+		return nil
+	}
+
+	// GeoJSON stores [longitude, latitude]
+	origin := geocoding.Location{Lat: booking.WorkerLocation.Coordinates[1], Lng: booking.WorkerLocation.Coordinates[0]}
+	destination := geocoding.Location{Lat: booking.UserLocation.Coordinates[1], Lng: booking.UserLocation.Coordinates[0]}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	estimate, err := bs.router.Estimate(ctx, origin, destination)
+	if err != nil {
+		logrus.Warnf("[Tracking] ETA estimate failed for booking %s: %v", booking.ID.Hex(), err)
+		return nil
+	}
+	return estimate
+}
+
+// notifyIfArrivingSoon tells the customer once when the worker is within arrivalAlertMinutes
+func (bs *BookingService) notifyIfArrivingSoon(booking *models.Booking, estimate *routing.Estimate) {
+	if bs.notificationService == nil || booking.ArrivalNotifiedAt != nil || booking.Status == "completed" || booking.Status == "cancelled" {
+		return
+	}
+
+	if estimate == nil || estimate.DurationMinutes > arrivalAlertMinutes {
+		return
+	}
+
+	// Only the request that flips the flag sends the notification
+	marked, err := bs.bookingRepository.MarkArrivalNotified(booking.ID)
+	if err != nil || !marked {
+		return
+	}
+
+	bs.notificationService.SendWorkerArrivingSoon(booking, estimate.DurationMinutes)
+}
+
+// notifyIfArrived tells the customer once when the worker's position is within onSiteRadiusKm of them
+func (bs *BookingService) notifyIfArrived(booking *models.Booking) {
+	if bs.notificationService == nil || booking.ArrivedNotifiedAt != nil || booking.Status == "completed" || booking.Status == "cancelled" {
+		return
+	}
+	if booking.WorkerLocation == nil || booking.UserLocation == nil ||
+		len(booking.WorkerLocation.Coordinates) < 2 || len(booking.UserLocation.Coordinates) < 2 {
+		return
+	}
+
+	worker, dest := booking.WorkerLocation.Coordinates, booking.UserLocation.Coordinates
+	if utils.CalculateDistance(worker[1], worker[0], dest[1], dest[0]) > onSiteRadiusKm {
+		return
+	}
+
+	marked, err := bs.bookingRepository.MarkArrivedNotified(booking.ID)
+	if err != nil || !marked {
+		return
+	}
+
+	bs.notificationService.SendWorkerArrived(booking)
+}
+
+// SubscribeTracking registers a live subscriber for a booking's tracking updates
+func (bs *BookingService) SubscribeTracking(bookingID string) (<-chan tracking.Event, func()) {
+	return bs.tracker.Subscribe(bookingID)
+}
+
+// TrackingSnapshot builds the current tracking state of a booking, sent when a client first connects
+func (bs *BookingService) TrackingSnapshot(booking *models.Booking) tracking.Event {
+	return bs.statusEvent(booking)
+}
+
+// publishTracking pushes a tracking event for the booking. Failures are logged, never returned,
+// so a broken stream can't fail the write that triggered it.
+func (bs *BookingService) publishTracking(event tracking.Event) {
+	if bs.tracker == nil {
+		return
+	}
+
+	if err := bs.tracker.Publish(context.Background(), event); err != nil {
+		logrus.Warnf("[Tracking] Failed to publish %s event for booking %s: %v", event.Type, event.BookingID, err)
+	}
+}
+
+// statusEvent carries the whole tracking state: status, worker location and ETA
+func (bs *BookingService) statusEvent(booking *models.Booking) tracking.Event {
+	event := bs.locationEvent(booking)
+	event.Type = tracking.EventStatus
+	if estimate := bs.EstimateArrival(booking); estimate != nil {
+		event.ETAMinutes = &estimate.DurationMinutes
+		event.DistanceKm = &estimate.DistanceKm
+	}
+	return event
+}
+
+// locationEvent carries the worker's latest position
+func (bs *BookingService) locationEvent(booking *models.Booking) tracking.Event {
+	event := tracking.Event{
+		Type:      tracking.EventLocation,
+		BookingID: booking.ID.Hex(),
+		Status:    booking.Status,
+		Timestamp: time.Now(),
+	}
+	if booking.WorkerLocation != nil && len(booking.WorkerLocation.Coordinates) >= 2 {
+		event.Location = &tracking.Point{Lat: booking.WorkerLocation.Coordinates[1], Lng: booking.WorkerLocation.Coordinates[0]}
+	}
+	return event
+}
+
+// etaEvent carries a fresh arrival estimate
+func (bs *BookingService) etaEvent(booking *models.Booking, estimate *routing.Estimate) tracking.Event {
+	return tracking.Event{
+		Type:       tracking.EventETA,
+		BookingID:  booking.ID.Hex(),
+		Status:     booking.Status,
+		ETAMinutes: &estimate.DurationMinutes,
+		DistanceKm: &estimate.DistanceKm,
+		Timestamp:  time.Now(),
+	}
+}
+
+// IssueTrackingLink creates a share token for a booking's tracking page.
+// Read links can be issued to the customer, the assigned worker or the carwash owner;
+// write links (location/status updates) only to the assigned worker.
+// The raw token is returned once and never stored.
+func (bs *BookingService) IssueTrackingLink(bookingID string, requester policy.Subject, scope string) (string, *models.TrackingToken, error) {
+	if scope == "" {
+		scope = models.TrackingScopeRead
+	}
+	if scope != models.TrackingScopeRead && scope != models.TrackingScopeWrite {
+		return "", nil, errors.New("scope must be 'read' or 'write'")
+	}
+
+	objID, err := primitive.ObjectIDFromHex(bookingID)
+	if err != nil {
+		return "", nil, errors.New("invalid booking ID")
+	}
+
+	booking, err := bs.bookingRepository.GetBookingByID(objID)
+	if err != nil {
+		return "", nil, errors.New("booking not found")
+	}
+	if booking.Status == "completed" || booking.Status == "cancelled" {
+		return "", nil, errors.New("booking is no longer active")
+	}
+
+	if scope == models.TrackingScopeWrite {
+		err = bs.authz.Authorize(requester, bookingID, bs.authz.AssignedWorker)
+	} else {
+		err = bs.authz.Authorize(requester, bookingID, bs.authz.BookingOwner, bs.authz.AssignedWorker, bs.authz.CanOnBooking(models.PermManageBookings))
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	rawToken, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return "", nil, err
+	}
+
+	token := &models.TrackingToken{
+		BookingID: booking.ID,
+		IssuedTo:  requester.UserID,
+		TokenHash: utils.HashToken(rawToken),
+		Scope:     scope,
+	}
+	token.SetDefaults()
+
+	if err := bs.trackingTokenRepo.CreateToken(token); err != nil {
+		return "", nil, errors.New("failed to create tracking link")
+	}
+
+	return rawToken, token, nil
+}
+
+// ResolveTrackingLink returns the booking a share token points to, if the token grants the scope
+func (bs *BookingService) ResolveTrackingLink(rawToken, scope string) (*models.Booking, error) {
+	if rawToken == "" {
+		return nil, errors.New("tracking link is invalid or expired")
+	}
+
+	token, err := bs.trackingTokenRepo.FindActiveToken(utils.HashToken(rawToken))
+	if err != nil {
+		return nil, err
+	}
+	if !token.Allows(scope) {
+		return nil, errors.New("this tracking link is read-only")
+	}
+
+	booking, err := bs.bookingRepository.GetBookingByID(token.BookingID)
+	if err != nil {
+		return nil, errors.New("booking not found")
+	}
+
+	if scope == models.TrackingScopeWrite && (booking.Status == "completed" || booking.Status == "cancelled") {
+		return nil, errors.New("booking is no longer active")
+	}
+
+	return booking, nil
+}
+
+// UpdateTrackedStatus moves a booking forward for the assigned worker, from the app or their write
+// link. Confirming, cancelling or going back a step is left to the carwash and the customer.
+func (bs *BookingService) UpdateTrackedStatus(booking *models.Booking, newStatus, verificationCode string) error {
+	from, to := workerStep(booking.Status), workerStep(newStatus)
+	if from < 0 || to <= from {
+		return ErrInvalidTrackedStatus
+	}
+	return bs.UpdateBookingStatus(booking.ID.Hex(), newStatus, verificationCode)
+}
+
+// workerStep is the position of status in workerSteps, or -1
+func workerStep(status string) int {
+	for i, step := range workerSteps {
+		if step == status {
+			return i
+		}
+	}
+	return -1
+}
+
+// revokeWriteLinks invalidates the worker's tracking links. Best effort: tokens also expire on their own.
+func (bs *BookingService) revokeWriteLinks(bookingID primitive.ObjectID) {
+	if err := bs.trackingTokenRepo.RevokeTokensByBookingID(bookingID, models.TrackingScopeWrite); err != nil {
+		logrus.Warnf("[Tracking] Failed to revoke write links for booking %s: %v", bookingID.Hex(), err)
+	}
 }
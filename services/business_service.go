@@ -0,0 +1,365 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/policy"
+	"github.com/olabanji12-ojo/CarWashApp/repositories"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BranchReport is the booking and revenue summary of one branch
+type BranchReport struct {
+	CarwashID     primitive.ObjectID `json:"carwash_id"`
+	Name          string             `json:"name"`
+	Bookings      map[string]int     `json:"bookings"` // by status
+	TotalBookings int                `json:"total_bookings"`
+	Revenue       float64            `json:"revenue"`
+}
+
+// BusinessReport sums BranchReports across the branches the caller can see
+type BusinessReport struct {
+	BusinessID    primitive.ObjectID `json:"business_id"`
+	From          string             `json:"from,omitempty"`
+	To            string             `json:"to,omitempty"`
+	Branches      []BranchReport     `json:"branches"`
+	Bookings      map[string]int     `json:"bookings"`
+	TotalBookings int                `json:"total_bookings"`
+	Revenue       float64            `json:"revenue"`
+}
+
+// BusinessService manages businesses and their carwash branches.
+// Owners see every branch; staff only see the branch they work at.
+type BusinessService struct {
+	businessRepo *repositories.BusinessRepository
+	carwashRepo  *repositories.CarWashRepository
+	bookingRepo  *repositories.BookingRepository
+	workerRepo   *repositories.WorkerRepository
+	userRepo     *repositories.UserRepository
+	authz        *policy.Policy
+}
+
+// NewBusinessService creates a new BusinessService instance
+func NewBusinessService(
+	businessRepo *repositories.BusinessRepository,
+	carwashRepo *repositories.CarWashRepository,
+	bookingRepo *repositories.BookingRepository,
+	workerRepo *repositories.WorkerRepository,
+	userRepo *repositories.UserRepository,
+	authz *policy.Policy,
+) *BusinessService {
+	return &BusinessService{
+		businessRepo: businessRepo,
+		carwashRepo:  carwashRepo,
+		bookingRepo:  bookingRepo,
+		workerRepo:   workerRepo,
+		userRepo:     userRepo,
+		authz:        authz,
+	}
+}
+
+// EnsureBusiness returns the owner's business, creating it on first use.
+// Carwashes the owner created before businesses existed become its branches.
+func (bs *BusinessService) EnsureBusiness(owner *models.User, name string) (*models.Business, error) {
+	business, err := bs.businessRepo.FindBusinessByOwnerID(owner.ID)
+	if err != nil {
+		if name == "" {
+			name = owner.Name
+		}
+		business = &models.Business{
+			OwnerID: owner.ID,
+			Name:    name,
+			Email:   owner.Email,
+			Phone:   owner.Phone,
+		}
+		business.SetDefaults()
+		if err := business.Validate(); err != nil {
+			return nil, err
+		}
+		if err := bs.businessRepo.CreateBusiness(business); err != nil {
+			logrus.Error("Failed to create business: ", err)
+			return nil, errors.New("failed to create business")
+		}
+		logrus.Infof("🏢 Created business %s for owner %s", business.ID.Hex(), owner.ID.Hex())
+	}
+
+	if err := bs.carwashRepo.AttachOwnerCarwashes(owner.ID, business.ID); err != nil {
+		logrus.Error("Failed to attach carwashes to business: ", err)
+	}
+	if err := bs.syncBranchList(business); err != nil {
+		logrus.Error("Failed to update the branch list of business: ", err)
+	}
+	return business, nil
+}
+
+// BackfillBranchLists fills in the branch list of businesses created before they kept one.
+// Only carwashes the business owner owns are listed. Safe to run on every start.
+func (bs *BusinessService) BackfillBranchLists() (int, error) {
+	businesses, err := bs.businessRepo.FindBusinessesWithoutBranchList()
+	if err != nil {
+		return 0, err
+	}
+	for i := range businesses {
+		if err := bs.syncBranchList(&businesses[i]); err != nil {
+			return i, err
+		}
+	}
+	if len(businesses) > 0 {
+		logrus.Infof("🏢 Backfilled the branch lists of %d businesses", len(businesses))
+	}
+	return len(businesses), nil
+}
+
+// syncBranchList adds the owner's carwashes filed under the business to its branch list.
+// Carwash.BusinessID alone is never trusted: the business's owner must also own the carwash.
+func (bs *BusinessService) syncBranchList(business *models.Business) error {
+	carwashes, err := bs.carwashRepo.GetOwnerBranches(business.OwnerID, business.ID)
+	if err != nil {
+		return err
+	}
+	ids := branchIDs(carwashes)
+	if err := bs.businessRepo.AddBranches(business.ID, ids); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if !business.HasBranch(id) {
+			business.BranchIDs = append(business.BranchIDs, id)
+		}
+	}
+	return nil
+}
+
+// AddBranch files a newly created carwash under its owner's business
+func (bs *BusinessService) AddBranch(ownerID primitive.ObjectID, carwash *models.Carwash) error {
+	owner, err := bs.userRepo.FindUserByID(ownerID)
+	if err != nil {
+		return errors.New("owner not found")
+	}
+	business, err := bs.EnsureBusiness(owner, carwash.Name)
+	if err != nil {
+		return err
+	}
+	carwash.BusinessID = &business.ID
+	return bs.userRepo.AssignOwnerBranch(owner.ID, business.ID, carwash.ID)
+}
+
+// GetMyBusiness returns the business of an owner, or of the branch a staff member works at
+func (bs *BusinessService) GetMyBusiness(userID string) (*models.Business, error) {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+	user, err := bs.userRepo.FindUserByID(objID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if business, err := bs.businessRepo.FindBusinessByOwnerID(user.ID); err == nil {
+		return business, nil
+	}
+	if user.CarWashID != nil {
+		carwash, err := bs.carwashRepo.GetCarwashByID(*user.CarWashID)
+		if err == nil && carwash.OwnerID == user.ID {
+			return bs.EnsureBusiness(user, carwash.Name)
+		}
+		if err == nil && carwash.BusinessID != nil {
+			business, err := bs.businessRepo.FindBusinessByID(*carwash.BusinessID)
+			if err == nil && business.HasBranch(carwash.ID) {
+				return business, nil
+			}
+		}
+	}
+	return nil, errors.New("no business found for this account")
+}
+
+// UpdateBusiness changes a business's profile
+func (bs *BusinessService) UpdateBusiness(businessID string, input models.Business) error {
+	objID, err := primitive.ObjectIDFromHex(businessID)
+	if err != nil {
+		return errors.New("invalid business ID")
+	}
+
+	update := bson.M{}
+	if input.Name != "" {
+		if len(input.Name) < 2 || len(input.Name) > 100 {
+			return errors.New("name must be between 2 and 100 characters")
+		}
+		update["name"] = input.Name
+	}
+	if input.Email != "" {
+		update["email"] = input.Email
+	}
+	if input.Phone != "" {
+		update["phone"] = input.Phone
+	}
+	if input.Address != "" {
+		update["address"] = input.Address
+	}
+	if input.Logo != "" {
+		update["logo"] = input.Logo
+	}
+	if len(update) == 0 {
+		return errors.New("nothing to update")
+	}
+	return bs.businessRepo.UpdateBusiness(objID, update)
+}
+
+// ListBranches returns the branches of a business the caller can see
+func (bs *BusinessService) ListBranches(sub policy.Subject, businessID string) ([]models.Carwash, error) {
+	return bs.scopeBranches(sub, businessID, "", "")
+}
+
+// ListBookings returns bookings of one branch, or of every branch the caller manages bookings at
+func (bs *BusinessService) ListBookings(sub policy.Subject, businessID, branchID, status, from, to string) ([]models.Booking, error) {
+	branches, err := bs.scopeBranches(sub, businessID, branchID, models.PermManageBookings)
+	if err != nil {
+		return nil, err
+	}
+	fromDate, toDate, err := parseReportRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	bookings, err := bs.bookingRepo.GetBookingsByCarwashesWithFilters(branchIDs(branches), status, fromDate, toDate)
+	if err != nil {
+		return nil, err
+	}
+	if bookings == nil {
+		bookings = []models.Booking{}
+	}
+	return bookings, nil
+}
+
+// ListWorkers returns workers of one branch, or of every branch the caller manages workers at
+func (bs *BusinessService) ListWorkers(sub policy.Subject, businessID, branchID string) ([]*models.User, error) {
+	branches, err := bs.scopeBranches(sub, businessID, branchID, models.PermManageWorkers)
+	if err != nil {
+		return nil, err
+	}
+	return bs.workerRepo.FindWorkersByCarwashIDs(branchIDs(branches))
+}
+
+// Report summarises bookings and paid revenue per branch, plus totals across them
+func (bs *BusinessService) Report(sub policy.Subject, businessID, branchID, from, to string) (*BusinessReport, error) {
+	branches, err := bs.scopeBranches(sub, businessID, branchID, models.PermViewFinances)
+	if err != nil {
+		return nil, err
+	}
+	fromDate, toDate, err := parseReportRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := branchIDs(branches)
+	counts, err := bs.bookingRepo.CountBookingsByBranch(ids, fromDate, toDate)
+	if err != nil {
+		logrus.Error("Failed to count bookings for report: ", err)
+		return nil, errors.New("failed to build report")
+	}
+	revenue, err := repositories.SumPaidByCarwash(ids, fromDate, toDate)
+	if err != nil {
+		logrus.Error("Failed to sum revenue for report: ", err)
+		return nil, errors.New("failed to build report")
+	}
+
+	report := &BusinessReport{
+		BusinessID: *branches[0].BusinessID,
+		From:       from,
+		To:         to,
+		Branches:   make([]BranchReport, 0, len(branches)),
+		Bookings:   map[string]int{},
+	}
+	for _, branch := range branches {
+		row := BranchReport{
+			CarwashID: branch.ID,
+			Name:      branch.Name,
+			Bookings:  map[string]int{},
+			Revenue:   revenue[branch.ID],
+		}
+		for _, c := range counts {
+			if c.CarwashID != branch.ID {
+				continue
+			}
+			row.Bookings[c.Status] += c.Count
+			row.TotalBookings += c.Count
+			report.Bookings[c.Status] += c.Count
+		}
+		report.TotalBookings += row.TotalBookings
+		report.Revenue += row.Revenue
+		report.Branches = append(report.Branches, row)
+	}
+	return report, nil
+}
+
+// scopeBranches resolves which branches of a business a request covers.
+// branchID narrows it to one branch; otherwise every branch the caller holds perm at is included.
+// An empty perm means any owner or staff member of the branch.
+func (bs *BusinessService) scopeBranches(sub policy.Subject, businessID, branchID string, perm models.Permission) ([]models.Carwash, error) {
+	objID, err := primitive.ObjectIDFromHex(businessID)
+	if err != nil {
+		return nil, errors.New("invalid business ID")
+	}
+	business, err := bs.businessRepo.FindBusinessByID(objID)
+	if err != nil {
+		return nil, errors.New("business not found")
+	}
+	branches := []models.Carwash{}
+	if len(business.BranchIDs) > 0 {
+		branches, err = bs.carwashRepo.GetCarwashesByIDs(business.BranchIDs)
+		if err != nil {
+			return nil, errors.New("failed to load branches")
+		}
+	}
+
+	scoped := []models.Carwash{}
+	for _, branch := range branches {
+		if branchID != "" && branch.ID.Hex() != branchID {
+			continue
+		}
+		if bs.canAtBranch(sub, branch.ID, perm) {
+			scoped = append(scoped, branch)
+		}
+	}
+	if len(scoped) == 0 {
+		if branchID != "" {
+			return nil, errors.New("branch not found")
+		}
+		return nil, policy.ErrForbidden
+	}
+	return scoped, nil
+}
+
+func (bs *BusinessService) canAtBranch(sub policy.Subject, carwashID primitive.ObjectID, perm models.Permission) bool {
+	if perm == "" {
+		return bs.authz.Allowed(sub, carwashID.Hex(), bs.authz.CarwashStaff)
+	}
+	return bs.authz.HasCarwashPermission(sub, carwashID, perm)
+}
+
+func branchIDs(branches []models.Carwash) []primitive.ObjectID {
+	ids := make([]primitive.ObjectID, len(branches))
+	for i, branch := range branches {
+		ids[i] = branch.ID
+	}
+	return ids
+}
+
+// parseReportRange parses optional YYYY-MM-DD bounds; "to" covers the whole day
+func parseReportRange(from, to string) (time.Time, time.Time, error) {
+	if from == "" || to == "" {
+		return time.Time{}, time.Time{}, nil
+	}
+	fromDate, err := time.Parse("2006-01-02", from)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("invalid from date format")
+	}
+	toDate, err := time.Parse("2006-01-02", to)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("invalid to date format")
+	}
+	return fromDate, toDate.Add(24*time.Hour - time.Nanosecond), nil
+}
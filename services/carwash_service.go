@@ -161,7 +161,26 @@ func (cws *CarWashService) GetAllActiveCarwashes() ([]models.Carwash, error) {
 	return cws.carwashRepository.GetActiveCarwashes()
 }
 
-func (cws *CarWashService) UpdateCarwash(id string, updateData map[string]interface{}) error {
+// editableCarwashFields are the profile fields an owner may change. Ownership, the business,
+// ratings, queue and SMS settings and moderation state change through their own flows.
+var editableCarwashFields = map[string]bool{
+	"name": true, "description": true, "about": true, "address": true, "state": true, "country": true, "lga": true,
+	"photo_gallery": true, "services": true, "features": true, "addons": true, "base_price": true,
+	"open_hours": true, "max_cars_per_slot": true, "home_service": true, "delivery_radius_km": true,
+	"service_range_minutes": true,
+}
+
+func (cws *CarWashService) UpdateCarwash(id string, input map[string]interface{}) error {
+	updateData := bson.M{}
+	for field, value := range input {
+		if editableCarwashFields[field] {
+			updateData[field] = value
+		}
+	}
+	if len(updateData) == 0 {
+		return errors.New("no valid fields to update")
+	}
+
 	// If address is being updated, geocode the new address
 	if address, ok := updateData["address"].(string); ok && address != "" {
 		logrus.Infof("Geocoding updated address: %s", address)
@@ -187,6 +206,12 @@ func (cws *CarWashService) SetCarwashStatus(id string, isActive bool) error {
 	return cws.carwashRepository.SetCarwashStatus(id, isActive)
 }
 
+// SetWorkerTwoFactorRequirement makes 2FA mandatory (or optional) for the carwash's workers.
+// Workers without 2FA are asked to set it up on their next login.
+func (cws *CarWashService) SetWorkerTwoFactorRequirement(id string, required bool) error {
+	return cws.carwashRepository.UpdateCarwash(id, bson.M{"require_worker_2fa": required})
+}
+
 func (cws *CarWashService) CompleteOnboarding(id primitive.ObjectID) error {
 	// 1. Fetch carwash details
 	carwash, err := cws.carwashRepository.GetCarwashByID(id)
@@ -445,3 +470,84 @@ func (cws *CarWashService) UploadCarwashPhoto(carwashID string, photoFile *Profi
 
 	return uploadResult.SecureURL, nil
 }
+
+// CarwashVerification is the KYC review state of a listing, shown to its owner and to admins
+type CarwashVerification struct {
+	CarwashID      primitive.ObjectID            `json:"carwash_id"`
+	ApprovalStatus string                        `json:"approval_status"`
+	Note           string                        `json:"note,omitempty"`
+	Verified       bool                          `json:"verified"`
+	ApprovedAt     *time.Time                    `json:"approved_at,omitempty"`
+	Documents      []models.VerificationDocument `json:"documents"`
+}
+
+func newCarwashVerification(carwash *models.Carwash) *CarwashVerification {
+	documents := carwash.VerificationDocuments
+	if documents == nil {
+		documents = []models.VerificationDocument{}
+	}
+	return &CarwashVerification{
+		CarwashID:      carwash.ID,
+		ApprovalStatus: carwash.ApprovalStatus,
+		Note:           carwash.ApprovalNote,
+		Verified:       carwash.IsVerified(),
+		ApprovedAt:     carwash.ApprovedAt,
+		Documents:      documents,
+	}
+}
+
+// GetVerification returns the listing's KYC review state for its owner
+func (cws *CarWashService) GetVerification(carwashID string) (*CarwashVerification, error) {
+	carwash, err := cws.GetCarwashByID(carwashID)
+	if err != nil {
+		return nil, err
+	}
+	return newCarwashVerification(carwash), nil
+}
+
+// UploadVerificationDocument stores a business document for KYC review.
+// The listing stays in pending_review (or returns to it after a rejection) until an admin decides.
+func (cws *CarWashService) UploadVerificationDocument(carwashID, docType string, docFile *ProfilePhotoFile) (*models.VerificationDocument, error) {
+	objID, err := primitive.ObjectIDFromHex(carwashID)
+	if err != nil {
+		return nil, errors.New("invalid carwash ID format")
+	}
+	if !models.IsValidDocumentType(docType) {
+		return nil, errors.New("invalid document type (allowed: business_registration, tax_id, owner_id, other)")
+	}
+
+	maxSize := int64(10 * 1024 * 1024) // 10MB
+	if docFile.Size > maxSize {
+		return nil, errors.New("file size too large (max 10MB)")
+	}
+
+	ext := strings.ToLower(filepath.Ext(docFile.Filename))
+	allowedExts := map[string]bool{
+		".pdf": true, ".jpg": true, ".jpeg": true, ".png": true,
+	}
+	if !allowedExts[ext] {
+		return nil, errors.New("invalid file type (allowed: pdf, jpg, jpeg, png)")
+	}
+
+	doc := models.VerificationDocument{
+		ID:         primitive.NewObjectID(),
+		Type:       docType,
+		FileName:   docFile.Filename,
+		UploadedAt: time.Now(),
+	}
+
+	uploadResult, err := UploadDocument(docFile.File, fmt.Sprintf("carwash_%s_%s", carwashID, doc.ID.Hex()), "carwash_documents")
+	if err != nil {
+		return nil, err
+	}
+	doc.URL = uploadResult.SecureURL
+	doc.PublicID = uploadResult.PublicID
+
+	if err := cws.carwashRepository.AddVerificationDocument(objID, doc); err != nil {
+		DeleteImage(uploadResult.PublicID)
+		return nil, err
+	}
+
+	logrus.Infof("📄 Verification document %s uploaded for carwash %s", docType, carwashID)
+	return &doc, nil
+}
@@ -66,4 +66,25 @@ func DeleteImage(publicID string) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+// UploadDocument uploads a business document (image or PDF) to Cloudinary without resizing it
+func UploadDocument(file multipart.File, filename string, folder string) (*CloudinaryUploadResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	result, err := config.CloudinaryInstance.Upload.Upload(ctx, file, uploader.UploadParams{
+		Folder:       folder,
+		PublicID:     filename,
+		ResourceType: "auto", // PDFs are stored as raw files
+	})
+	if err != nil {
+		logrus.Error("Cloudinary document upload failed: ", err)
+		return nil, fmt.Errorf("failed to upload document: %v", err)
+	}
+
+	return &CloudinaryUploadResult{
+		PublicID:  result.PublicID,
+		URL:       result.URL,
+		SecureURL: result.SecureURL,
+	}, nil
+}
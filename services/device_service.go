@@ -0,0 +1,64 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/repositories"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DeviceService manages the push tokens clients register for their user
+type DeviceService struct {
+	deviceRepo *repositories.DeviceTokenRepository
+}
+
+// NewDeviceService creates a new DeviceService instance
+func NewDeviceService(deviceRepo *repositories.DeviceTokenRepository) *DeviceService {
+	return &DeviceService{deviceRepo: deviceRepo}
+}
+
+// RegisterDevice saves the caller's push token. Registering a known token refreshes it.
+func (ds *DeviceService) RegisterDevice(userID string, input models.DeviceToken) (*models.DeviceToken, error) {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+	input.UserID = objID
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	device, err := ds.deviceRepo.Register(&input)
+	if err != nil {
+		logrus.Error("Failed to register device token: ", err)
+		return nil, errors.New("failed to register device")
+	}
+	return device, nil
+}
+
+// ListDevices returns the caller's registered devices
+func (ds *DeviceService) ListDevices(userID string) ([]models.DeviceToken, error) {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+	return ds.deviceRepo.FindByUserID(objID)
+}
+
+// UnregisterDevice stops push notifications to one of the caller's devices
+func (ds *DeviceService) UnregisterDevice(userID, token string) error {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return errors.New("invalid user ID")
+	}
+	deleted, err := ds.deviceRepo.DeleteUserToken(objID, token)
+	if err != nil {
+		return errors.New("failed to unregister device")
+	}
+	if !deleted {
+		return errors.New("device not found")
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// services/email/capture.go
+package email
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// CaptureSender keeps emails in memory instead of sending them, so tests can inspect what was sent.
+// Addresses passed to Reject fail with ErrRejected like a real provider would.
+type CaptureSender struct {
+	mu       sync.Mutex
+	sent     []Message
+	rejected map[string]bool
+}
+
+// NewCaptureSender creates an empty CaptureSender
+func NewCaptureSender() *CaptureSender {
+	return &CaptureSender{rejected: map[string]bool{}}
+}
+
+// Send implements EmailSender
+func (s *CaptureSender) Send(ctx context.Context, msg Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rejected[strings.ToLower(msg.To)] {
+		return ErrRejected
+	}
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+// Reject makes later sends to address fail with ErrRejected
+func (s *CaptureSender) Reject(address string) {
+	s.mu.Lock()
+	s.rejected[strings.ToLower(address)] = true
+	s.mu.Unlock()
+}
+
+// Sent returns a copy of every email sent so far
+func (s *CaptureSender) Sent() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.sent...)
+}
+
+// SentTo returns the emails sent to an address
+func (s *CaptureSender) SentTo(address string) []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var matched []Message
+	for _, msg := range s.sent {
+		if strings.EqualFold(msg.To, address) {
+			matched = append(matched, msg)
+		}
+	}
+	return matched
+}
+
+// Reset forgets the sent emails
+func (s *CaptureSender) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = nil
+}
@@ -0,0 +1,42 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCaptureSender(t *testing.T) {
+	ctx := context.Background()
+	sender := NewCaptureSender()
+	sender.Reject("Bounced@Example.com")
+
+	for _, msg := range []Message{
+		{To: "ada@example.com", Subject: "first"},
+		{To: "tunde@example.com", Subject: "second"},
+		{To: "ADA@example.com", Subject: "third"},
+	} {
+		if err := sender.Send(ctx, msg); err != nil {
+			t.Fatalf("Send(%s) = %v", msg.To, err)
+		}
+	}
+	if err := sender.Send(ctx, Message{To: "bounced@example.com"}); !errors.Is(err, ErrRejected) {
+		t.Errorf("Send to a rejected address = %v, want ErrRejected", err)
+	}
+
+	if n := len(sender.Sent()); n != 3 {
+		t.Errorf("Sent returned %d emails, want 3", n)
+	}
+	toAda := sender.SentTo("ada@example.com")
+	if len(toAda) != 2 || toAda[0].Subject != "first" || toAda[1].Subject != "third" {
+		t.Errorf("SentTo(ada) = %+v, want the first and third emails", toAda)
+	}
+
+	sender.Reset()
+	if n := len(sender.Sent()); n != 0 {
+		t.Errorf("Sent returned %d emails after Reset, want none", n)
+	}
+	if err := sender.Send(ctx, Message{To: "bounced@example.com"}); !errors.Is(err, ErrRejected) {
+		t.Error("Reset forgot the rejected addresses")
+	}
+}
@@ -0,0 +1,31 @@
+// services/email/console.go
+package email
+
+import (
+	"context"
+	"fmt"
+)
+
+// ConsoleSender prints emails instead of sending them, for local development
+type ConsoleSender struct{}
+
+// NewConsoleSender creates a sender that only logs
+func NewConsoleSender() *ConsoleSender {
+	return &ConsoleSender{}
+}
+
+// Send implements EmailSender
+func (s *ConsoleSender) Send(ctx context.Context, msg Message) error {
+	fmt.Println("==================================================")
+	fmt.Println("📧 [MOCK EMAIL] No email provider configured - Logging Email")
+	fmt.Printf("To: %s\n", msg.To)
+	fmt.Printf("Subject: %s\n", msg.Subject)
+	fmt.Println("Body:")
+	if msg.Text != "" {
+		fmt.Println(msg.Text)
+	} else {
+		fmt.Println(msg.HTML)
+	}
+	fmt.Println("==================================================")
+	return nil
+}
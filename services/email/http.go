@@ -0,0 +1,111 @@
+// services/email/http.go
+package email
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HTTPSender sends mail through a transactional-email provider's HTTP API, which keeps
+// working where outbound SMTP ports are blocked:
+//
+//	POST <endpoint>  Authorization: Bearer <api key>
+//	{"from": {"email": "...", "name": "..."}, "to": "...", "subject": "...",
+//	 "text": "...", "html": "...", "headers": {"List-Unsubscribe": "..."}}
+//
+// A 2xx answer may report {"message_id": "..."}. Only a refusal of the recipient — 422, or an
+// error answer with {"code": "invalid_recipient"} — is ErrRejected; other failures, including
+// 401/403/404 from a bad API key or endpoint, are configuration errors worth retrying.
+// Providers with a different API sit behind a small adapter exposing this one.
+type HTTPSender struct {
+	endpoint string
+	apiKey   string
+	from     From
+	client   *http.Client
+}
+
+// NewHTTPSender creates a provider API sender
+func NewHTTPSender(endpoint, apiKey string, from From) *HTTPSender {
+	return &HTTPSender{
+		endpoint: endpoint,
+		apiKey:   apiKey,
+		from:     from,
+		client:   &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+type providerAddress struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+type providerRequest struct {
+	From    providerAddress   `json:"from"`
+	To      string            `json:"to"`
+	Subject string            `json:"subject"`
+	Text    string            `json:"text,omitempty"`
+	HTML    string            `json:"html,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+type providerResponse struct {
+	MessageID string `json:"message_id"`
+	Error     string `json:"error"`
+	Code      string `json:"code"`
+}
+
+// codeInvalidRecipient is the error code a provider answers with when it refuses the address itself
+const codeInvalidRecipient = "invalid_recipient"
+
+// Send implements EmailSender
+func (s *HTTPSender) Send(ctx context.Context, msg Message) error {
+	payload, err := json.Marshal(providerRequest{
+		From:    providerAddress{Email: s.from.Email, Name: s.from.Name},
+		To:      msg.To,
+		Subject: msg.Subject,
+		Text:    msg.Text,
+		HTML:    msg.HTML,
+		Headers: msg.Headers,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("email provider request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var result providerResponse
+	json.Unmarshal(body, &result)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		reason := strings.TrimSpace(result.Error)
+		if reason == "" {
+			reason = http.StatusText(resp.StatusCode)
+		}
+		// Only a refused recipient is final; the caller suppresses the address for good
+		if resp.StatusCode == http.StatusUnprocessableEntity || result.Code == codeInvalidRecipient {
+			return fmt.Errorf("%w: %s", ErrRejected, reason)
+		}
+		return fmt.Errorf("email provider returned %d: %s", resp.StatusCode, reason)
+	}
+	return nil
+}
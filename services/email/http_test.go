@@ -0,0 +1,77 @@
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPSenderSend(t *testing.T) {
+	var got providerRequest
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"message_id": "m-1"}`))
+	}))
+	defer server.Close()
+
+	sender := NewHTTPSender(server.URL, "secret", From{Email: "no-reply@example.com", Name: "CarWash"})
+	err := sender.Send(context.Background(), Message{
+		To:      "ada@example.com",
+		Subject: "Hello",
+		Text:    "plain",
+		HTML:    "<p>html</p>",
+		Headers: map[string]string{"List-Unsubscribe": "<https://example.com/u>"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if auth != "Bearer secret" {
+		t.Errorf("Authorization = %q, want the API key", auth)
+	}
+	if got.From.Email != "no-reply@example.com" || got.From.Name != "CarWash" || got.To != "ada@example.com" ||
+		got.Subject != "Hello" || got.Text != "plain" || got.HTML != "<p>html</p>" ||
+		got.Headers["List-Unsubscribe"] != "<https://example.com/u>" {
+		t.Errorf("unexpected provider request %+v", got)
+	}
+}
+
+func TestHTTPSenderErrors(t *testing.T) {
+	cases := []struct {
+		name     string
+		status   int
+		body     string
+		rejected bool
+	}{
+		{"invalid address", http.StatusUnprocessableEntity, `{"error": "mailbox does not exist"}`, true},
+		{"recipient error code", http.StatusBadRequest, `{"error": "bad address", "code": "invalid_recipient"}`, true},
+		{"bad request", http.StatusBadRequest, `{"error": "sender domain not verified"}`, false},
+		{"bad API key", http.StatusUnauthorized, `{"error": "invalid key"}`, false},
+		{"key without permission", http.StatusForbidden, `{"error": "forbidden"}`, false},
+		{"wrong endpoint", http.StatusNotFound, ``, false},
+		{"throttled", http.StatusTooManyRequests, `{"error": "slow down"}`, false},
+		{"provider down", http.StatusBadGateway, ``, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			err := NewHTTPSender(server.URL, "", From{}).Send(context.Background(), Message{To: "ada@example.com"})
+			if err == nil {
+				t.Fatal("Send succeeded")
+			}
+			if errors.Is(err, ErrRejected) != tc.rejected {
+				t.Errorf("Send = %v, rejected = %v, want %v", err, errors.Is(err, ErrRejected), tc.rejected)
+			}
+		})
+	}
+}
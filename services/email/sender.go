@@ -0,0 +1,36 @@
+// services/email/sender.go
+package email
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrRejected is returned when the provider refuses a message outright (e.g. an invalid address);
+// sending it again won't help
+var ErrRejected = errors.New("email rejected by provider")
+
+// ErrSuppressed is returned for addresses that bounced or complained before; they are not mailed again
+var ErrSuppressed = errors.New("email address is undeliverable")
+
+// Message is an email with a plain-text part, an HTML part, or both
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+	Headers map[string]string // extra headers, such as List-Unsubscribe
+}
+
+// EmailSender defines the interface for delivering emails.
+// SMTPSender talks SMTP, HTTPSender a transactional-email API (for hosts that block SMTP ports),
+// ConsoleSender only logs and CaptureSender keeps messages in memory for tests.
+type EmailSender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// From is the sender address and display name
+type From struct {
+	Email string
+	Name  string
+}
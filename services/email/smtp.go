@@ -0,0 +1,146 @@
+// services/email/smtp.go
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"sort"
+	"time"
+)
+
+// SMTPConfig holds the SMTP server and account mail is sent through
+type SMTPConfig struct {
+	Host     string
+	Port     string // 465 uses TLS from the start, anything else STARTTLS
+	Username string
+	Password string
+	From     From
+}
+
+// SMTPSender sends mail over SMTP. Port 465 (implicit TLS) works on hosts that block 587;
+// see RENDER_EMAIL_FIX.md. Where every SMTP port is blocked, use HTTPSender instead.
+type SMTPSender struct {
+	config SMTPConfig
+}
+
+// NewSMTPSender creates an SMTP sender
+func NewSMTPSender(config SMTPConfig) *SMTPSender {
+	return &SMTPSender{config: config}
+}
+
+// Send implements EmailSender
+func (s *SMTPSender) Send(ctx context.Context, msg Message) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+	}
+
+	addr := net.JoinHostPort(s.config.Host, s.config.Port)
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %v", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	tlsConfig := &tls.Config{ServerName: s.config.Host}
+	if s.config.Port == "465" {
+		// Port 465 uses SSL/TLS from the start
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		return fmt.Errorf("failed to create SMTP client: %v", err)
+	}
+	defer client.Close()
+
+	if s.config.Port != "465" {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return fmt.Errorf("STARTTLS failed: %v", err)
+			}
+		}
+	}
+
+	if s.config.Username != "" {
+		auth := smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %v", err)
+		}
+	}
+
+	if err := client.Mail(s.config.From.Email); err != nil {
+		return fmt.Errorf("failed to set sender: %v", err)
+	}
+	if err := client.Rcpt(msg.To); err != nil {
+		// A permanent (5xx) refusal of the recipient won't change on retry
+		var smtpErr *textproto.Error
+		if errors.As(err, &smtpErr) && smtpErr.Code >= 500 {
+			return fmt.Errorf("%w: %v", ErrRejected, err)
+		}
+		return fmt.Errorf("failed to set recipient: %v", err)
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to get data writer: %v", err)
+	}
+	if _, err := writer.Write(Compose(s.config.From, msg)); err != nil {
+		return fmt.Errorf("failed to write message: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to send email: %v", err)
+	}
+	return client.Quit()
+}
+
+// Compose builds the raw message. With both parts present the text part comes first,
+// so clients that can show HTML pick the last alternative.
+func Compose(from From, msg Message) []byte {
+	headers := fmt.Sprintf(
+		"To: %s\r\n"+
+			"From: %s <%s>\r\n"+
+			"Subject: %s\r\n"+
+			"MIME-Version: 1.0\r\n",
+		msg.To, mime.QEncoding.Encode("UTF-8", from.Name), from.Email, mime.QEncoding.Encode("UTF-8", msg.Subject))
+	names := make([]string, 0, len(msg.Headers))
+	for name := range msg.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		headers += name + ": " + msg.Headers[name] + "\r\n"
+	}
+
+	switch {
+	case msg.Text == "":
+		return []byte(headers + "Content-Type: text/html; charset=UTF-8\r\n\r\n" + msg.HTML + "\r\n")
+	case msg.HTML == "":
+		return []byte(headers + "Content-Type: text/plain; charset=UTF-8\r\n\r\n" + msg.Text + "\r\n")
+	}
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=UTF-8", msg.Text},
+		{"text/html; charset=UTF-8", msg.HTML},
+	} {
+		w, _ := writer.CreatePart(textproto.MIMEHeader{"Content-Type": {part.contentType}})
+		w.Write([]byte(part.body))
+	}
+	writer.Close()
+
+	return []byte(headers + "Content-Type: multipart/alternative; boundary=" + writer.Boundary() + "\r\n\r\n" + buf.String())
+}
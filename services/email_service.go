@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"strings"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/repositories"
+	"github.com/olabanji12-ojo/CarWashApp/services/email"
+	"github.com/sirupsen/logrus"
+)
+
+// EmailService sends email through the configured provider, except to addresses that bounced
+// or complained before. It implements email.EmailSender, so everything that sends mail goes through it.
+type EmailService struct {
+	sender          email.EmailSender
+	suppressionRepo *repositories.EmailSuppressionRepository
+	webhookSecret   string
+}
+
+// NewEmailService creates a new EmailService instance. webhookSecret authenticates the
+// provider's bounce and complaint reports; without one, reports are refused.
+func NewEmailService(sender email.EmailSender, suppressionRepo *repositories.EmailSuppressionRepository, webhookSecret string) *EmailService {
+	return &EmailService{sender: sender, suppressionRepo: suppressionRepo, webhookSecret: webhookSecret}
+}
+
+// EmailEvent is a delivery report from the email provider:
+//
+//	{"type": "bounce", "email": "someone@example.com", "bounce_type": "hard", "reason": "550 mailbox not found"}
+//	{"type": "complaint", "email": "someone@example.com"}
+//
+// Providers with a different format sit behind a small adapter posting this one.
+type EmailEvent struct {
+	Type       string `json:"type"`        // bounce, complaint; anything else is ignored
+	Email      string `json:"email"`       // the recipient
+	BounceType string `json:"bounce_type"` // hard/permanent or soft/transient; hard when missing
+	Reason     string `json:"reason"`
+}
+
+// Send implements email.EmailSender. Suppressed addresses fail with email.ErrSuppressed, and an
+// address the provider rejects outright is suppressed like a hard bounce.
+func (es *EmailService) Send(ctx context.Context, msg email.Message) error {
+	address := normalizeEmail(msg.To)
+	suppressed, err := es.suppressionRepo.IsSuppressed(address)
+	if err != nil {
+		// Better to risk one more bounce than to lose the email
+		logrus.Warnf("Failed to check email suppression of %s: %v", address, err)
+	}
+	if suppressed {
+		return email.ErrSuppressed
+	}
+
+	err = es.sender.Send(ctx, msg)
+	if errors.Is(err, email.ErrRejected) {
+		es.suppress(address, models.EmailSuppressionBounce, err.Error())
+	}
+	return err
+}
+
+// VerifyWebhookToken checks the token the provider's webhook calls us with
+func (es *EmailService) VerifyWebhookToken(token string) bool {
+	if es.webhookSecret == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(es.webhookSecret)) == 1
+}
+
+// HandleEvents suppresses the addresses of hard bounces and complaints and returns how many
+// reports it acted on. Soft bounces are left alone: the mailbox may accept mail again later.
+func (es *EmailService) HandleEvents(events []EmailEvent) (int, error) {
+	handled := 0
+	for _, event := range events {
+		address := normalizeEmail(event.Email)
+		if address == "" {
+			continue
+		}
+
+		var reason string
+		switch strings.ToLower(event.Type) {
+		case "bounce", "bounced":
+			switch strings.ToLower(event.BounceType) {
+			case "", "hard", "permanent":
+				reason = models.EmailSuppressionBounce
+			default:
+				continue
+			}
+		case "complaint", "spam", "spamreport":
+			reason = models.EmailSuppressionComplaint
+		default:
+			continue
+		}
+
+		if err := es.suppressionRepo.Suppress(address, reason, event.Reason); err != nil {
+			logrus.Errorf("Failed to suppress %s: %v", address, err)
+			return handled, errors.New("failed to record email event")
+		}
+		logrus.Infof("📪 Stopped emailing %s (%s)", address, reason)
+		handled++
+	}
+	return handled, nil
+}
+
+func (es *EmailService) suppress(address, reason, detail string) {
+	if err := es.suppressionRepo.Suppress(address, reason, detail); err != nil {
+		logrus.Errorf("Failed to suppress %s: %v", address, err)
+	}
+}
+
+func normalizeEmail(address string) string {
+	return strings.ToLower(strings.TrimSpace(address))
+}
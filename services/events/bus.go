@@ -0,0 +1,71 @@
+// services/events/bus.go
+package events
+
+import (
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Handler consumes a published event
+type Handler func(event Event)
+
+// Bus carries domain events from the services that publish them to their subscribers.
+type Bus interface {
+	// Publish hands an event to every subscriber of its name
+	Publish(event Event)
+	// Subscribe registers a handler for events with the given name
+	Subscribe(name string, handler Handler)
+}
+
+// On subscribes a handler to one event type, so it receives the typed event:
+//
+//	events.On(bus, func(e events.BookingCreated) { ... })
+func On[E Event](bus Bus, handler func(E)) {
+	var zero E
+	bus.Subscribe(zero.EventName(), func(event Event) {
+		if typed, ok := event.(E); ok {
+			handler(typed)
+		}
+	})
+}
+
+// LocalBus is an in-process Bus. Publish runs the subscribers one after another, in the order
+// they subscribed, before it returns; a subscriber that panics is logged and the rest still run.
+// Subscribers that do slow work should queue it (the outbox, the scheduler) rather than do it inline.
+type LocalBus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+// NewLocalBus creates an empty LocalBus
+func NewLocalBus() *LocalBus {
+	return &LocalBus{handlers: map[string][]Handler{}}
+}
+
+// Subscribe registers a handler for events with the given name
+func (b *LocalBus) Subscribe(name string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[name] = append(b.handlers[name], handler)
+}
+
+// Publish hands an event to every subscriber of its name
+func (b *LocalBus) Publish(event Event) {
+	b.mu.RLock()
+	handlers := b.handlers[event.EventName()]
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		b.dispatch(handler, event)
+	}
+}
+
+func (b *LocalBus) dispatch(handler Handler, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("❌ Subscriber of %s panicked: %v", event.EventName(), r)
+		}
+	}()
+	handler(event)
+}
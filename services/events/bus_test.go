@@ -0,0 +1,66 @@
+package events
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+)
+
+func TestLocalBusRunsSubscribersInOrder(t *testing.T) {
+	bus := NewLocalBus()
+	var calls []string
+	bus.Subscribe(NameOrderCompleted, func(Event) { calls = append(calls, "first") })
+	bus.Subscribe(NameOrderCompleted, func(Event) { calls = append(calls, "second") })
+	bus.Subscribe(NameReviewCreated, func(Event) { calls = append(calls, "review") })
+
+	bus.Publish(OrderCompleted{Order: &models.Order{}})
+
+	if want := []string{"first", "second"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestLocalBusIsolatesPanickingSubscribers(t *testing.T) {
+	bus := NewLocalBus()
+	reached := false
+	bus.Subscribe(NameOrderCompleted, func(Event) { panic("boom") })
+	bus.Subscribe(NameOrderCompleted, func(Event) { reached = true })
+
+	bus.Publish(OrderCompleted{Order: &models.Order{}})
+
+	if !reached {
+		t.Error("a panicking subscriber stopped the ones after it")
+	}
+}
+
+func TestOnDeliversTypedEvents(t *testing.T) {
+	bus := NewLocalBus()
+	var got []*models.Review
+	On(bus, func(e ReviewCreated) { got = append(got, e.Review) })
+
+	review := &models.Review{Comment: "spotless"}
+	bus.Publish(ReviewCreated{Review: review})
+	bus.Publish(OrderCompleted{Order: &models.Order{}})
+
+	if len(got) != 1 || got[0] != review {
+		t.Errorf("handler received %v, want only the review", got)
+	}
+}
+
+func TestOnIgnoresOtherTypesWithTheSameName(t *testing.T) {
+	bus := NewLocalBus()
+	called := false
+	On(bus, func(ReviewCreated) { called = true })
+
+	bus.Publish(impostor{})
+
+	if called {
+		t.Error("handler ran for an event of another type")
+	}
+}
+
+// impostor shares ReviewCreated's name but not its type
+type impostor struct{}
+
+func (impostor) EventName() string { return NameReviewCreated }
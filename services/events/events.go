@@ -0,0 +1,85 @@
+// services/events/events.go
+package events
+
+import (
+	"time"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+)
+
+// Event names
+const (
+	NameBookingCreated       = "booking.created"
+	NameBookingStatusChanged = "booking.status_changed"
+	NameBookingRescheduled   = "booking.rescheduled"
+	NameOrderCompleted       = "order.completed"
+	NamePaymentSucceeded     = "payment.succeeded"
+	NameReviewCreated        = "review.created"
+)
+
+// Event is something that happened in the domain. Services publish events once the change is
+// saved; what else should happen as a result (notifications, webhooks, reminders…) is up to
+// whoever subscribed.
+type Event interface {
+	EventName() string
+}
+
+// BookingCreated is published after a customer books a slot
+type BookingCreated struct {
+	Booking      *models.Booking
+	Carwash      *models.Carwash
+	CustomerName string
+	OccurredAt   time.Time
+}
+
+func (BookingCreated) EventName() string { return NameBookingCreated }
+
+// How a booking's status came to change
+const (
+	SourceStatusUpdate = "status_update" // the carwash moved it along its workflow
+	SourceCancellation = "cancellation"  // the booking was cancelled
+)
+
+// BookingStatusChanged is published after a booking moves to another status
+type BookingStatusChanged struct {
+	Booking        *models.Booking // already carries the new status
+	PreviousStatus string
+	Source         string // status_update, cancellation
+	CarwashName    string
+	OccurredAt     time.Time
+}
+
+func (BookingStatusChanged) EventName() string { return NameBookingStatusChanged }
+
+// BookingRescheduled is published after a booking is moved to another time
+type BookingRescheduled struct {
+	Booking             *models.Booking // already carries the new time
+	PreviousBookingTime time.Time
+	OccurredAt          time.Time
+}
+
+func (BookingRescheduled) EventName() string { return NameBookingRescheduled }
+
+// OrderCompleted is published the first time an order is marked completed
+type OrderCompleted struct {
+	Order      *models.Order
+	OccurredAt time.Time
+}
+
+func (OrderCompleted) EventName() string { return NameOrderCompleted }
+
+// PaymentSucceeded is published after a payment is recorded as paid
+type PaymentSucceeded struct {
+	Payment    *models.Payment
+	OccurredAt time.Time
+}
+
+func (PaymentSucceeded) EventName() string { return NamePaymentSucceeded }
+
+// ReviewCreated is published after a customer reviews a carwash
+type ReviewCreated struct {
+	Review     *models.Review
+	OccurredAt time.Time
+}
+
+func (ReviewCreated) EventName() string { return NameReviewCreated }
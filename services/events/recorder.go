@@ -0,0 +1,55 @@
+// services/events/recorder.go
+package events
+
+import "sync"
+
+// Recorder is a Bus that keeps every event published to it, so tests can assert on what a
+// service published. Subscribers registered on it still receive the events.
+type Recorder struct {
+	*LocalBus
+
+	mu     sync.Mutex
+	events []Event
+}
+
+// NewRecorder creates an empty Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{LocalBus: NewLocalBus()}
+}
+
+// Publish records the event and hands it to the subscribers
+func (r *Recorder) Publish(event Event) {
+	r.mu.Lock()
+	r.events = append(r.events, event)
+	r.mu.Unlock()
+
+	r.LocalBus.Publish(event)
+}
+
+// Events returns everything published so far, oldest first
+func (r *Recorder) Events() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Event(nil), r.events...)
+}
+
+// Named returns the published events with the given name, oldest first
+func (r *Recorder) Named(name string) []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var matched []Event
+	for _, event := range r.events {
+		if event.EventName() == name {
+			matched = append(matched, event)
+		}
+	}
+	return matched
+}
+
+// Reset forgets the recorded events
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = nil
+}
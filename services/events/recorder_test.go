@@ -0,0 +1,52 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+)
+
+func TestRecorder(t *testing.T) {
+	rec := NewRecorder()
+	var delivered int
+	On(rec, func(OrderCompleted) { delivered++ })
+
+	rec.Publish(OrderCompleted{Order: &models.Order{}})
+	rec.Publish(ReviewCreated{Review: &models.Review{}})
+	rec.Publish(OrderCompleted{Order: &models.Order{}})
+
+	if delivered != 2 {
+		t.Errorf("subscriber received %d events, want 2", delivered)
+	}
+
+	all := rec.Events()
+	if len(all) != 3 {
+		t.Fatalf("recorded %d events, want 3", len(all))
+	}
+	for i, want := range []string{NameOrderCompleted, NameReviewCreated, NameOrderCompleted} {
+		if all[i].EventName() != want {
+			t.Errorf("event %d = %s, want %s", i, all[i].EventName(), want)
+		}
+	}
+	if n := len(rec.Named(NameOrderCompleted)); n != 2 {
+		t.Errorf("Named(%s) returned %d events, want 2", NameOrderCompleted, n)
+	}
+	if n := len(rec.Named(NamePaymentSucceeded)); n != 0 {
+		t.Errorf("Named(%s) returned %d events, want none", NamePaymentSucceeded, n)
+	}
+
+	// Events hands out a copy
+	all[0] = nil
+	if rec.Events()[0] == nil {
+		t.Error("Events exposed the recorder's own slice")
+	}
+
+	rec.Reset()
+	if n := len(rec.Events()); n != 0 {
+		t.Errorf("recorded %d events after Reset, want none", n)
+	}
+	rec.Publish(OrderCompleted{Order: &models.Order{}})
+	if delivered != 3 {
+		t.Error("Reset dropped the subscribers")
+	}
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// permanentError marks a failure that retrying cannot fix
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps an error so the job is given up at once instead of retried
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// jobInfo is what a jobRunner needs to know about a claimed job
+type jobInfo struct {
+	ID          primitive.ObjectID
+	Key         string // picks the handler: the outbox channel, the scheduled job kind
+	Attempts    int    // counting the current one
+	MaxAttempts int
+}
+
+// leasedQueue is a collection of jobs that workers lease one at a time. Each outcome is only
+// recorded while the worker still holds the lease.
+type leasedQueue[J any] interface {
+	ClaimNext(workerID string, lease time.Duration) (*J, error)
+	Complete(job *J) error
+	Retry(job *J, at time.Time, lastError string) error
+	GiveUp(job *J, lastError string) error
+	Info(job *J) jobInfo
+}
+
+// jobRunner is a pool of goroutines working off a leasedQueue. Every instance can run one; the
+// lease makes sure each job runs on one of them. Failed jobs are retried with exponential backoff
+// and given up after MaxAttempts or a Permanent error.
+type jobRunner[J any] struct {
+	name         string // for logs, e.g. "Outbox job"
+	queue        leasedQueue[J]
+	handlers     map[string]func(ctx context.Context, job *J) error
+	workerID     string
+	concurrency  int
+	pollInterval time.Duration
+	lease        time.Duration
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newJobRunner[J any](name string, queue leasedQueue[J], concurrency int, pollInterval time.Duration) *jobRunner[J] {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	host, _ := os.Hostname()
+	return &jobRunner[J]{
+		name:         name,
+		queue:        queue,
+		handlers:     map[string]func(context.Context, *J) error{},
+		workerID:     fmt.Sprintf("%s-%d-%s", host, os.Getpid(), primitive.NewObjectID().Hex()[18:]),
+		concurrency:  concurrency,
+		pollInterval: pollInterval,
+		lease:        2 * time.Minute,
+		stop:         make(chan struct{}),
+	}
+}
+
+func (jr *jobRunner[J]) handle(key string, handler func(ctx context.Context, job *J) error) {
+	jr.handlers[key] = handler
+}
+
+func (jr *jobRunner[J]) start() {
+	for i := 0; i < jr.concurrency; i++ {
+		jr.wg.Add(1)
+		go jr.run()
+	}
+}
+
+// shutdown stops claiming new jobs and waits for running ones to finish.
+// Jobs still running when ctx expires keep their lease and are picked up again after it lapses.
+func (jr *jobRunner[J]) shutdown(ctx context.Context) error {
+	close(jr.stop)
+
+	done := make(chan struct{})
+	go func() {
+		jr.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (jr *jobRunner[J]) run() {
+	defer jr.wg.Done()
+
+	for {
+		select {
+		case <-jr.stop:
+			return
+		default:
+		}
+
+		job, err := jr.queue.ClaimNext(jr.workerID, jr.lease)
+		if err != nil {
+			logrus.Errorf("Failed to claim %s: %v", jr.name, err)
+		}
+		if job == nil {
+			select {
+			case <-jr.stop:
+				return
+			case <-time.After(jr.pollInterval):
+			}
+			continue
+		}
+		jr.process(job)
+	}
+}
+
+func (jr *jobRunner[J]) process(job *J) {
+	info := jr.queue.Info(job)
+	handler, ok := jr.handlers[info.Key]
+	if !ok {
+		jr.fail(job, info, Permanent(fmt.Errorf("no handler for %q", info.Key)))
+		return
+	}
+
+	// A job must finish inside its lease, or another worker may pick it up
+	ctx, cancel := context.WithTimeout(context.Background(), jr.lease)
+	defer cancel()
+
+	if err := handler(ctx, job); err != nil {
+		jr.fail(job, info, err)
+		return
+	}
+	if err := jr.queue.Complete(job); err != nil {
+		logrus.Errorf("Failed to complete %s %s: %v", jr.name, info.ID.Hex(), err)
+	}
+}
+
+func (jr *jobRunner[J]) fail(job *J, info jobInfo, err error) {
+	var permanent permanentError
+	if errors.As(err, &permanent) || info.Attempts >= info.MaxAttempts {
+		logrus.Errorf("☠️ %s %s (%s) given up after %d attempts: %v", jr.name, info.ID.Hex(), info.Key, info.Attempts, err)
+		if markErr := jr.queue.GiveUp(job, err.Error()); markErr != nil {
+			logrus.Errorf("Failed to give up %s %s: %v", jr.name, info.ID.Hex(), markErr)
+		}
+		return
+	}
+
+	next := time.Now().Add(retryBackoff(info.Attempts))
+	logrus.Warnf("%s %s (%s) attempt %d failed, retrying at %s: %v", jr.name, info.ID.Hex(), info.Key, info.Attempts, next.Format(time.RFC3339), err)
+	if markErr := jr.queue.Retry(job, next, err.Error()); markErr != nil {
+		logrus.Errorf("Failed to reschedule %s %s: %v", jr.name, info.ID.Hex(), markErr)
+	}
+}
+
+// retryBackoff doubles from 30s per attempt up to an hour, with up to 20% jitter
+func retryBackoff(attempt int) time.Duration {
+	const base, max = 30 * time.Second, time.Hour
+
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := max
+	if attempt < 12 {
+		delay = base << uint(attempt-1)
+		if delay > max {
+			delay = max
+		}
+	}
+	return delay + time.Duration(rand.Int63n(int64(delay)/5+1))
+}
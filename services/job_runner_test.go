@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type fakeJob struct {
+	id          primitive.ObjectID
+	kind        string
+	attempts    int
+	maxAttempts int
+}
+
+// fakeQueue hands out its jobs once each and records how each one ended
+type fakeQueue struct {
+	mu       sync.Mutex
+	pending  []*fakeJob
+	outcomes map[primitive.ObjectID]string
+}
+
+func newFakeQueue(jobs ...*fakeJob) *fakeQueue {
+	return &fakeQueue{pending: jobs, outcomes: map[primitive.ObjectID]string{}}
+}
+
+func (q *fakeQueue) ClaimNext(string, time.Duration) (*fakeJob, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.pending) == 0 {
+		return nil, nil
+	}
+	job := q.pending[0]
+	q.pending = q.pending[1:]
+	job.attempts++
+	return job, nil
+}
+
+func (q *fakeQueue) settle(job *fakeJob, outcome string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.outcomes[job.id] = outcome
+	return nil
+}
+
+func (q *fakeQueue) Complete(job *fakeJob) error { return q.settle(job, "done") }
+
+func (q *fakeQueue) Retry(job *fakeJob, _ time.Time, _ string) error { return q.settle(job, "retry") }
+
+func (q *fakeQueue) GiveUp(job *fakeJob, _ string) error { return q.settle(job, "given up") }
+
+func (q *fakeQueue) Info(job *fakeJob) jobInfo {
+	return jobInfo{ID: job.id, Key: job.kind, Attempts: job.attempts, MaxAttempts: job.maxAttempts}
+}
+
+func (q *fakeQueue) outcome(job *fakeJob) string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.outcomes[job.id]
+}
+
+func TestJobRunnerOutcomes(t *testing.T) {
+	newJob := func(kind string, attempts int) *fakeJob {
+		return &fakeJob{id: primitive.NewObjectID(), kind: kind, attempts: attempts, maxAttempts: 3}
+	}
+	succeeds := newJob("ok", 0)
+	fails := newJob("flaky", 0)
+	failsLastTime := newJob("flaky", 2)
+	permanent := newJob("broken", 0)
+	unhandled := newJob("unknown", 0)
+
+	queue := newFakeQueue(succeeds, fails, failsLastTime, permanent, unhandled)
+	runner := newJobRunner[fakeJob]("Test job", queue, 2, 10*time.Millisecond)
+	runner.handle("ok", func(context.Context, *fakeJob) error { return nil })
+	runner.handle("flaky", func(context.Context, *fakeJob) error { return errors.New("try later") })
+	runner.handle("broken", func(context.Context, *fakeJob) error { return Permanent(errors.New("never")) })
+
+	runner.start()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) && queue.outcome(unhandled) == "" {
+		time.Sleep(5 * time.Millisecond)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := runner.shutdown(ctx); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		job  *fakeJob
+		want string
+	}{
+		{"success", succeeds, "done"},
+		{"failure with attempts left", fails, "retry"},
+		{"failure on the last attempt", failsLastTime, "given up"},
+		{"permanent failure", permanent, "given up"},
+		{"no handler", unhandled, "given up"},
+	}
+	for _, tc := range cases {
+		if got := queue.outcome(tc.job); got != tc.want {
+			t.Errorf("%s: outcome = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	cases := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{0, 30 * time.Second, 36 * time.Second},
+		{1, 30 * time.Second, 36 * time.Second},
+		{3, 2 * time.Minute, 144 * time.Second},
+		{20, time.Hour, 72 * time.Minute},
+	}
+	for _, tc := range cases {
+		if got := retryBackoff(tc.attempt); got < tc.min || got > tc.max {
+			t.Errorf("retryBackoff(%d) = %s, want between %s and %s", tc.attempt, got, tc.min, tc.max)
+		}
+	}
+}
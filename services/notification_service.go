@@ -1,33 +1,162 @@
 package services
 
 import (
+	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
-	"log"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/olabanji12-ojo/CarWashApp/models"
 	"github.com/olabanji12-ojo/CarWashApp/repositories"
+	"github.com/olabanji12-ojo/CarWashApp/services/email"
+	"github.com/olabanji12-ojo/CarWashApp/services/events"
+	"github.com/olabanji12-ojo/CarWashApp/services/push"
+	"github.com/olabanji12-ojo/CarWashApp/services/sms"
+	"github.com/olabanji12-ojo/CarWashApp/templates"
 	"github.com/olabanji12-ojo/CarWashApp/utils"
+	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
-// NotificationService handles all notification operations
+// NotificationService handles all notification operations.
+// Which channels a notification goes out on is up to the recipient's preferences.
+// Emails, push and SMS are not sent inline: they are queued in the outbox together with the
+// notification and delivered by the OutboxWorker.
 type NotificationService struct {
-	userRepo *repositories.UserRepository
+	userRepo         *repositories.UserRepository
+	notificationRepo *repositories.NotificationRepository
+	outboxRepo       *repositories.OutboxRepository
+	prefsRepo        *repositories.NotificationPreferenceRepository
+	deviceRepo       *repositories.DeviceTokenRepository
+	emailSender      email.EmailSender
+	pushSender       push.PushSender
+	smsService       *SMSService
 }
 
+// smsEvents are the time-critical events also sent by SMS, with the purpose their cost is logged under
+var smsEvents = map[templates.Event]string{
+	templates.EventWorkerArrivingSoon: models.SMSPurposeWorkerOnTheWay,
+	templates.EventWorkerArrived:      models.SMSPurposeWorkerArrived,
+}
+
+// smsMaxAttempts is kept low: an SMS that is late by more than a few minutes is no longer useful
+const smsMaxAttempts = 3
+
 // NewNotificationService creates a new notification service
-func NewNotificationService(userRepo *repositories.UserRepository) *NotificationService {
+func NewNotificationService(
+	userRepo *repositories.UserRepository,
+	notificationRepo *repositories.NotificationRepository,
+	outboxRepo *repositories.OutboxRepository,
+	prefsRepo *repositories.NotificationPreferenceRepository,
+	deviceRepo *repositories.DeviceTokenRepository,
+	emailSender email.EmailSender,
+	pushSender push.PushSender,
+	smsService *SMSService,
+) *NotificationService {
 	return &NotificationService{
-		userRepo: userRepo,
+		userRepo:         userRepo,
+		notificationRepo: notificationRepo,
+		outboxRepo:       outboxRepo,
+		prefsRepo:        prefsRepo,
+		deviceRepo:       deviceRepo,
+		emailSender:      emailSender,
+		pushSender:       pushSender,
+		smsService:       smsService,
 	}
 }
 
-// CreateNotification creates a notification and optionally sends email
-func (ns *NotificationService) CreateNotification(userID primitive.ObjectID, title, message, notificationType string, sendEmail bool) error {
+// CreateNotification sends a free-form notification over the channels the user allows for its type
+func (ns *NotificationService) CreateNotification(userID primitive.ObjectID, title, message, notificationType string) error {
+	return ns.notify(userID, templates.EventCustom, notificationType, map[string]interface{}{
+		"Title":   title,
+		"Message": message,
+	})
+}
+
+// notify renders an event in the recipient's preferred language and dispatches it over the
+// channels their preferences allow for the notification type. During quiet hours the outbound
+// deliveries are held until the quiet period ends; the in-app notification is stored at once.
+func (ns *NotificationService) notify(userID primitive.ObjectID, event templates.Event, notificationType string, data map[string]interface{}) error {
+	return ns.notifyForCarwash(userID, nil, event, notificationType, data)
+}
+
+// notifyForCarwash is notify for events about a carwash's booking. SMS are only sent for
+// these, charged to the carwash's SMS budget, and are dropped rather than held during quiet hours.
+func (ns *NotificationService) notifyForCarwash(userID primitive.ObjectID, carwashID *primitive.ObjectID, event templates.Event, notificationType string, data map[string]interface{}) error {
+	prefs, err := ns.preferences(userID)
+	if err != nil {
+		logrus.Warnf("Failed to load notification preferences of %s, using defaults: %v", userID.Hex(), err)
+		prefs = models.DefaultNotificationPreferences(userID)
+	}
+	channels := prefs.For(notificationType)
+	if carwashID == nil || smsEvents[event] == "" {
+		channels.SMS = false
+	}
+	if !channels.InApp && !channels.Email && !channels.Push && !channels.SMS {
+		return nil
+	}
+
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	var unsubscribeURL string
+	if channels.Email && prefs.UnsubscribeToken != "" {
+		unsubscribeURL = ns.unsubscribeURL(prefs.UnsubscribeToken, notificationType)
+		data[templates.UnsubscribeURLKey] = unsubscribeURL
+	}
+
+	msg, err := templates.Render(event, ns.userLocale(userID), data)
+	if err != nil {
+		return err
+	}
+
+	var jobs []*models.NotificationJob
+	if channels.Email {
+		job := newEmailJob(userID, "", msg)
+		job.UnsubscribeURL = unsubscribeURL
+		jobs = append(jobs, job)
+	}
+	if channels.Push {
+		jobs = append(jobs, ns.pushJobs(userID, msg)...)
+	}
+	if until, quiet := prefs.QuietHours.Until(time.Now()); quiet {
+		for _, job := range jobs {
+			job.NextAttemptAt = until
+		}
+	} else if channels.SMS {
+		job := &models.NotificationJob{
+			UserID:    &userID,
+			CarwashID: carwashID,
+			Channel:   models.ChannelSMS,
+			TextBody:  msg.Text,
+			Template:  msg.Ref(),
+		}
+		job.SetDefaults()
+		job.MaxAttempts = smsMaxAttempts
+		jobs = append(jobs, job)
+	}
+	if len(jobs) == 0 && !channels.InApp {
+		return nil
+	}
+
+	if !channels.InApp {
+		if err := ns.outboxRepo.EnqueueJobs(jobs); err != nil {
+			return fmt.Errorf("failed to queue notification: %v", err)
+		}
+		return nil
+	}
+
 	notification := models.Notification{
 		UserID:  userID,
-		Title:   title,
-		Message: message,
+		Title:   msg.Subject,
+		Message: msg.Text,
 		Type:    notificationType,
 	}
 
@@ -36,77 +165,265 @@ func (ns *NotificationService) CreateNotification(userID primitive.ObjectID, tit
 		return fmt.Errorf("notification validation failed: %v", err)
 	}
 
-	// Save to database
-	savedNotification, err := repositories.CreateNotification(notification)
-	if err != nil {
+	// Save the notification and its deliveries together
+	if err := ns.outboxRepo.CreateNotificationWithJobs(&notification, jobs); err != nil {
 		return fmt.Errorf("failed to save notification: %v", err)
 	}
 
-	// Send email if requested (async to avoid blocking)
-	if sendEmail {
-		go ns.sendEmailAsync(savedNotification.ID, userID, title, message)
-	}
-
 	return nil
 }
 
-// sendEmailAsync sends email in background (like Django's async tasks)
-func (ns *NotificationService) sendEmailAsync(notificationID, userID primitive.ObjectID, title, message string) {
-	// Get user email
+// userLocale returns the user's preferred language, or "" for the default
+func (ns *NotificationService) userLocale(userID primitive.ObjectID) string {
 	user, err := ns.userRepo.FindUserByID(userID)
 	if err != nil {
-		log.Printf("[NotificationDebug] Failed to get user %s for email notification: %v", userID.Hex(), err)
-		return
+		return ""
+	}
+	return user.PreferredLanguage
+}
+
+// pushJobs queues one push delivery per registered device, so a retry only repeats the failed device
+func (ns *NotificationService) pushJobs(userID primitive.ObjectID, msg *templates.Message) []*models.NotificationJob {
+	devices, err := ns.deviceRepo.FindByUserID(userID)
+	if err != nil {
+		logrus.Errorf("Failed to load devices of %s: %v", userID.Hex(), err)
+		return nil
+	}
+	jobs := make([]*models.NotificationJob, 0, len(devices))
+	for _, device := range devices {
+		job := &models.NotificationJob{
+			UserID:    &userID,
+			Channel:   models.ChannelPush,
+			Recipient: device.Token,
+			Subject:   msg.Subject,
+			TextBody:  msg.Text,
+			Template:  msg.Ref(),
+		}
+		job.SetDefaults()
+		jobs = append(jobs, job)
+	}
+	return jobs
+}
+
+func newEmailJob(userID primitive.ObjectID, recipient string, msg *templates.Message) *models.NotificationJob {
+	job := &models.NotificationJob{
+		Channel:   models.ChannelEmail,
+		Recipient: recipient,
+		Subject:   msg.Subject,
+		TextBody:  msg.EmailText,
+		Body:      msg.HTML,
+		Template:  msg.Ref(),
+	}
+	if !userID.IsZero() {
+		job.UserID = &userID
+	}
+	job.SetDefaults()
+	return job
+}
+
+// DeliverEmail is the outbox handler for the email channel.
+// Addresses that bounced, complained or were rejected are not retried.
+func (ns *NotificationService) DeliverEmail(ctx context.Context, job *models.NotificationJob) error {
+	recipient := job.Recipient
+	if recipient == "" {
+		if job.UserID == nil {
+			return Permanent(errors.New("email job has no recipient"))
+		}
+		user, err := ns.userRepo.FindUserByID(*job.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to load recipient %s: %v", job.UserID.Hex(), err)
+		}
+		if user.Email == "" {
+			return Permanent(fmt.Errorf("user %s has no email address", job.UserID.Hex()))
+		}
+		recipient = user.Email
 	}
 
-	// Send email
-	err = utils.SendEmail(user.Email, title, message)
+	var headers map[string]string
+	if job.UnsubscribeURL != "" {
+		// RFC 8058 one-click unsubscribe, shown as a button by most mail clients
+		headers = map[string]string{
+			"List-Unsubscribe":      "<" + job.UnsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		}
+	}
+	err := ns.emailSender.Send(ctx, email.Message{
+		To:      recipient,
+		Subject: job.Subject,
+		Text:    job.TextBody,
+		HTML:    job.Body,
+		Headers: headers,
+	})
+	if errors.Is(err, email.ErrSuppressed) || errors.Is(err, email.ErrRejected) {
+		return Permanent(err)
+	}
 	if err != nil {
-		log.Printf("[NotificationDebug] Failed to send email to %s: %v", user.Email, err)
-		return
+		return err
+	}
+
+	if job.NotificationID != nil {
+		if err := ns.notificationRepo.MarkEmailSent(*job.NotificationID); err != nil {
+			logrus.Errorf("Failed to mark email as sent: %v", err)
+		}
+	}
+	logrus.Infof("Email notification sent successfully to %s", recipient)
+	return nil
+}
+
+// DeliverPush is the outbox handler for the push channel.
+// Tokens the provider rejects are deleted, and the job is not retried.
+func (ns *NotificationService) DeliverPush(ctx context.Context, job *models.NotificationJob) error {
+	msg := push.Message{Title: job.Subject, Body: job.TextBody}
+	if job.NotificationID != nil {
+		msg.Data = map[string]string{"notification_id": job.NotificationID.Hex()}
+	}
+
+	err := ns.pushSender.Send(ctx, job.Recipient, msg)
+	if errors.Is(err, push.ErrInvalidToken) {
+		if delErr := ns.deviceRepo.DeleteToken(job.Recipient); delErr != nil {
+			logrus.Errorf("Failed to prune invalid push token: %v", delErr)
+		} else {
+			logrus.Infof("Pruned invalid push token of user %s", job.UserID.Hex())
+		}
+		return Permanent(err)
 	}
+	return err
+}
 
-	// Mark email as sent
-	err = repositories.MarkNotificationEmailSent(notificationID)
+// DeliverSMS is the outbox handler for the SMS channel. The number is looked up when sending,
+// so a customer who just added their phone still gets the message.
+func (ns *NotificationService) DeliverSMS(ctx context.Context, job *models.NotificationJob) error {
+	if job.UserID == nil {
+		return Permanent(errors.New("sms job has no recipient"))
+	}
+	user, err := ns.userRepo.FindUserByID(*job.UserID)
 	if err != nil {
-		log.Printf("Failed to mark email as sent: %v", err)
+		return fmt.Errorf("failed to load recipient %s: %v", job.UserID.Hex(), err)
+	}
+	phone, err := utils.NormalizePhone(user.Phone)
+	if err != nil {
+		return Permanent(fmt.Errorf("user %s has no usable phone number", job.UserID.Hex()))
+	}
+
+	err = ns.smsService.Send(ctx, SMSRequest{
+		To:        phone,
+		Body:      job.TextBody,
+		Purpose:   smsEvents[templates.Event(path.Base(job.Template))],
+		UserID:    job.UserID,
+		CarwashID: job.CarwashID,
+	})
+	if errors.Is(err, ErrSMSBudgetExceeded) || errors.Is(err, sms.ErrRejected) {
+		return Permanent(err)
 	}
+	return err
+}
 
-	log.Printf("Email notification sent successfully to %s", user.Email)
+// Subscribe sends the booking notifications when the matching domain events are published
+func (ns *NotificationService) Subscribe(bus events.Bus) {
+	events.On(bus, func(e events.BookingCreated) {
+		// Notify Business Owner
+		if !e.Carwash.OwnerID.IsZero() {
+			ns.SendNewBookingToBusiness(e.Carwash.OwnerID, e.CustomerName, "New Booking")
+		} else {
+			logrus.Warnf("Carwash %s has no OwnerID, cannot send notification", e.Carwash.Name)
+		}
+
+		// Notify Customer; channels follow their notification preferences
+		ns.SendBookingConfirmation(e.Booking, e.CustomerName, e.Carwash.Name)
+	})
+
+	events.On(bus, func(e events.BookingStatusChanged) {
+		// Customers hear about the steps the carwash takes, not about their own edits or cancellations
+		if e.Source != events.SourceStatusUpdate {
+			return
+		}
+		switch e.Booking.Status {
+		case "confirmed":
+			ns.SendBookingAccepted(e.Booking, e.CarwashName)
+		case "cancelled":
+			ns.SendBookingRejected(e.Booking, "Cancelled by business")
+		case "completed":
+			ns.SendWashCompleted(e.Booking, e.CarwashName)
+		}
+	})
 }
 
 // BOOKING NOTIFICATION TRIGGERS (Like Django Signals)
 
 // SendBookingConfirmation - triggered when booking is created
-func (ns *NotificationService) SendBookingConfirmation(booking *models.Booking) {
-	title := "Booking Confirmation"
-	message := fmt.Sprintf("Your carwash booking has been confirmed for %s", booking.BookingTime.Format("Jan 2, 2006 at 3:04 PM"))
-
-	err := ns.CreateNotification(booking.UserID, title, message, models.NotificationTypeBooking, true)
+func (ns *NotificationService) SendBookingConfirmation(booking *models.Booking, customerName, carwashName string) {
+	err := ns.notify(booking.UserID, templates.EventBookingConfirmation, models.NotificationTypeBooking, map[string]interface{}{
+		"Name":    customerName,
+		"Carwash": carwashName,
+		"Time":    booking.BookingTime,
+	})
 	if err != nil {
-		log.Printf("Failed to send booking confirmation: %v", err)
+		logrus.Errorf("Failed to send booking confirmation: %v", err)
 	}
 }
 
 // SendBookingAccepted - triggered when business accepts booking
 func (ns *NotificationService) SendBookingAccepted(booking *models.Booking, carwashName string) {
-	title := "Booking Accepted!"
-	message := fmt.Sprintf("Great news! %s has accepted your booking for %s", carwashName, booking.BookingTime.Format("Jan 2, 2006 at 3:04 PM"))
-
-	err := ns.CreateNotification(booking.UserID, title, message, models.NotificationTypeBooking, true)
+	err := ns.notify(booking.UserID, templates.EventBookingAccepted, models.NotificationTypeBooking, map[string]interface{}{
+		"Carwash": carwashName,
+		"Time":    booking.BookingTime,
+	})
 	if err != nil {
-		log.Printf("Failed to send booking accepted notification: %v", err)
+		logrus.Errorf("Failed to send booking accepted notification: %v", err)
 	}
 }
 
 // SendBookingRejected - triggered when business rejects booking
 func (ns *NotificationService) SendBookingRejected(booking *models.Booking, reason string) {
-	title := "Booking Update"
-	message := fmt.Sprintf("Unfortunately, your booking for %s could not be confirmed. Reason: %s", booking.BookingTime.Format("Jan 2, 2006"), reason)
+	err := ns.notify(booking.UserID, templates.EventBookingRejected, models.NotificationTypeBooking, map[string]interface{}{
+		"Time":   booking.BookingTime,
+		"Reason": reason,
+	})
+	if err != nil {
+		logrus.Errorf("Failed to send booking rejected notification: %v", err)
+	}
+}
+
+// SendWashCompleted - triggered when the business marks a booking completed; channels follow the customer's preferences
+func (ns *NotificationService) SendWashCompleted(booking *models.Booking, carwashName string) {
+	err := ns.notify(booking.UserID, templates.EventWashCompleted, models.NotificationTypeBooking, map[string]interface{}{
+		"Carwash": carwashName,
+	})
+	if err != nil {
+		logrus.Errorf("Failed to send wash completed notification: %v", err)
+	}
+}
+
+// SendBookingReminder - triggered by the scheduler ahead of a booking.
+// Unlike the other triggers it returns the error, so the scheduler can retry.
+func (ns *NotificationService) SendBookingReminder(booking *models.Booking, carwashName string, lead time.Duration) error {
+	hours, minutes := reminderLead(lead)
+	return ns.notify(booking.UserID, templates.EventBookingReminder, models.NotificationTypeBooking, map[string]interface{}{
+		"Carwash": carwashName,
+		"Time":    booking.BookingTime,
+		"Hours":   hours,
+		"Minutes": minutes,
+	})
+}
+
+// SendWorkerArrivingSoon - triggered when a home-service worker's ETA drops below a few minutes
+func (ns *NotificationService) SendWorkerArrivingSoon(booking *models.Booking, etaMinutes int) {
+	err := ns.notifyForCarwash(booking.UserID, &booking.CarwashID, templates.EventWorkerArrivingSoon, models.NotificationTypeWorker, map[string]interface{}{
+		"ETA": etaMinutes,
+	})
+	if err != nil {
+		logrus.Errorf("Failed to send worker arriving notification: %v", err)
+	}
+}
 
-	err := ns.CreateNotification(booking.UserID, title, message, models.NotificationTypeBooking, true)
+// SendWorkerArrived - triggered when a home-service worker reaches the customer's location.
+// It repeats the handshake code the customer gives the worker once the wash is done.
+func (ns *NotificationService) SendWorkerArrived(booking *models.Booking) {
+	err := ns.notifyForCarwash(booking.UserID, &booking.CarwashID, templates.EventWorkerArrived, models.NotificationTypeWorker, map[string]interface{}{
+		"Code": booking.VerificationCode,
+	})
 	if err != nil {
-		log.Printf("Failed to send booking rejected notification: %v", err)
+		logrus.Errorf("Failed to send worker arrived notification: %v", err)
 	}
 }
 
@@ -114,37 +431,30 @@ func (ns *NotificationService) SendBookingRejected(booking *models.Booking, reas
 
 // SendOrderCreated - triggered when order is created from booking
 func (ns *NotificationService) SendOrderCreated(order *models.Order) {
-	title := "Order Created"
-	message := "Your booking has been converted to an active order. We'll notify you when a worker is assigned."
-
-	err := ns.CreateNotification(order.UserID, title, message, models.NotificationTypeOrder, true)
+	err := ns.notify(order.UserID, templates.EventOrderCreated, models.NotificationTypeOrder, nil)
 	if err != nil {
-		log.Printf("Failed to send order created notification: %v", err)
+		logrus.Errorf("Failed to send order created notification: %v", err)
 	}
 }
 
 // SendWorkerAssigned - triggered when worker is assigned to order
 func (ns *NotificationService) SendWorkerAssigned(order *models.Order, workerName string) {
-	title := "Worker Assigned"
-	message := fmt.Sprintf("Good news! %s has been assigned to your order and will be with you soon.", workerName)
-
-	err := ns.CreateNotification(order.UserID, title, message, models.NotificationTypeWorker, true)
+	err := ns.notify(order.UserID, templates.EventWorkerAssigned, models.NotificationTypeWorker, map[string]interface{}{
+		"Worker": workerName,
+	})
 	if err != nil {
-		log.Printf("Failed to send worker assigned notification: %v", err)
+		logrus.Errorf("Failed to send worker assigned notification: %v", err)
 	}
 }
 
 // SendOrderStatusUpdate - triggered when order status changes
 func (ns *NotificationService) SendOrderStatusUpdate(order *models.Order, newStatus, details string) {
-	title := fmt.Sprintf("Order %s", newStatus)
-	message := details
-	if message == "" {
-		message = fmt.Sprintf("Your order status has been updated to: %s", newStatus)
-	}
-
-	err := ns.CreateNotification(order.UserID, title, message, models.NotificationTypeOrder, true)
+	err := ns.notify(order.UserID, templates.EventOrderStatusUpdate, models.NotificationTypeOrder, map[string]interface{}{
+		"Status":  newStatus,
+		"Details": details,
+	})
 	if err != nil {
-		log.Printf("Failed to send order status update: %v", err)
+		logrus.Errorf("Failed to send order status update: %v", err)
 	}
 }
 
@@ -152,34 +462,267 @@ func (ns *NotificationService) SendOrderStatusUpdate(order *models.Order, newSta
 
 // SendNewBookingToBusiness - notify business of new booking
 func (ns *NotificationService) SendNewBookingToBusiness(businessUserID primitive.ObjectID, customerName, serviceName string) {
-	title := "New Booking Received"
-	message := fmt.Sprintf("You have a new booking from %s for %s. Please review and accept/reject.", customerName, serviceName)
+	err := ns.notify(businessUserID, templates.EventNewBookingBusiness, models.NotificationTypeBooking, map[string]interface{}{
+		"Customer": customerName,
+		"Service":  serviceName,
+	})
+	if err != nil {
+		logrus.Errorf("Failed to send new booking notification to business: %v", err)
+	}
+}
 
-	err := ns.CreateNotification(businessUserID, title, message, models.NotificationTypeBooking, true)
+// NotificationQuery selects a page of a user's notifications
+type NotificationQuery struct {
+	Type     string
+	Read     *bool
+	Archived bool
+	Cursor   string // next_cursor of the previous page
+	Limit    int
+}
+
+// NotificationPage is one page of a user's notifications, newest first.
+// NextCursor is empty on the last page.
+type NotificationPage struct {
+	Notifications []models.Notification `json:"notifications"`
+	NextCursor    string                `json:"next_cursor,omitempty"`
+}
+
+// maxBulkNotifications caps how many notifications one bulk request may touch
+const maxBulkNotifications = 100
+
+// ListNotifications returns a page of the user's inbox, or their archive
+func (ns *NotificationService) ListNotifications(userID string, query NotificationQuery) (*NotificationPage, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+	if query.Type != "" && query.Type != models.NotificationTypeGeneral && !models.IsConfigurableNotificationType(query.Type) {
+		return nil, fmt.Errorf("unknown notification type %q", query.Type)
+	}
+	if query.Limit < 1 || query.Limit > 100 {
+		query.Limit = 20
+	}
+
+	filter := repositories.NotificationFilter{Type: query.Type, Read: query.Read, Archived: query.Archived}
+	if query.Cursor != "" {
+		filter.BeforeTime, filter.BeforeID, err = decodeNotificationCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// One extra tells whether there is a next page
+	notifications, err := ns.notificationRepo.FindByUser(userObjID, filter, int64(query.Limit+1))
 	if err != nil {
-		log.Printf("Failed to send new booking notification to business: %v", err)
+		return nil, err
+	}
+
+	page := &NotificationPage{Notifications: notifications}
+	if len(notifications) > query.Limit {
+		page.Notifications = notifications[:query.Limit]
+		last := page.Notifications[query.Limit-1]
+		page.NextCursor = encodeNotificationCursor(last.CreatedAt, last.ID)
 	}
+	return page, nil
+}
+
+// encodeNotificationCursor makes the opaque cursor pointing after a notification
+func encodeNotificationCursor(createdAt time.Time, id primitive.ObjectID) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(createdAt.UnixMilli(), 10) + ":" + id.Hex()))
 }
 
-// GetUserNotifications gets notifications for a user
-func (ns *NotificationService) GetUserNotifications(userID string, limit int) ([]models.Notification, error) {
-	return repositories.GetNotificationsByUserID(userID, limit)
+func decodeNotificationCursor(cursor string) (time.Time, primitive.ObjectID, error) {
+	invalid := errors.New("invalid cursor")
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, primitive.NilObjectID, invalid
+	}
+	millis, hexID, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return time.Time{}, primitive.NilObjectID, invalid
+	}
+	ms, err := strconv.ParseInt(millis, 10, 64)
+	if err != nil {
+		return time.Time{}, primitive.NilObjectID, invalid
+	}
+	id, err := primitive.ObjectIDFromHex(hexID)
+	if err != nil {
+		return time.Time{}, primitive.NilObjectID, invalid
+	}
+	return time.UnixMilli(ms), id, nil
 }
 
 // MarkAsRead marks a notification as read
 func (ns *NotificationService) MarkAsRead(notificationID string) error {
-	return repositories.MarkNotificationAsRead(notificationID)
+	objID, err := primitive.ObjectIDFromHex(notificationID)
+	if err != nil {
+		return errors.New("invalid notification ID")
+	}
+	return ns.notificationRepo.MarkAsRead(objID)
 }
 
 // GetUnreadCount gets unread notification count for a user
 func (ns *NotificationService) GetUnreadCount(userID string) (int64, error) {
-	return repositories.GetUnreadNotificationCount(userID)
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return 0, errors.New("invalid user ID")
+	}
+	return ns.notificationRepo.CountUnread(objID)
 }
 
 // MarkAllAsRead marks all notifications as read for a user
 func (ns *NotificationService) MarkAllAsRead(userID string) error {
-	return repositories.MarkAllNotificationsAsRead(userID)
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return errors.New("invalid user ID")
+	}
+	return ns.notificationRepo.MarkAllAsRead(objID)
+}
+
+// ArchiveNotifications moves the given notifications of the user out of their inbox.
+// IDs that aren't the user's are ignored; the count of archived notifications is returned.
+func (ns *NotificationService) ArchiveNotifications(userID string, notificationIDs []string) (int64, error) {
+	userObjID, ids, err := parseBulkNotificationIDs(userID, notificationIDs)
+	if err != nil {
+		return 0, err
+	}
+	return ns.notificationRepo.Archive(userObjID, ids)
+}
+
+// DeleteNotifications deletes the given notifications of the user.
+// IDs that aren't the user's are ignored; the count of deleted notifications is returned.
+func (ns *NotificationService) DeleteNotifications(userID string, notificationIDs []string) (int64, error) {
+	userObjID, ids, err := parseBulkNotificationIDs(userID, notificationIDs)
+	if err != nil {
+		return 0, err
+	}
+	return ns.notificationRepo.Delete(userObjID, ids)
 }
 
-// Global notification service instance
-var NotificationSvc = &NotificationService{}
+func parseBulkNotificationIDs(userID string, notificationIDs []string) (primitive.ObjectID, []primitive.ObjectID, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return primitive.NilObjectID, nil, errors.New("invalid user ID")
+	}
+	if len(notificationIDs) == 0 {
+		return primitive.NilObjectID, nil, errors.New("ids is required")
+	}
+	if len(notificationIDs) > maxBulkNotifications {
+		return primitive.NilObjectID, nil, fmt.Errorf("at most %d notifications at a time", maxBulkNotifications)
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(notificationIDs))
+	for _, id := range notificationIDs {
+		objID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return primitive.NilObjectID, nil, fmt.Errorf("invalid notification ID %q", id)
+		}
+		ids = append(ids, objID)
+	}
+	return userObjID, ids, nil
+}
+
+// NOTIFICATION PREFERENCES
+
+// NotificationPreferencesInput is a partial update of a user's preferences.
+// Notification types left out keep their current channels.
+type NotificationPreferencesInput struct {
+	Events     map[string]models.ChannelPreferences `json:"events"`
+	QuietHours *models.QuietHours                   `json:"quiet_hours"`
+}
+
+// preferences returns the user's preferences, creating the defaults on first use
+func (ns *NotificationService) preferences(userID primitive.ObjectID) (*models.NotificationPreferences, error) {
+	defaults := models.DefaultNotificationPreferences(userID)
+	token, err := utils.GenerateSecureToken(24)
+	if err != nil {
+		return nil, err
+	}
+	defaults.UnsubscribeToken = token
+	return ns.prefsRepo.FindOrCreate(defaults)
+}
+
+// GetPreferences returns the authenticated user's notification preferences
+func (ns *NotificationService) GetPreferences(userID string) (*models.NotificationPreferences, error) {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+	return ns.preferences(objID)
+}
+
+// UpdatePreferences changes channels per notification type and the quiet hours
+func (ns *NotificationService) UpdatePreferences(userID string, input NotificationPreferencesInput) (*models.NotificationPreferences, error) {
+	prefs, err := ns.GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults := models.DefaultNotificationPreferences(prefs.UserID)
+	events := map[string]models.ChannelPreferences{}
+	for notificationType, channels := range defaults.Events {
+		if current, ok := prefs.Events[notificationType]; ok {
+			channels = current
+		}
+		events[notificationType] = channels
+	}
+	for notificationType, channels := range input.Events {
+		events[notificationType] = channels
+	}
+	prefs.Events = events
+	if input.QuietHours != nil {
+		prefs.QuietHours = *input.QuietHours
+		if prefs.QuietHours.Timezone == "" {
+			prefs.QuietHours.Timezone = "UTC"
+		}
+	}
+	if err := prefs.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := ns.prefsRepo.Update(prefs.UserID, prefs.Events, prefs.QuietHours); err != nil {
+		return nil, errors.New("failed to save notification preferences")
+	}
+	prefs.UpdatedAt = time.Now()
+	return prefs, nil
+}
+
+// Unsubscribe handles an email unsubscribe link. It turns email off for the notification type
+// the email was about, or for every type when the link names none.
+func (ns *NotificationService) Unsubscribe(token, notificationType string) error {
+	if token == "" {
+		return errors.New("unsubscribe token is required")
+	}
+	prefs, err := ns.prefsRepo.FindByUnsubscribeToken(token)
+	if err == mongo.ErrNoDocuments {
+		return errors.New("invalid unsubscribe link")
+	}
+	if err != nil {
+		return err
+	}
+
+	types := models.NotificationEventTypes
+	if models.IsConfigurableNotificationType(notificationType) {
+		types = []string{notificationType}
+	}
+	for _, t := range types {
+		if err := ns.prefsRepo.DisableChannel(prefs.UserID, t, models.ChannelEmail); err != nil {
+			return errors.New("failed to unsubscribe")
+		}
+	}
+	logrus.Infof("User %s unsubscribed from %s emails", prefs.UserID.Hex(), strings.Join(types, ", "))
+	return nil
+}
+
+// unsubscribeURL is the one-click link put in every notification email
+func (ns *NotificationService) unsubscribeURL(token, notificationType string) string {
+	baseURL := os.Getenv("API_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080"
+	}
+	query := url.Values{"token": {token}}
+	if models.IsConfigurableNotificationType(notificationType) {
+		query.Set("type", notificationType)
+	}
+	return strings.TrimRight(baseURL, "/") + "/api/notifications/unsubscribe?" + query.Encode()
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/olabanji12-ojo/CarWashApp/models"
 	"github.com/olabanji12-ojo/CarWashApp/repositories"
+	"github.com/olabanji12-ojo/CarWashApp/services/events"
 	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -14,10 +15,11 @@ import (
 type OrderService struct {
 	orderRepository repositories.OrderRepository
 	bookingRepository repositories.BookingRepository
+	bus events.Bus
 }
 
-func NewOrderService(orderRepository repositories.OrderRepository) *OrderService {
-	return &OrderService{orderRepository: orderRepository}
+func NewOrderService(orderRepository repositories.OrderRepository, bus events.Bus) *OrderService {
+	return &OrderService{orderRepository: orderRepository, bus: bus}
 }
 
 
@@ -115,7 +117,21 @@ func(os *OrderService) UpdateOrderStatus(orderID string, newStatus string) error
 		return errors.New("invalid order ID")
 	}
 
-	return os.orderRepository.UpdateOrderStatus(objID, newStatus)
+	order, err := os.orderRepository.GetOrderByID(objID)
+	if err != nil {
+		return errors.New("order not found")
+	}
+
+	if err := os.orderRepository.UpdateOrderStatus(objID, newStatus); err != nil {
+		return err
+	}
+
+	// Only the first completion counts
+	if newStatus == "completed" && order.Status != "completed" {
+		order.Status = newStatus
+		os.bus.Publish(events.OrderCompleted{Order: order, OccurredAt: time.Now()})
+	}
+	return nil
 }
 
 
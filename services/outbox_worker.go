@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/repositories"
+	"github.com/sirupsen/logrus"
+)
+
+// DeliveryHandler delivers one outbox job over its channel
+type DeliveryHandler func(ctx context.Context, job *models.NotificationJob) error
+
+// OutboxWorker is a pool of goroutines working off the notification outbox.
+// Failed deliveries are retried with exponential backoff and dead-lettered after MaxAttempts.
+type OutboxWorker struct {
+	runner *jobRunner[models.NotificationJob]
+}
+
+// NewOutboxWorker creates a worker pool of the given size
+func NewOutboxWorker(outboxRepo *repositories.OutboxRepository, concurrency int) *OutboxWorker {
+	return &OutboxWorker{runner: newJobRunner[models.NotificationJob]("Outbox job", outboxQueue{outboxRepo}, concurrency, 2*time.Second)}
+}
+
+// Handle registers the delivery handler for a channel
+func (ow *OutboxWorker) Handle(channel string, handler DeliveryHandler) {
+	ow.runner.handle(channel, handler)
+}
+
+// Start launches the pool
+func (ow *OutboxWorker) Start() {
+	ow.runner.start()
+	logrus.Infof("📬 Notification outbox started with %d workers", ow.runner.concurrency)
+}
+
+// Shutdown stops claiming new jobs and waits for in-flight deliveries to finish.
+// Jobs still running when ctx expires keep their lease and are picked up again after it lapses.
+func (ow *OutboxWorker) Shutdown(ctx context.Context) error {
+	if err := ow.runner.shutdown(ctx); err != nil {
+		return err
+	}
+	logrus.Info("📬 Notification outbox drained")
+	return nil
+}
+
+// outboxQueue runs the outbox through a jobRunner; giving up dead-letters the job
+type outboxQueue struct {
+	repo *repositories.OutboxRepository
+}
+
+func (q outboxQueue) ClaimNext(workerID string, lease time.Duration) (*models.NotificationJob, error) {
+	return q.repo.ClaimNext(workerID, lease)
+}
+
+func (q outboxQueue) Complete(job *models.NotificationJob) error {
+	return q.repo.MarkSent(job)
+}
+
+func (q outboxQueue) Retry(job *models.NotificationJob, at time.Time, lastError string) error {
+	return q.repo.MarkRetry(job, at, lastError)
+}
+
+func (q outboxQueue) GiveUp(job *models.NotificationJob, lastError string) error {
+	return q.repo.MarkDead(job, lastError)
+}
+
+func (q outboxQueue) Info(job *models.NotificationJob) jobInfo {
+	return jobInfo{ID: job.ID, Key: job.Channel, Attempts: job.Attempts, MaxAttempts: job.MaxAttempts}
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/repositories"
+	"github.com/olabanji12-ojo/CarWashApp/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// PhoneActionLoginCode is the rate-limit action for sending phone login codes
+const PhoneActionLoginCode = "phone_login_code"
+
+// PhoneLoginService handles passwordless login with one-time codes sent by SMS
+type PhoneLoginService struct {
+	userRepo *repositories.UserRepository
+	otpRepo  *repositories.PhoneOTPRepository
+	sms      *SMSService
+	throttle *AuthThrottleService
+}
+
+// NewPhoneLoginService creates a new PhoneLoginService instance
+func NewPhoneLoginService(userRepo *repositories.UserRepository, otpRepo *repositories.PhoneOTPRepository, smsService *SMSService, throttle *AuthThrottleService) *PhoneLoginService {
+	return &PhoneLoginService{userRepo: userRepo, otpRepo: otpRepo, sms: smsService, throttle: throttle}
+}
+
+// RequestCode sends a login code to the phone number if it belongs to an account.
+// It reports success either way so the endpoint can't be used to discover registered numbers.
+func (ps *PhoneLoginService) RequestCode(phone, ip string) error {
+	normalized, err := utils.NormalizePhone(phone)
+	if err != nil {
+		return err
+	}
+
+	if err := ps.throttle.AllowMessageRequest(PhoneActionLoginCode, normalized, ip); err != nil {
+		return err
+	}
+
+	user, err := ps.userRepo.FindUserByPhone(normalized)
+	if err != nil {
+		logrus.Infof("Phone login requested for unknown number %s", normalized)
+		return nil
+	}
+	if user.Status == "suspended" || user.Status == "invited" {
+		logrus.Infof("Phone login requested for %s account %s", user.Status, user.ID.Hex())
+		return nil
+	}
+
+	code, err := utils.GenerateNumericCode(models.PhoneOTPLength)
+	if err != nil {
+		logrus.Error("Failed to generate phone login code: ", err)
+		return errors.New("failed to send code")
+	}
+
+	otp := &models.PhoneOTP{
+		Phone:    normalized,
+		UserID:   user.ID,
+		CodeHash: utils.HashToken(code),
+	}
+	otp.SetDefaults()
+	if err := ps.otpRepo.ReplaceOTP(otp); err != nil {
+		logrus.Error("Failed to save phone login code: ", err)
+		return errors.New("failed to send code")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	message := fmt.Sprintf("Your CarWashApp login code is %s. It expires in %d minutes.", code, int(models.PhoneOTPTTL.Minutes()))
+	err = ps.sms.Send(ctx, SMSRequest{
+		To:      normalized,
+		Body:    message,
+		Purpose: models.SMSPurposeVerificationCode,
+		UserID:  &user.ID,
+	})
+	if err != nil {
+		logrus.Error("Failed to send phone login code: ", err)
+		return errors.New("failed to send code")
+	}
+	return nil
+}
+
+// VerifyCode checks a login code and returns the user it belongs to.
+// Each code allows a limited number of guesses before a new one must be requested.
+func (ps *PhoneLoginService) VerifyCode(phone, code string) (*models.User, error) {
+	normalized, err := utils.NormalizePhone(phone)
+	if err != nil {
+		return nil, err
+	}
+
+	otp, err := ps.otpRepo.ConsumeAttempt(normalized)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(otp.CodeHash), []byte(utils.HashToken(code))) != 1 {
+		if remaining := models.PhoneOTPMaxAttempts - otp.Attempts; remaining > 0 {
+			return nil, fmt.Errorf("invalid code, %d attempt(s) left", remaining)
+		}
+		return nil, errors.New("invalid code, please request a new one")
+	}
+
+	if err := ps.otpRepo.DeleteOTP(otp.ID); err != nil {
+		logrus.Warn("Failed to delete used phone login code: ", err)
+	}
+
+	user, err := ps.userRepo.FindUserByID(otp.UserID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.Status == "suspended" {
+		return nil, errors.New("account suspended")
+	}
+	return user, nil
+}
+
+// normalizedPhone normalizes a phone number where possible, keeping the input otherwise
+func normalizedPhone(phone string) string {
+	if phone == "" {
+		return ""
+	}
+	if normalized, err := utils.NormalizePhone(phone); err == nil {
+		return normalized
+	}
+	return phone
+}
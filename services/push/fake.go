@@ -0,0 +1,55 @@
+// services/push/fake.go
+package push
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Delivery is a push notification recorded by FakeSender
+type Delivery struct {
+	Token   string
+	Message Message
+}
+
+// FakeSender logs push notifications instead of sending them and keeps them in memory,
+// for local development and tests. Tokens passed to Invalidate are rejected like a real provider would.
+type FakeSender struct {
+	mu      sync.Mutex
+	sent    []Delivery
+	invalid map[string]bool
+}
+
+// NewFakeSender creates a sender that only logs
+func NewFakeSender() *FakeSender {
+	return &FakeSender{invalid: map[string]bool{}}
+}
+
+// Send implements PushSender
+func (s *FakeSender) Send(ctx context.Context, token string, msg Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.invalid[token] {
+		return ErrInvalidToken
+	}
+	s.sent = append(s.sent, Delivery{Token: token, Message: msg})
+	logrus.Infof("🔔 [Push] to %s: %s - %s", token, msg.Title, msg.Body)
+	return nil
+}
+
+// Invalidate makes later sends to token fail with ErrInvalidToken
+func (s *FakeSender) Invalidate(token string) {
+	s.mu.Lock()
+	s.invalid[token] = true
+	s.mu.Unlock()
+}
+
+// Sent returns a copy of every notification sent so far
+func (s *FakeSender) Sent() []Delivery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Delivery(nil), s.sent...)
+}
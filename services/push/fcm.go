@@ -0,0 +1,123 @@
+// services/push/fcm.go
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	fcmScope    = "https://www.googleapis.com/auth/firebase.messaging"
+	fcmEndpoint = "https://fcm.googleapis.com/v1/projects/%s/messages:send"
+)
+
+// FCMSender sends through the Firebase Cloud Messaging HTTP v1 API.
+// Any gateway that speaks the same API can be used by overriding the endpoint.
+type FCMSender struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewFCMSender authenticates with a service account key (the JSON file downloaded from Firebase)
+func NewFCMSender(ctx context.Context, projectID string, credentialsJSON []byte) (*FCMSender, error) {
+	creds, err := google.CredentialsFromJSON(ctx, credentialsJSON, fcmScope)
+	if err != nil {
+		return nil, fmt.Errorf("invalid FCM credentials: %v", err)
+	}
+	return NewFCMSenderWithClient(fmt.Sprintf(fcmEndpoint, projectID), oauth2.NewClient(ctx, creds.TokenSource)), nil
+}
+
+// NewFCMSenderWithClient sends to endpoint with a client that already adds authentication
+func NewFCMSenderWithClient(endpoint string, client *http.Client) *FCMSender {
+	return &FCMSender{endpoint: endpoint, client: client}
+}
+
+// Client returns the authenticated HTTP client
+func (s *FCMSender) Client() *http.Client {
+	return s.client
+}
+
+type fcmRequest struct {
+	Message fcmMessage `json:"message"`
+}
+
+type fcmMessage struct {
+	Token        string            `json:"token"`
+	Notification fcmNotification   `json:"notification"`
+	Data         map[string]string `json:"data,omitempty"`
+}
+
+type fcmNotification struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type fcmErrorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+		Details []struct {
+			Type      string `json:"@type"`
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+// Send implements PushSender
+func (s *FCMSender) Send(ctx context.Context, token string, msg Message) error {
+	payload, err := json.Marshal(fcmRequest{Message: fcmMessage{
+		Token:        token,
+		Notification: fcmNotification{Title: msg.Title, Body: msg.Body},
+		Data:         msg.Data,
+	}})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fcm request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var fcmErr fcmErrorResponse
+	json.Unmarshal(body, &fcmErr)
+	if isInvalidTokenError(resp.StatusCode, fcmErr) {
+		return ErrInvalidToken
+	}
+	return fmt.Errorf("fcm returned %d: %s", resp.StatusCode, strings.TrimSpace(fcmErr.Error.Message))
+}
+
+// isInvalidTokenError recognises FCM's answers for tokens that will never work again
+func isInvalidTokenError(status int, resp fcmErrorResponse) bool {
+	for _, detail := range resp.Error.Details {
+		switch detail.ErrorCode {
+		case "UNREGISTERED", "SENDER_ID_MISMATCH":
+			return true
+		case "INVALID_ARGUMENT":
+			// also used for malformed payloads; only a bad token is worth pruning for
+			return strings.Contains(strings.ToLower(resp.Error.Message), "registration token")
+		}
+	}
+	return status == http.StatusNotFound && resp.Error.Status == "NOT_FOUND"
+}
@@ -0,0 +1,25 @@
+// services/push/sender.go
+package push
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrInvalidToken means the provider no longer knows the device token (app uninstalled,
+// token rotated, wrong project). The token should be deleted rather than retried.
+var ErrInvalidToken = errors.New("push token is no longer valid")
+
+// Message is one push notification
+type Message struct {
+	Title string
+	Body  string
+	Data  map[string]string // delivered to the app alongside the notification
+}
+
+// PushSender defines the interface for delivering push notifications to one device.
+// FCMSender talks to Firebase Cloud Messaging; FakeSender is used locally.
+type PushSender interface {
+	// Send delivers msg to the device registered with token
+	Send(ctx context.Context, token string, msg Message) error
+}
@@ -7,16 +7,18 @@ import (
 
 	"github.com/olabanji12-ojo/CarWashApp/models"
 	"github.com/olabanji12-ojo/CarWashApp/repositories"
+	"github.com/olabanji12-ojo/CarWashApp/services/events"
 	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type ReviewService struct {
 	reviewRepository repositories.ReviewRepository
+	bus              events.Bus
 }
 
-func NewReviewService(reviewRepository repositories.ReviewRepository) *ReviewService {
-	return &ReviewService{reviewRepository: reviewRepository}
+func NewReviewService(reviewRepository repositories.ReviewRepository, bus events.Bus) *ReviewService {
+	return &ReviewService{reviewRepository: reviewRepository, bus: bus}
 }
 
 // LeaveReview allows a user to review a carwash after a completed order
@@ -62,6 +64,8 @@ func (rs *ReviewService) CreateReview(userID string, input models.Review) (*mode
 		return nil, errors.New("failed to create review")
 	}
 
+	rs.bus.Publish(events.ReviewCreated{Review: &newReview, OccurredAt: time.Now()})
+
 	return &newReview, nil
 }
 
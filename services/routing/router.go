@@ -0,0 +1,22 @@
+// services/routing/router.go
+package routing
+
+import (
+	"context"
+
+	"github.com/olabanji12-ojo/CarWashApp/services/geocoding"
+)
+
+// Estimate is the travel estimate between two points
+type Estimate struct {
+	DistanceKm      float64 `json:"distance_km"`
+	DurationMinutes int     `json:"duration_minutes"`
+}
+
+// Router defines the interface for travel time estimation.
+// A real routing provider (Google Directions, OSRM, ...) can implement this
+// to account for roads and traffic.
+type Router interface {
+	// Estimate returns distance and travel time from origin to destination
+	Estimate(ctx context.Context, origin, destination geocoding.Location) (*Estimate, error)
+}
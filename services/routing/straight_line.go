@@ -0,0 +1,27 @@
+// services/routing/straight_line.go
+package routing
+
+import (
+	"context"
+
+	"github.com/olabanji12-ojo/CarWashApp/services/geocoding"
+	"github.com/olabanji12-ojo/CarWashApp/utils"
+)
+
+// StraightLineRouter estimates travel time from the great-circle distance
+// and an average city driving speed. It needs no external API.
+type StraightLineRouter struct{}
+
+// NewStraightLineRouter creates the default router
+func NewStraightLineRouter() *StraightLineRouter {
+	return &StraightLineRouter{}
+}
+
+// Estimate implements Router using utils.CalculateDistance and utils.EstimateTravelTimeMinutes
+func (r *StraightLineRouter) Estimate(ctx context.Context, origin, destination geocoding.Location) (*Estimate, error) {
+	distanceKm := utils.CalculateDistance(origin.Lat, origin.Lng, destination.Lat, destination.Lng)
+	return &Estimate{
+		DistanceKm:      distanceKm,
+		DurationMinutes: utils.EstimateTravelTimeMinutes(distanceKm),
+	}, nil
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/repositories"
+	"github.com/sirupsen/logrus"
+)
+
+// JobHandler runs one scheduled job of its kind
+type JobHandler func(ctx context.Context, job *models.ScheduledJob) error
+
+// Scheduler is a pool of goroutines running scheduled jobs when they fall due.
+// Every instance can run one; the job lease makes sure each job runs on one of them.
+// Failed jobs are retried with the outbox's backoff and marked failed after MaxAttempts.
+type Scheduler struct {
+	runner *jobRunner[models.ScheduledJob]
+}
+
+// NewScheduler creates a scheduler pool of the given size
+func NewScheduler(jobRepo *repositories.ScheduledJobRepository, concurrency int) *Scheduler {
+	return &Scheduler{runner: newJobRunner[models.ScheduledJob]("Scheduled job", scheduledJobQueue{jobRepo}, concurrency, 5*time.Second)}
+}
+
+// Handle registers the handler for a job kind
+func (s *Scheduler) Handle(kind string, handler JobHandler) {
+	s.runner.handle(kind, handler)
+}
+
+// Start launches the pool
+func (s *Scheduler) Start() {
+	s.runner.start()
+	logrus.Infof("⏰ Job scheduler started with %d workers", s.runner.concurrency)
+}
+
+// Shutdown stops claiming new jobs and waits for running ones to finish.
+// Jobs still running when ctx expires keep their lease and are picked up again after it lapses.
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	if err := s.runner.shutdown(ctx); err != nil {
+		return err
+	}
+	logrus.Info("⏰ Job scheduler stopped")
+	return nil
+}
+
+// scheduledJobQueue runs scheduled jobs through a jobRunner; giving up marks the job failed
+type scheduledJobQueue struct {
+	repo *repositories.ScheduledJobRepository
+}
+
+func (q scheduledJobQueue) ClaimNext(workerID string, lease time.Duration) (*models.ScheduledJob, error) {
+	return q.repo.ClaimNext(workerID, lease)
+}
+
+func (q scheduledJobQueue) Complete(job *models.ScheduledJob) error {
+	return q.repo.MarkDone(job)
+}
+
+func (q scheduledJobQueue) Retry(job *models.ScheduledJob, at time.Time, lastError string) error {
+	return q.repo.MarkRetry(job, at, lastError)
+}
+
+func (q scheduledJobQueue) GiveUp(job *models.ScheduledJob, lastError string) error {
+	return q.repo.MarkFailed(job, lastError)
+}
+
+func (q scheduledJobQueue) Info(job *models.ScheduledJob) jobInfo {
+	return jobInfo{ID: job.ID, Key: job.Kind, Attempts: job.Attempts, MaxAttempts: job.MaxAttempts}
+}
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/repositories"
+	"github.com/olabanji12-ojo/CarWashApp/services/tokens"
+	"github.com/olabanji12-ojo/CarWashApp/utils"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TokenPair is what a client receives on login or refresh
+type TokenPair struct {
+	AccessToken      string    `json:"token"`
+	RefreshToken     string    `json:"refresh_token"`
+	ExpiresIn        int       `json:"expires_in"` // access token lifetime in seconds
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+	SessionID        string    `json:"session_id"`
+}
+
+// ClientInfo describes the device a session was opened from
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// SessionService issues access/refresh token pairs and revokes sessions
+type SessionService struct {
+	sessionRepo *repositories.SessionRepository
+	userRepo    *repositories.UserRepository
+	issuer      *tokens.Issuer
+}
+
+// NewSessionService creates a new SessionService instance
+func NewSessionService(sessionRepo *repositories.SessionRepository, userRepo *repositories.UserRepository, issuer *tokens.Issuer) *SessionService {
+	return &SessionService{sessionRepo: sessionRepo, userRepo: userRepo, issuer: issuer}
+}
+
+// IssueSession opens a new session for an authenticated user
+func (ss *SessionService) IssueSession(user *models.User, client ClientInfo) (*TokenPair, error) {
+	refreshToken, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &models.Session{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(refreshToken),
+		UserAgent: client.UserAgent,
+		IPAddress: client.IPAddress,
+	}
+	session.SetDefaults()
+
+	if err := ss.sessionRepo.CreateSession(session); err != nil {
+		logrus.Error("Failed to create session: ", err)
+		return nil, errors.New("failed to start session")
+	}
+
+	return ss.buildTokenPair(user, session, refreshToken)
+}
+
+// IssueImpersonationSession opens a short session as target on behalf of an admin.
+// It cannot be refreshed past models.ImpersonationTTL.
+func (ss *SessionService) IssueImpersonationSession(adminID primitive.ObjectID, target *models.User, client ClientInfo) (*TokenPair, error) {
+	refreshToken, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &models.Session{
+		UserID:         target.ID,
+		TokenHash:      utils.HashToken(refreshToken),
+		UserAgent:      client.UserAgent,
+		IPAddress:      client.IPAddress,
+		ImpersonatorID: &adminID,
+	}
+	session.SetDefaults()
+	session.ExpiresAt = time.Now().Add(models.ImpersonationTTL)
+
+	if err := ss.sessionRepo.CreateSession(session); err != nil {
+		logrus.Error("Failed to create impersonation session: ", err)
+		return nil, errors.New("failed to start session")
+	}
+
+	return ss.buildTokenPair(target, session, refreshToken)
+}
+
+// Refresh rotates a refresh token and returns a new token pair.
+// Presenting a refresh token that was already rotated means it leaked: the whole session is revoked.
+func (ss *SessionService) Refresh(refreshToken string) (*TokenPair, error) {
+	if refreshToken == "" {
+		return nil, errors.New("refresh token is required")
+	}
+	tokenHash := utils.HashToken(refreshToken)
+
+	session, err := ss.sessionRepo.FindSessionByTokenHash(tokenHash)
+	if err != nil {
+		// Reuse detection: an old token from this session's chain
+		if reused, findErr := ss.sessionRepo.FindSessionByPreviousTokenHash(tokenHash); findErr == nil {
+			logrus.Warnf("🚨 [Session] Refresh token reuse detected for session %s (user %s); revoking", reused.ID.Hex(), reused.UserID.Hex())
+			if err := ss.sessionRepo.RevokeSession(reused.ID, models.SessionRevokedReuse); err != nil {
+				logrus.Error("Failed to revoke reused session: ", err)
+			}
+		}
+		return nil, errors.New("invalid refresh token")
+	}
+
+	if !session.IsActive() {
+		return nil, errors.New("session has expired or been revoked")
+	}
+
+	user, err := ss.userRepo.FindUserByID(session.UserID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.Status == "suspended" {
+		return nil, errors.New("account suspended")
+	}
+
+	newToken, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+	if session.ImpersonatorID == nil {
+		session.ExpiresAt = time.Now().Add(models.RefreshTokenTTL)
+	}
+
+	if err := ss.sessionRepo.RotateToken(session.ID, tokenHash, utils.HashToken(newToken), session.ExpiresAt); err != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	return ss.buildTokenPair(user, session, newToken)
+}
+
+// Logout revokes the session a refresh token belongs to
+func (ss *SessionService) Logout(refreshToken string) error {
+	session, err := ss.sessionRepo.FindSessionByTokenHash(utils.HashToken(refreshToken))
+	if err != nil {
+		return err
+	}
+	return ss.sessionRepo.RevokeSession(session.ID, models.SessionRevokedLogout)
+}
+
+// RevokeSessionByID revokes a session by its ID (the "sid" claim of an access token)
+func (ss *SessionService) RevokeSessionByID(sessionID, reason string) error {
+	objID, err := primitive.ObjectIDFromHex(sessionID)
+	if err != nil {
+		return errors.New("invalid session ID")
+	}
+	return ss.sessionRepo.RevokeSession(objID, reason)
+}
+
+// LogoutAll revokes every session of a user ("log out all devices")
+func (ss *SessionService) LogoutAll(userID, reason string) (int64, error) {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return 0, errors.New("invalid user ID")
+	}
+	return ss.sessionRepo.RevokeAllUserSessions(objID, reason)
+}
+
+// ValidateSession is called by AuthMiddleware on every request.
+// It rejects suspended users and sessions that were revoked or have expired.
+func (ss *SessionService) ValidateSession(userID, sessionID string) error {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return errors.New("invalid user ID")
+	}
+	sessionObjID, err := primitive.ObjectIDFromHex(sessionID)
+	if err != nil {
+		return errors.New("invalid session")
+	}
+
+	user, err := ss.userRepo.FindUserByID(userObjID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	if user.Status == "suspended" {
+		return errors.New("account suspended")
+	}
+
+	session, err := ss.sessionRepo.FindSessionByID(sessionObjID)
+	if err != nil || session.UserID != userObjID || !session.IsActive() {
+		return errors.New("session has expired or been revoked")
+	}
+
+	return nil
+}
+
+// SessionIDFromAccessToken returns the session an access token belongs to (the "sid" claim)
+func (ss *SessionService) SessionIDFromAccessToken(accessToken string) (string, error) {
+	_, claims, err := ss.issuer.ValidateToken(accessToken)
+	if err != nil {
+		return "", err
+	}
+	sid, ok := claims["sid"].(string)
+	if !ok || sid == "" {
+		return "", errors.New("token has no session")
+	}
+	return sid, nil
+}
+
+func (ss *SessionService) buildTokenPair(user *models.User, session *models.Session, refreshToken string) (*TokenPair, error) {
+	var accessToken string
+	var err error
+	if session.ImpersonatorID != nil {
+		accessToken, err = ss.issuer.GenerateImpersonationToken(user.ID.Hex(), user.Email, user.Role, user.AccountType, session.ID.Hex(), session.ImpersonatorID.Hex())
+	} else {
+		accessToken, err = ss.issuer.GenerateToken(user.ID.Hex(), user.Email, user.Role, user.AccountType, session.ID.Hex())
+	}
+	if err != nil {
+		logrus.Error("Error generating token: ", err)
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		ExpiresIn:        int(ss.issuer.TTL().Seconds()),
+		RefreshExpiresAt: session.ExpiresAt,
+		SessionID:        session.ID.Hex(),
+	}, nil
+}
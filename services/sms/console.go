@@ -0,0 +1,46 @@
+// services/sms/console.go
+package sms
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Message is an SMS recorded by ConsoleSender
+type Message struct {
+	To   string
+	Body string
+}
+
+// ConsoleSender logs messages instead of sending them and keeps them in memory,
+// for local development and tests
+type ConsoleSender struct {
+	mu   sync.Mutex
+	sent []Message
+}
+
+// NewConsoleSender creates a sender that only logs
+func NewConsoleSender() *ConsoleSender {
+	return &ConsoleSender{}
+}
+
+// Send implements SMSSender
+func (s *ConsoleSender) Send(ctx context.Context, to, message string) (*Receipt, error) {
+	s.mu.Lock()
+	s.sent = append(s.sent, Message{To: to, Body: message})
+	id := fmt.Sprintf("console-%d", len(s.sent))
+	s.mu.Unlock()
+
+	logrus.Infof("📱 [SMS] to %s: %s", to, message)
+	return &Receipt{MessageID: id, Segments: Segments(message)}, nil
+}
+
+// Sent returns a copy of every message sent so far
+func (s *ConsoleSender) Sent() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.sent...)
+}
@@ -0,0 +1,95 @@
+// services/sms/http.go
+package sms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HTTPSender posts messages as JSON to an SMS gateway:
+//
+//	POST <endpoint>  Authorization: Bearer <api key>
+//	{"to": "+2348012345678", "from": "CarWash", "body": "..."}
+//
+// A 2xx answer may report {"message_id": "...", "segments": 1, "cost": 4.0}.
+// Gateways with a different API sit behind a small adapter exposing this one.
+type HTTPSender struct {
+	endpoint string
+	apiKey   string
+	senderID string
+	client   *http.Client
+}
+
+// NewHTTPSender creates a gateway sender; senderID is the "from" name or number
+func NewHTTPSender(endpoint, apiKey, senderID string) *HTTPSender {
+	return &HTTPSender{
+		endpoint: endpoint,
+		apiKey:   apiKey,
+		senderID: senderID,
+		client:   &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+type gatewayRequest struct {
+	To   string `json:"to"`
+	From string `json:"from,omitempty"`
+	Body string `json:"body"`
+}
+
+type gatewayResponse struct {
+	MessageID string  `json:"message_id"`
+	Segments  int     `json:"segments"`
+	Cost      float64 `json:"cost"`
+	Error     string  `json:"error"`
+}
+
+// Send implements SMSSender
+func (s *HTTPSender) Send(ctx context.Context, to, message string) (*Receipt, error) {
+	payload, err := json.Marshal(gatewayRequest{To: to, From: s.senderID, Body: message})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sms gateway request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var result gatewayResponse
+	json.Unmarshal(body, &result)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		reason := strings.TrimSpace(result.Error)
+		if reason == "" {
+			reason = http.StatusText(resp.StatusCode)
+		}
+		// Client errors other than throttling mean the message itself is unacceptable
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return nil, fmt.Errorf("%w: %s", ErrRejected, reason)
+		}
+		return nil, fmt.Errorf("sms gateway returned %d: %s", resp.StatusCode, reason)
+	}
+
+	receipt := &Receipt{MessageID: result.MessageID, Segments: result.Segments, Cost: result.Cost}
+	if receipt.Segments <= 0 {
+		receipt.Segments = Segments(message)
+	}
+	return receipt, nil
+}
@@ -0,0 +1,67 @@
+// services/sms/sender.go
+package sms
+
+import (
+	"context"
+	"errors"
+	"unicode/utf8"
+)
+
+// ErrRejected is returned when the gateway refuses a message outright (e.g. an invalid number);
+// sending it again won't help
+var ErrRejected = errors.New("sms rejected by gateway")
+
+// Receipt describes an accepted message
+type Receipt struct {
+	MessageID string  // gateway reference, if any
+	Segments  int     // billable parts
+	Cost      float64 // as reported by the gateway; 0 when it doesn't report one
+}
+
+// SMSSender defines the interface for delivering text messages.
+// HTTPSender talks to a gateway (Twilio, Termii, ...); ConsoleSender is used locally.
+type SMSSender interface {
+	// Send delivers message to the phone number "to" (E.164 format)
+	Send(ctx context.Context, to, message string) (*Receipt, error)
+}
+
+// gsm7 holds the characters of the GSM 03.38 basic set; extended characters count twice
+const (
+	gsm7Basic    = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+	gsm7Extended = "^{}\\[~]|€\f"
+)
+
+// Segments counts the parts a message is split into: 160 GSM-7 characters (153 when concatenated),
+// or 70 UCS-2 characters (67) as soon as one character is outside the GSM alphabet
+func Segments(message string) int {
+	units, gsm := 0, true
+	for _, r := range message {
+		switch {
+		case containsRune(gsm7Basic, r):
+			units++
+		case containsRune(gsm7Extended, r):
+			units += 2
+		default:
+			gsm = false
+		}
+	}
+
+	single, multi := 160, 153
+	if !gsm {
+		units = utf8.RuneCountInString(message)
+		single, multi = 70, 67
+	}
+	if units <= single {
+		return 1
+	}
+	return (units + multi - 1) / multi
+}
+
+func containsRune(set string, r rune) bool {
+	for _, c := range set {
+		if c == r {
+			return true
+		}
+	}
+	return false
+}
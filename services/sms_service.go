@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/repositories"
+	"github.com/olabanji12-ojo/CarWashApp/services/sms"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrSMSBudgetExceeded is returned when a message would take a carwash over its monthly SMS budget
+var ErrSMSBudgetExceeded = errors.New("monthly SMS budget exceeded")
+
+// SMSRequest is one message to send. CarwashID is set when the carwash pays for it.
+type SMSRequest struct {
+	To        string
+	Body      string
+	Purpose   string
+	UserID    *primitive.ObjectID
+	CarwashID *primitive.ObjectID
+}
+
+// SMSService sends text messages, records what each one cost and keeps carwashes within
+// their monthly SMS budget. Messages without a carwash (e.g. login codes) are only recorded.
+type SMSService struct {
+	sender         sms.SMSSender
+	messageRepo    *repositories.SMSMessageRepository
+	carwashRepo    *repositories.CarWashRepository
+	costPerSegment float64 // used when the gateway doesn't report a cost
+	defaultBudget  float64 // for carwashes that haven't set their own
+}
+
+// NewSMSService creates a new SMSService instance
+func NewSMSService(sender sms.SMSSender, messageRepo *repositories.SMSMessageRepository, carwashRepo *repositories.CarWashRepository, costPerSegment, defaultBudget float64) *SMSService {
+	return &SMSService{
+		sender:         sender,
+		messageRepo:    messageRepo,
+		carwashRepo:    carwashRepo,
+		costPerSegment: costPerSegment,
+		defaultBudget:  defaultBudget,
+	}
+}
+
+// Send delivers a message. A carwash's budget is checked before sending, so messages
+// sent at the same moment can overshoot it by at most their own cost.
+func (ss *SMSService) Send(ctx context.Context, req SMSRequest) error {
+	estimate := ss.cost(sms.Segments(req.Body))
+	if req.CarwashID != nil {
+		usage, err := ss.usage(*req.CarwashID)
+		if err != nil {
+			return err
+		}
+		if usage.Spent+estimate > usage.Budget {
+			logrus.Warnf("📱 SMS budget of carwash %s reached (%.2f of %.2f spent)", req.CarwashID.Hex(), usage.Spent, usage.Budget)
+			return ErrSMSBudgetExceeded
+		}
+	}
+
+	record := &models.SMSMessage{
+		CarwashID: req.CarwashID,
+		UserID:    req.UserID,
+		Purpose:   req.Purpose,
+		To:        req.To,
+	}
+
+	receipt, sendErr := ss.sender.Send(ctx, req.To, req.Body)
+	if sendErr != nil {
+		record.Status = models.SMSStatusFailed
+		record.Error = sendErr.Error()
+	} else {
+		record.Status = models.SMSStatusSent
+		record.Segments = receipt.Segments
+		record.ProviderMessageID = receipt.MessageID
+		record.Cost = receipt.Cost
+		if record.Cost <= 0 {
+			record.Cost = ss.cost(receipt.Segments)
+		}
+	}
+
+	if err := ss.messageRepo.Create(record); err != nil {
+		logrus.Error("Failed to record SMS cost: ", err)
+	}
+	return sendErr
+}
+
+func (ss *SMSService) cost(segments int) float64 {
+	return math.Round(float64(segments)*ss.costPerSegment*100) / 100
+}
+
+// GetUsage returns what a carwash has spent on SMS this month, with its latest messages
+func (ss *SMSService) GetUsage(carwashID string) (*models.SMSUsage, error) {
+	objID, err := primitive.ObjectIDFromHex(carwashID)
+	if err != nil {
+		return nil, errors.New("invalid carwash ID format")
+	}
+
+	usage, err := ss.usage(objID)
+	if err != nil {
+		return nil, err
+	}
+	usage.Recent, err = ss.messageRepo.FindByCarwash(objID, usage.PeriodStart, 50)
+	if err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// SetMonthlyBudget sets how much a carwash may spend on SMS per month; 0 turns SMS off
+func (ss *SMSService) SetMonthlyBudget(carwashID string, budget float64) error {
+	if budget < 0 || math.IsNaN(budget) || math.IsInf(budget, 0) {
+		return errors.New("budget must be zero or more")
+	}
+	return ss.carwashRepo.UpdateCarwash(carwashID, bson.M{"sms_monthly_budget": budget, "updated_at": time.Now()})
+}
+
+func (ss *SMSService) usage(carwashID primitive.ObjectID) (*models.SMSUsage, error) {
+	carwash, err := ss.carwashRepo.GetCarwashByID(carwashID)
+	if err != nil {
+		return nil, errors.New("carwash not found")
+	}
+
+	budget := ss.defaultBudget
+	if carwash.SMSMonthlyBudget != nil {
+		budget = *carwash.SMSMonthlyBudget
+	}
+
+	now := time.Now().UTC()
+	periodStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	spent, count, err := ss.messageRepo.SpentSince(carwashID, periodStart)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SMSUsage{
+		CarwashID:   carwashID,
+		PeriodStart: periodStart,
+		Budget:      budget,
+		Spent:       math.Round(spent*100) / 100,
+		Remaining:   math.Max(0, math.Round((budget-spent)*100)/100),
+		Messages:    count,
+		Recent:      []models.SMSMessage{},
+	}, nil
+}
@@ -0,0 +1,116 @@
+package tokens
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// Issuer signs and verifies access tokens with a KeySet
+type Issuer struct {
+	keys *KeySet
+	ttl  time.Duration
+}
+
+// NewIssuer creates a new Issuer instance
+func NewIssuer(keys *KeySet, ttl time.Duration) *Issuer {
+	return &Issuer{keys: keys, ttl: ttl}
+}
+
+// AccessTokenTTLFromEnv reads ACCESS_TOKEN_TTL_MINUTES, defaulting to 15 minutes
+func AccessTokenTTLFromEnv() time.Duration {
+	if minutes, err := strconv.Atoi(os.Getenv("ACCESS_TOKEN_TTL_MINUTES")); err == nil && minutes > 0 {
+		return time.Duration(minutes) * time.Minute
+	}
+	return 15 * time.Minute
+}
+
+// TTL is the lifetime of access tokens. Clients renew them with a refresh token.
+func (i *Issuer) TTL() time.Duration {
+	return i.ttl
+}
+
+// Keys returns the key set (for publishing the JWKS)
+func (i *Issuer) Keys() *KeySet {
+	return i.keys
+}
+
+// GenerateToken creates a short-lived access JWT bound to a login session (the "sid" claim)
+func (i *Issuer) GenerateToken(userID, email, role, accountType, sessionID string) (string, error) {
+	claims := jwt.MapClaims{
+		"user_id":      userID,
+		"email":        email,
+		"role":         role,
+		"account_type": accountType,
+		"sid":          sessionID,
+		"iat":          time.Now().Unix(),
+		"exp":          time.Now().Add(i.ttl).Unix(),
+	}
+	return i.sign(claims)
+}
+
+// GenerateImpersonationToken is GenerateToken for a session an admin opened as another user.
+// The "imp" claim carries the admin's ID so every request can be attributed to them.
+func (i *Issuer) GenerateImpersonationToken(userID, email, role, accountType, sessionID, impersonatorID string) (string, error) {
+	claims := jwt.MapClaims{
+		"user_id":      userID,
+		"email":        email,
+		"role":         role,
+		"account_type": accountType,
+		"sid":          sessionID,
+		"imp":          impersonatorID,
+		"iat":          time.Now().Unix(),
+		"exp":          time.Now().Add(i.ttl).Unix(),
+	}
+	return i.sign(claims)
+}
+
+func (i *Issuer) sign(claims jwt.MapClaims) (string, error) {
+	key := i.keys.Active()
+	token := jwt.NewWithClaims(signingMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.ID
+
+	signedToken, err := token.SignedString(key.Private)
+	if err != nil {
+		logrus.Error("Error signing token: ", err)
+		return "", err
+	}
+	return signedToken, nil
+}
+
+// ValidateToken parses a JWT, picks the verification key by its kid header and returns token + claims
+func (i *Issuer) ValidateToken(tokenString string) (*jwt.Token, jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := i.keys.Lookup(kid)
+		if !ok {
+			return nil, errors.New("unknown signing key")
+		}
+		// The key decides the algorithm, never the token header
+		if token.Method.Alg() != key.Algorithm {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return key.Public, nil
+	})
+	if err != nil {
+		logrus.Warn("Token parse error: ", err)
+		return nil, nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, nil, errors.New("invalid token claims")
+	}
+	return token, claims, nil
+}
+
+func signingMethod(alg string) jwt.SigningMethod {
+	if alg == AlgEdDSA {
+		return jwt.SigningMethodEdDSA
+	}
+	return jwt.SigningMethodRS256
+}
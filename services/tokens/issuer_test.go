@@ -0,0 +1,159 @@
+package tokens
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func newRSAKey(t *testing.T, kid string) *Key {
+	t.Helper()
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := withKeyID(&Key{Algorithm: AlgRS256, Private: priv, Public: &priv.PublicKey}, kid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func newEd25519Key(t *testing.T) *Key {
+	t.Helper()
+	key, err := GenerateEd25519Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func newIssuer(t *testing.T, active *Key, verification ...*Key) *Issuer {
+	t.Helper()
+	keys, err := NewKeySet(active, verification...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewIssuer(keys, time.Minute)
+}
+
+func TestIssuerRoundTrip(t *testing.T) {
+	cases := []struct {
+		name string
+		key  *Key
+	}{
+		{"RS256", newRSAKey(t, "")},
+		{"EdDSA", newEd25519Key(t)},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			issuer := newIssuer(t, tc.key)
+			signed, err := issuer.GenerateToken("user-1", "a@example.com", "car_owner", "car_owner", "session-1")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			token, claims, err := issuer.ValidateToken(signed)
+			if err != nil {
+				t.Fatalf("ValidateToken: %v", err)
+			}
+			if token.Header["kid"] != tc.key.ID {
+				t.Errorf("kid = %v, want %s", token.Header["kid"], tc.key.ID)
+			}
+			if token.Method.Alg() != tc.key.Algorithm {
+				t.Errorf("alg = %s, want %s", token.Method.Alg(), tc.key.Algorithm)
+			}
+			if claims["user_id"] != "user-1" || claims["sid"] != "session-1" {
+				t.Errorf("unexpected claims %v", claims)
+			}
+		})
+	}
+}
+
+func TestIssuerImpersonationClaim(t *testing.T) {
+	issuer := newIssuer(t, newEd25519Key(t))
+	signed, err := issuer.GenerateImpersonationToken("user-1", "a@example.com", "car_owner", "car_owner", "session-1", "admin-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, claims, err := issuer.ValidateToken(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims["imp"] != "admin-1" {
+		t.Errorf("imp = %v, want admin-1", claims["imp"])
+	}
+}
+
+func TestIssuerKeyRotation(t *testing.T) {
+	oldKey := newEd25519Key(t)
+	newKey := newRSAKey(t, "2024-rotation")
+
+	oldToken, err := newIssuer(t, oldKey).GenerateToken("user-1", "", "car_owner", "car_owner", "s")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// After rotating, the old public key is kept for verification only
+	oldPublic := &Key{ID: oldKey.ID, Algorithm: oldKey.Algorithm, Public: oldKey.Public}
+	rotated := newIssuer(t, newKey, oldPublic)
+	if _, _, err := rotated.ValidateToken(oldToken); err != nil {
+		t.Errorf("token signed with the previous key was rejected: %v", err)
+	}
+
+	newToken, err := rotated.GenerateToken("user-1", "", "car_owner", "car_owner", "s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := rotated.ValidateToken(newToken); err != nil {
+		t.Errorf("token signed with the active key was rejected: %v", err)
+	}
+
+	// Once the old key is dropped its tokens stop working
+	if _, _, err := newIssuer(t, newKey).ValidateToken(oldToken); err == nil {
+		t.Error("token signed with a retired key was accepted")
+	}
+}
+
+func TestIssuerRejects(t *testing.T) {
+	key := newEd25519Key(t)
+	issuer := newIssuer(t, key)
+
+	sign := func(method jwt.SigningMethod, kid string, signingKey interface{}, claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(method, claims)
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		signed, err := token.SignedString(signingKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	valid := jwt.MapClaims{"user_id": "user-1", "exp": time.Now().Add(time.Minute).Unix()}
+	stranger := newEd25519Key(t)
+
+	cases := []struct {
+		name  string
+		token string
+	}{
+		{"expired", sign(jwt.SigningMethodEdDSA, key.ID, key.Private, jwt.MapClaims{"user_id": "user-1", "exp": time.Now().Add(-time.Minute).Unix()})},
+		{"missing kid", sign(jwt.SigningMethodEdDSA, "", key.Private, valid)},
+		{"unknown kid", sign(jwt.SigningMethodEdDSA, stranger.ID, stranger.Private, valid)},
+		{"signed by another key under our kid", sign(jwt.SigningMethodEdDSA, key.ID, stranger.Private, valid)},
+		{"HMAC with the public key as secret", sign(jwt.SigningMethodHS256, key.ID, []byte(key.Public.(ed25519.PublicKey)), valid)},
+		{"alg none", sign(jwt.SigningMethodNone, key.ID, jwt.UnsafeAllowNoneSignatureType, valid)},
+		{"garbage", "not.a.token"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, _, err := issuer.ValidateToken(tc.token); err == nil {
+				t.Error("token was accepted")
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package tokens
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Supported signing algorithms
+const (
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// Key is a JWT key identified by its kid. Private is nil for verification-only keys.
+type Key struct {
+	ID        string
+	Algorithm string
+	Private   crypto.Signer
+	Public    crypto.PublicKey
+}
+
+// KeySet holds the key new tokens are signed with plus every key tokens may still be verified with.
+// To rotate: make the new key active and keep the old public key as a verification key
+// until the last token it signed has expired.
+type KeySet struct {
+	active *Key
+	keys   map[string]*Key
+}
+
+// NewKeySet builds a key set from the active signing key and any older verification keys
+func NewKeySet(active *Key, verification ...*Key) (*KeySet, error) {
+	if active == nil || active.Private == nil {
+		return nil, errors.New("an active signing key with a private key is required")
+	}
+
+	ks := &KeySet{active: active, keys: map[string]*Key{active.ID: active}}
+	for _, key := range verification {
+		if _, exists := ks.keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate key id %q", key.ID)
+		}
+		ks.keys[key.ID] = key
+	}
+	return ks, nil
+}
+
+// Active returns the signing key
+func (ks *KeySet) Active() *Key {
+	return ks.active
+}
+
+// Lookup finds a verification key by kid
+func (ks *KeySet) Lookup(kid string) (*Key, bool) {
+	key, ok := ks.keys[kid]
+	return key, ok
+}
+
+// ParsePrivateKeyPEM reads an RSA (PKCS#1 or PKCS#8) or Ed25519 (PKCS#8) private key.
+// If kid is empty it is derived from the public key.
+func ParsePrivateKeyPEM(kid string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found in private key")
+	}
+
+	var parsed interface{}
+	var err error
+	if block.Type == "RSA PRIVATE KEY" {
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	} else {
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %v", err)
+	}
+
+	var key *Key
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key = &Key{Algorithm: AlgRS256, Private: k, Public: &k.PublicKey}
+	case ed25519.PrivateKey:
+		key = &Key{Algorithm: AlgEdDSA, Private: k, Public: k.Public()}
+	default:
+		return nil, errors.New("unsupported private key type (use RSA or Ed25519)")
+	}
+
+	return withKeyID(key, kid)
+}
+
+// ParsePublicKeyPEM reads an RSA or Ed25519 public key (PKIX) for verification only
+func ParsePublicKeyPEM(kid string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found in public key")
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %v", err)
+	}
+
+	var key *Key
+	switch k := parsed.(type) {
+	case *rsa.PublicKey:
+		key = &Key{Algorithm: AlgRS256, Public: k}
+	case ed25519.PublicKey:
+		key = &Key{Algorithm: AlgEdDSA, Public: k}
+	default:
+		return nil, errors.New("unsupported public key type (use RSA or Ed25519)")
+	}
+
+	return withKeyID(key, kid)
+}
+
+// GenerateEd25519Key creates a throwaway signing key (local development only)
+func GenerateEd25519Key() (*Key, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return withKeyID(&Key{Algorithm: AlgEdDSA, Private: priv, Public: pub}, "")
+}
+
+// LoadKeySetFromEnv loads keys from the environment:
+//
+//	JWT_PRIVATE_KEY_FILE  PEM file with the active signing key (or JWT_PRIVATE_KEY with the PEM inline)
+//	JWT_KEY_ID            kid of the active key (optional, derived from the key if empty)
+//	JWT_PUBLIC_KEY_FILES  comma-separated "kid=path" or "path" entries for older keys still accepted
+//
+// Outside production a missing signing key falls back to an ephemeral Ed25519 key.
+func LoadKeySetFromEnv() (*KeySet, error) {
+	privatePEM, err := readKeyEnv("JWT_PRIVATE_KEY", "JWT_PRIVATE_KEY_FILE")
+	if err != nil {
+		return nil, err
+	}
+
+	var active *Key
+	if privatePEM == nil {
+		if os.Getenv("ENVIRONMENT") == "production" {
+			return nil, errors.New("JWT_PRIVATE_KEY_FILE or JWT_PRIVATE_KEY must be set in production")
+		}
+		logrus.Warn("⚠️ No JWT signing key configured; using an ephemeral Ed25519 key. Tokens won't survive a restart.")
+		if active, err = GenerateEd25519Key(); err != nil {
+			return nil, err
+		}
+	} else if active, err = ParsePrivateKeyPEM(os.Getenv("JWT_KEY_ID"), privatePEM); err != nil {
+		return nil, err
+	}
+
+	var verification []*Key
+	for _, entry := range strings.Split(os.Getenv("JWT_PUBLIC_KEY_FILES"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		kid, path := "", entry
+		if parts := strings.SplitN(entry, "=", 2); len(parts) == 2 {
+			kid, path = parts[0], parts[1]
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read verification key %s: %v", path, err)
+		}
+		key, err := ParsePublicKeyPEM(kid, data)
+		if err != nil {
+			return nil, fmt.Errorf("verification key %s: %v", path, err)
+		}
+		verification = append(verification, key)
+	}
+
+	logrus.Infof("🔑 JWT signing key %s (%s), %d additional verification key(s)", active.ID, active.Algorithm, len(verification))
+	return NewKeySet(active, verification...)
+}
+
+// readKeyEnv returns the inline PEM from inlineVar, else the contents of the file named by fileVar.
+// Returns nil when neither is set.
+func readKeyEnv(inlineVar, fileVar string) ([]byte, error) {
+	if inline := os.Getenv(inlineVar); inline != "" {
+		// Hosting dashboards often store newlines escaped
+		return []byte(strings.ReplaceAll(inline, `\n`, "\n")), nil
+	}
+	if path := os.Getenv(fileVar); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", fileVar, err)
+		}
+		return data, nil
+	}
+	return nil, nil
+}
+
+// withKeyID sets the kid, deriving it from a hash of the public key when not given
+func withKeyID(key *Key, kid string) (*Key, error) {
+	if kid == "" {
+		der, err := x509.MarshalPKIXPublicKey(key.Public)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(der)
+		kid = hex.EncodeToString(sum[:8])
+	}
+	key.ID = kid
+	return key, nil
+}
@@ -0,0 +1,136 @@
+package tokens
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+)
+
+func pemBlock(t *testing.T, blockType string, der []byte, err error) []byte {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+}
+
+func TestParseKeyPEM(t *testing.T) {
+	rsaKey := newRSAKey(t, "")
+	edKey := newEd25519Key(t)
+	rsaPriv := rsaKey.Private.(*rsa.PrivateKey)
+
+	pkcs8RSA, err := x509.MarshalPKCS8PrivateKey(rsaPriv)
+	rsaPKCS8 := pemBlock(t, "PRIVATE KEY", pkcs8RSA, err)
+	rsaPKCS1 := pemBlock(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaPriv), nil)
+	pkcs8Ed, err := x509.MarshalPKCS8PrivateKey(edKey.Private)
+	edPKCS8 := pemBlock(t, "PRIVATE KEY", pkcs8Ed, err)
+	pkixRSA, err := x509.MarshalPKIXPublicKey(rsaKey.Public)
+	rsaPublic := pemBlock(t, "PUBLIC KEY", pkixRSA, err)
+	pkixEd, err := x509.MarshalPKIXPublicKey(edKey.Public)
+	edPublic := pemBlock(t, "PUBLIC KEY", pkixEd, err)
+
+	cases := []struct {
+		name    string
+		parse   func(string, []byte) (*Key, error)
+		data    []byte
+		alg     string
+		wantID  string
+		private bool
+	}{
+		{"RSA PKCS#8", ParsePrivateKeyPEM, rsaPKCS8, AlgRS256, rsaKey.ID, true},
+		{"RSA PKCS#1", ParsePrivateKeyPEM, rsaPKCS1, AlgRS256, rsaKey.ID, true},
+		{"Ed25519 PKCS#8", ParsePrivateKeyPEM, edPKCS8, AlgEdDSA, edKey.ID, true},
+		{"RSA public", ParsePublicKeyPEM, rsaPublic, AlgRS256, rsaKey.ID, false},
+		{"Ed25519 public", ParsePublicKeyPEM, edPublic, AlgEdDSA, edKey.ID, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := tc.parse("", tc.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if key.Algorithm != tc.alg {
+				t.Errorf("algorithm = %s, want %s", key.Algorithm, tc.alg)
+			}
+			// The derived kid depends only on the public key, so both halves agree
+			if key.ID != tc.wantID {
+				t.Errorf("kid = %s, want %s", key.ID, tc.wantID)
+			}
+			if (key.Private != nil) != tc.private {
+				t.Errorf("private key present = %v, want %v", key.Private != nil, tc.private)
+			}
+
+			named, err := tc.parse("custom", tc.data)
+			if err != nil || named.ID != "custom" {
+				t.Errorf("explicit kid not kept: %v, %v", named, err)
+			}
+		})
+	}
+
+	if _, err := ParsePrivateKeyPEM("", []byte("not a pem")); err == nil {
+		t.Error("garbage private key was accepted")
+	}
+	if _, err := ParsePublicKeyPEM("", rsaPKCS8); err == nil {
+		t.Error("private key was accepted as a public key")
+	}
+}
+
+func TestNewKeySet(t *testing.T) {
+	active := newEd25519Key(t)
+	verifyOnly := &Key{ID: "old", Algorithm: AlgEdDSA, Public: newEd25519Key(t).Public}
+
+	if _, err := NewKeySet(nil); err == nil {
+		t.Error("key set without an active key was accepted")
+	}
+	if _, err := NewKeySet(verifyOnly); err == nil {
+		t.Error("verification-only key was accepted as the signing key")
+	}
+	if _, err := NewKeySet(active, &Key{ID: active.ID, Public: active.Public}); err == nil {
+		t.Error("duplicate kid was accepted")
+	}
+
+	keys, err := NewKeySet(active, verifyOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys.Active() != active {
+		t.Error("Active did not return the signing key")
+	}
+	for _, kid := range []string{active.ID, "old"} {
+		if _, ok := keys.Lookup(kid); !ok {
+			t.Errorf("Lookup(%s) found nothing", kid)
+		}
+	}
+	if _, ok := keys.Lookup("missing"); ok {
+		t.Error("Lookup found an unknown kid")
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	rsaKey := newRSAKey(t, "b-rsa")
+	edKey := newEd25519Key(t)
+	edKey.ID = "a-ed"
+
+	keys, err := NewKeySet(rsaKey, edKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := keys.JWKS()
+	if len(doc.Keys) != 2 {
+		t.Fatalf("got %d keys, want 2", len(doc.Keys))
+	}
+
+	ed, rsaJWK := doc.Keys[0], doc.Keys[1]
+	if ed.KeyID != "a-ed" || ed.KeyType != "OKP" || ed.Curve != "Ed25519" || ed.Algorithm != AlgEdDSA || ed.X == "" {
+		t.Errorf("unexpected Ed25519 JWK %+v", ed)
+	}
+	if rsaJWK.KeyID != "b-rsa" || rsaJWK.KeyType != "RSA" || rsaJWK.Algorithm != AlgRS256 || rsaJWK.N == "" || rsaJWK.E != "AQAB" {
+		t.Errorf("unexpected RSA JWK %+v", rsaJWK)
+	}
+	for _, jwk := range doc.Keys {
+		if jwk.Use != "sig" {
+			t.Errorf("use = %s, want sig", jwk.Use)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+// services/tracking/broker.go
+package tracking
+
+import (
+	"context"
+	"time"
+)
+
+// Event types pushed to tracking subscribers
+const (
+	EventLocation = "location"
+	EventStatus   = "status"
+	EventETA      = "eta"
+)
+
+// Point is a latitude/longitude pair
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// Event is a single real-time update for a booking
+type Event struct {
+	Type       string    `json:"type"`
+	BookingID  string    `json:"booking_id"`
+	Status     string    `json:"status,omitempty"`
+	Location   *Point    `json:"location,omitempty"`
+	ETAMinutes *int      `json:"eta_minutes,omitempty"`
+	DistanceKm *float64  `json:"distance_km,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// Broker fans tracking events out to everyone watching a booking.
+// The in-process LocalBroker only reaches subscribers connected to this instance;
+// a shared implementation (e.g. Redis pub/sub) can satisfy the same interface
+// when we run more than one instance.
+type Broker interface {
+	// Publish delivers an event to all current subscribers of event.BookingID
+	Publish(ctx context.Context, event Event) error
+	// Subscribe registers interest in a booking. The returned cancel func must be
+	// called to release the subscription; the channel is closed afterwards.
+	Subscribe(bookingID string) (<-chan Event, func())
+}
@@ -0,0 +1,68 @@
+// services/tracking/local.go
+package tracking
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// subscriberBuffer is how many events a slow subscriber can fall behind before we drop updates
+const subscriberBuffer = 16
+
+// LocalBroker is a per-process Broker backed by Go channels
+type LocalBroker struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[chan Event]struct{}
+}
+
+// NewLocalBroker creates a new in-process broker
+func NewLocalBroker() *LocalBroker {
+	return &LocalBroker{
+		subscribers: make(map[string]map[chan Event]struct{}),
+	}
+}
+
+// Publish sends the event to every subscriber of the booking without blocking.
+// Subscribers whose buffer is full miss the update; the next one supersedes it anyway.
+func (b *LocalBroker) Publish(ctx context.Context, event Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers[event.BookingID] {
+		select {
+		case ch <- event:
+		default:
+			logrus.Warnf("[Tracking] Dropping %s event for slow subscriber on booking %s", event.Type, event.BookingID)
+		}
+	}
+	return nil
+}
+
+// Subscribe registers a new subscriber for a booking
+func (b *LocalBroker) Subscribe(bookingID string) (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	b.mu.Lock()
+	if b.subscribers[bookingID] == nil {
+		b.subscribers[bookingID] = make(map[chan Event]struct{})
+	}
+	b.subscribers[bookingID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers[bookingID], ch)
+			if len(b.subscribers[bookingID]) == 0 {
+				delete(b.subscribers, bookingID)
+			}
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+
+	return ch, cancel
+}
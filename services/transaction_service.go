@@ -7,48 +7,75 @@ import (
 
 	"github.com/olabanji12-ojo/CarWashApp/models"
 	"github.com/olabanji12-ojo/CarWashApp/repositories"
+	"github.com/olabanji12-ojo/CarWashApp/services/events"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-//  CreatePayment
-func CreatePayment(ownerID string, input models.Payment) (*models.Payment, error) {
-	_, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+// PaymentService records payments made for orders
+type PaymentService struct {
+	orderRepo *repositories.OrderRepository
+	bus       events.Bus
+}
+
+func NewPaymentService(orderRepo *repositories.OrderRepository, bus events.Bus) *PaymentService {
+	return &PaymentService{orderRepo: orderRepo, bus: bus}
+}
 
-	UserID, err := primitive.ObjectIDFromHex(ownerID)
+// CreatePayment records the payment of an order. The carwash and amount come from the order,
+// never from the request.
+func (ps *PaymentService) CreatePayment(ownerID, orderID string, input models.Payment) (*models.Payment, error) {
+	userID, err := primitive.ObjectIDFromHex(ownerID)
 	if err != nil {
 		return nil, errors.New("invalid user ID format")
 	}
+	orderObjID, err := primitive.ObjectIDFromHex(orderID)
+	if err != nil {
+		return nil, errors.New("invalid order ID")
+	}
 
+	order, err := ps.orderRepo.GetOrderByID(orderObjID)
+	if err != nil {
+		return nil, errors.New("order not found")
+	}
+	if order.UserID != userID {
+		return nil, errors.New("you can only pay for your own orders")
+	}
+	if existing, err := repositories.GetPaymentByOrderID(order.ID); err == nil && existing.Status == "paid" {
+		return nil, errors.New("this order has already been paid")
+	}
 
+	now := time.Now()
 	newPayment := models.Payment{
-	ID:         primitive.NewObjectID(),
-	OrderID:    input.OrderID,
-	UserID:     UserID,
-	CarwashID:  input.CarwashID,
-	Amount:     input.Amount,
-	Status:     "paid",
-	CreatedAt:  time.Now(),
-	UpdatedAt:  time.Now(),
-}
-
-
-	if input.Status == "" {
-		input.Status = "paid"
+		ID:             primitive.NewObjectID(),
+		OrderID:        order.ID,
+		UserID:         userID,
+		CarwashID:      order.CarwashID,
+		Amount:         order.TotalAmount,
+		Method:         input.Method,
+		Status:         "paid",
+		TransactionRef: input.TransactionRef,
+		PaidAt:         now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := newPayment.Validate(); err != nil {
+		return nil, err
 	}
 
-	err = repositories.CreatePayment(&newPayment)
-	
-	if err != nil {
+	if err := repositories.CreatePayment(&newPayment); err != nil {
 		return nil, err
 	}
 
+	if newPayment.Status == "paid" {
+		ps.bus.Publish(events.PaymentSucceeded{Payment: &newPayment, OccurredAt: time.Now()})
+	}
+
 	return &newPayment, nil
 }
 
 
 //  GetPaymentByOrderID
-func GetPaymentByOrderID(orderID string) (*models.Payment, error) {
+func (ps *PaymentService) GetPaymentByOrderID(orderID string) (*models.Payment, error) {
 	_, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -62,7 +89,7 @@ func GetPaymentByOrderID(orderID string) (*models.Payment, error) {
 
 
 //  GetPaymentsByUserID
-func GetPaymentsByUserID(userID string) ([]models.Payment, error) {
+func (ps *PaymentService) GetPaymentsByUserID(userID string) ([]models.Payment, error) {
 	_, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -76,7 +103,7 @@ func GetPaymentsByUserID(userID string) ([]models.Payment, error) {
 
 
 //  GetPaymentsByCarwashID
-func GetPaymentsByCarwashID(carwashID string) ([]models.Payment, error) {
+func (ps *PaymentService) GetPaymentsByCarwashID(carwashID string) ([]models.Payment, error) {
 	_, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -91,7 +118,7 @@ func GetPaymentsByCarwashID(carwashID string) ([]models.Payment, error) {
 
 
 //  CalculateEarningsByCarwash
-func CalculateEarningsByCarwash(carwashID string) (float64, error) {
+func (ps *PaymentService) CalculateEarningsByCarwash(carwashID string) (float64, error) {
 	_, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -0,0 +1,352 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/repositories"
+	"github.com/olabanji12-ojo/CarWashApp/utils"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TwoFactorSetup is shown once while enrolling so the user can add the account to an authenticator app
+type TwoFactorSetup struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"` // render as a QR code
+}
+
+// TwoFactorChallenge is returned instead of a session when a second factor is still needed
+type TwoFactorChallenge struct {
+	PreAuthToken string `json:"pre_auth_token"`
+	Purpose      string `json:"purpose"` // verify: enter a code; enroll: set up 2FA first
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+var errInvalidSecondFactor = errors.New("invalid authentication code")
+
+// TwoFactorService handles TOTP enrolment, recovery codes and the second login step.
+// Wrong codes are throttled per account, whichever flow they come from.
+type TwoFactorService struct {
+	userRepo    *repositories.UserRepository
+	carwashRepo *repositories.CarWashRepository
+	preAuthRepo *repositories.PreAuthTokenRepository
+	throttle    *AuthThrottleService
+}
+
+// NewTwoFactorService creates a new TwoFactorService instance
+func NewTwoFactorService(userRepo *repositories.UserRepository, carwashRepo *repositories.CarWashRepository, preAuthRepo *repositories.PreAuthTokenRepository, throttle *AuthThrottleService) *TwoFactorService {
+	return &TwoFactorService{userRepo: userRepo, carwashRepo: carwashRepo, preAuthRepo: preAuthRepo, throttle: throttle}
+}
+
+// Challenge decides whether a user who passed the first factor needs a second one.
+// It returns nil when a session can be issued straight away.
+func (tfs *TwoFactorService) Challenge(user *models.User) (*TwoFactorChallenge, error) {
+	purpose := ""
+	if user.TwoFactorEnabled {
+		purpose = models.PreAuthVerify
+	} else if tfs.requiredByEmployer(user) {
+		purpose = models.PreAuthEnroll
+	}
+	if purpose == "" {
+		return nil, nil
+	}
+
+	rawToken, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	token := &models.PreAuthToken{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(rawToken),
+		Purpose:   purpose,
+	}
+	token.SetDefaults()
+	if err := tfs.preAuthRepo.CreateToken(token); err != nil {
+		logrus.Error("Failed to create pre-auth token: ", err)
+		return nil, errors.New("failed to start two-factor login")
+	}
+
+	return &TwoFactorChallenge{
+		PreAuthToken: rawToken,
+		Purpose:      purpose,
+		ExpiresIn:    int(models.PreAuthTokenTTL.Seconds()),
+	}, nil
+}
+
+// VerifyChallenge exchanges a pre-auth token plus a TOTP or recovery code for the user to log in
+func (tfs *TwoFactorService) VerifyChallenge(rawToken, code string) (*models.User, error) {
+	token, err := tfs.preAuthRepo.ConsumeAttempt(utils.HashToken(rawToken))
+	if err != nil {
+		return nil, err
+	}
+	if token.Purpose != models.PreAuthVerify {
+		return nil, errors.New("two-factor authentication must be set up first")
+	}
+
+	user, err := tfs.userRepo.FindUserByID(token.UserID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if err := tfs.checkSecondFactor(user, code); err != nil {
+		return nil, err
+	}
+	// Only now is the login complete
+	tfs.throttle.RecordLoginSuccess(user.Email)
+
+	if err := tfs.preAuthRepo.DeleteToken(token.ID); err != nil {
+		logrus.Warn("Failed to delete used pre-auth token: ", err)
+	}
+	return user, nil
+}
+
+// BeginChallengeEnrollment starts enrolment for a user who must set up 2FA before logging in
+func (tfs *TwoFactorService) BeginChallengeEnrollment(rawToken string) (*TwoFactorSetup, error) {
+	token, err := tfs.preAuthRepo.FindActiveToken(utils.HashToken(rawToken))
+	if err != nil {
+		return nil, err
+	}
+	if token.Purpose != models.PreAuthEnroll {
+		return nil, errors.New("two-factor authentication is already set up")
+	}
+	return tfs.BeginEnrollment(token.UserID.Hex())
+}
+
+// ConfirmChallengeEnrollment finishes enrolment from the login flow and returns the user and
+// their recovery codes
+func (tfs *TwoFactorService) ConfirmChallengeEnrollment(rawToken, code string) (*models.User, []string, error) {
+	token, err := tfs.preAuthRepo.ConsumeAttempt(utils.HashToken(rawToken))
+	if err != nil {
+		return nil, nil, err
+	}
+	if token.Purpose != models.PreAuthEnroll {
+		return nil, nil, errors.New("two-factor authentication is already set up")
+	}
+
+	recoveryCodes, err := tfs.ConfirmEnrollment(token.UserID.Hex(), code)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := tfs.userRepo.FindUserByID(token.UserID)
+	if err != nil {
+		return nil, nil, errors.New("user not found")
+	}
+
+	if err := tfs.preAuthRepo.DeleteToken(token.ID); err != nil {
+		logrus.Warn("Failed to delete used pre-auth token: ", err)
+	}
+	return user, recoveryCodes, nil
+}
+
+// BeginEnrollment generates a new secret. It only takes effect once confirmed with a code.
+func (tfs *TwoFactorService) BeginEnrollment(userID string) (*TwoFactorSetup, error) {
+	user, err := tfs.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := tfs.userRepo.UpdateUserByID(user.ID, bson.M{
+		"two_factor_pending_secret": secret,
+		"updated_at":                time.Now(),
+	}); err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorSetup{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(models.TwoFactorIssuerLabel, user.Email, secret),
+	}, nil
+}
+
+// ConfirmEnrollment turns 2FA on once the user proves their app produces valid codes.
+// The returned recovery codes are shown once; only their hashes are kept.
+func (tfs *TwoFactorService) ConfirmEnrollment(userID, code string) ([]string, error) {
+	user, err := tfs.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+	if user.TwoFactorPendingSecret == "" {
+		return nil, errors.New("start two-factor setup first")
+	}
+
+	step, ok := utils.VerifyTOTP(user.TwoFactorPendingSecret, code, 0)
+	if !ok {
+		return nil, errInvalidSecondFactor
+	}
+
+	recoveryCodes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tfs.userRepo.UpdateUserByID(user.ID, bson.M{
+		"two_factor_enabled":        true,
+		"two_factor_secret":         user.TwoFactorPendingSecret,
+		"two_factor_pending_secret": "",
+		"two_factor_last_step":      step,
+		"recovery_code_hashes":      hashes,
+		"updated_at":                time.Now(),
+	}); err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("🔐 Two-factor authentication enabled for user %s", user.ID.Hex())
+	return recoveryCodes, nil
+}
+
+// Disable turns 2FA off after checking a current code. Workers whose carwash requires 2FA can't.
+func (tfs *TwoFactorService) Disable(userID, code string) error {
+	user, err := tfs.findUser(userID)
+	if err != nil {
+		return err
+	}
+	if !user.TwoFactorEnabled {
+		return errors.New("two-factor authentication is not enabled")
+	}
+	if tfs.requiredByEmployer(user) {
+		return errors.New("your carwash requires two-factor authentication")
+	}
+	if err := tfs.checkSecondFactor(user, code); err != nil {
+		return err
+	}
+
+	logrus.Infof("🔓 Two-factor authentication disabled for user %s", user.ID.Hex())
+	return tfs.userRepo.ClearTwoFactor(user.ID)
+}
+
+// RegenerateRecoveryCodes replaces every recovery code after checking a current code
+func (tfs *TwoFactorService) RegenerateRecoveryCodes(userID, code string) ([]string, error) {
+	user, err := tfs.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TwoFactorEnabled {
+		return nil, errors.New("two-factor authentication is not enabled")
+	}
+	if err := tfs.checkSecondFactor(user, code); err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := tfs.userRepo.UpdateUserByID(user.ID, bson.M{
+		"recovery_code_hashes": hashes,
+		"updated_at":           time.Now(),
+	}); err != nil {
+		return nil, err
+	}
+	return recoveryCodes, nil
+}
+
+// checkSecondFactor accepts a current TOTP code or an unused recovery code, unless the account
+// is backing off from too many wrong ones
+func (tfs *TwoFactorService) checkSecondFactor(user *models.User, code string) error {
+	if err := tfs.throttle.CheckSecondFactor(user.ID); err != nil {
+		return err
+	}
+
+	err := tfs.verifySecondFactor(user, code)
+	switch {
+	case errors.Is(err, errInvalidSecondFactor):
+		tfs.throttle.RecordSecondFactorFailure(user.ID)
+	case err == nil:
+		tfs.throttle.RecordSecondFactorSuccess(user.ID)
+	}
+	return err
+}
+
+func (tfs *TwoFactorService) verifySecondFactor(user *models.User, code string) error {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return errInvalidSecondFactor
+	}
+
+	if len(code) == utils.TOTPDigits {
+		step, ok := utils.VerifyTOTP(user.TwoFactorSecret, code, user.TwoFactorLastStep)
+		if !ok {
+			return errInvalidSecondFactor
+		}
+		// Another request may have used the same code in the meantime
+		advanced, err := tfs.userRepo.AdvanceTwoFactorStep(user.ID, step)
+		if err != nil {
+			return err
+		}
+		if !advanced {
+			return errInvalidSecondFactor
+		}
+		return nil
+	}
+
+	used, err := tfs.userRepo.ConsumeRecoveryCode(user.ID, hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return errInvalidSecondFactor
+	}
+	logrus.Infof("Recovery code used by user %s", user.ID.Hex())
+	return nil
+}
+
+// requiredByEmployer reports whether a worker's carwash makes 2FA mandatory
+func (tfs *TwoFactorService) requiredByEmployer(user *models.User) bool {
+	if user.Role != utils.ROLE_WORKER || user.CarWashID == nil {
+		return false
+	}
+	carwash, err := tfs.carwashRepo.GetCarwashByID(*user.CarWashID)
+	if err != nil {
+		logrus.Warn("Failed to load worker's carwash for 2FA policy: ", err)
+		return false
+	}
+	return carwash.RequireWorker2FA
+}
+
+func (tfs *TwoFactorService) findUser(userID string) (*models.User, error) {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+	user, err := tfs.userRepo.FindUserByID(objID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	return user, nil
+}
+
+// generateRecoveryCodes returns codes formatted like "a1b2-c3d4" and their hashes
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, models.RecoveryCodeCount)
+	hashes := make([]string, 0, models.RecoveryCodeCount)
+	for i := 0; i < models.RecoveryCodeCount; i++ {
+		raw, err := utils.GenerateSecureToken(4)
+		if err != nil {
+			return nil, nil, err
+		}
+		codes = append(codes, raw[:4]+"-"+raw[4:])
+		hashes = append(hashes, hashRecoveryCode(raw))
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode ignores case and separators so "A1B2 C3D4" matches "a1b2-c3d4"
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	return utils.HashToken(normalized)
+}
@@ -10,6 +10,8 @@ import (
 
 	"github.com/olabanji12-ojo/CarWashApp/models"
 	"github.com/olabanji12-ojo/CarWashApp/repositories"
+	"github.com/olabanji12-ojo/CarWashApp/templates"
+	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -33,6 +35,33 @@ func NewUserService(userRepo *repositories.UserRepository) *UserService {
 	}
 }
 
+// NormalizeStoredPhones rewrites phone numbers saved before they were normalized, so phone login
+// finds those users too. Numbers that can't be normalized, or whose normalized form already
+// belongs to another account, are left as they are. Safe to run on every start.
+func (s *UserService) NormalizeStoredPhones() (int, error) {
+	users, err := s.userRepo.FindUsersWithUnnormalizedPhone()
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, user := range users {
+		phone := normalizedPhone(user.Phone)
+		if phone == user.Phone {
+			continue
+		}
+		if err := s.userRepo.UpdateUserByID(user.ID, bson.M{"phone": phone}); err != nil {
+			logrus.Warnf("Could not normalize phone of user %s: %v", user.ID.Hex(), err)
+			continue
+		}
+		updated++
+	}
+	if updated > 0 {
+		logrus.Infof("📱 Normalized %d stored phone number(s)", updated)
+	}
+	return updated, nil
+}
+
 // GetUserByID retrieves a user's profile using their ID
 func (s *UserService) GetUserByID(userID string) (*models.User, error) {
 	// 1. Convert string ID to ObjectID
@@ -80,11 +109,18 @@ func (s *UserService) UpdateUserWithPhoto(userID string, input *models.User, pho
 		update["name"] = input.Name
 	}
 	if input.Phone != "" {
-		update["phone"] = input.Phone
+		update["phone"] = normalizedPhone(input.Phone)
 	}
 	if input.Email != "" {
 		update["email"] = input.Email
 	}
+	if input.PreferredLanguage != "" {
+		locale, err := templates.ValidateLocale(input.PreferredLanguage)
+		if err != nil {
+			return nil, err
+		}
+		update["preferred_language"] = locale
+	}
 
 	// 4. Handle profile photo upload if provided
 	if photoFile != nil {
@@ -0,0 +1,604 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/repositories"
+	"github.com/olabanji12-ojo/CarWashApp/services/events"
+	"github.com/olabanji12-ojo/CarWashApp/utils"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Headers sent with every webhook delivery
+const (
+	WebhookHeaderEvent     = "X-Webhook-Event"
+	WebhookHeaderEventID   = "X-Webhook-Event-Id"
+	WebhookHeaderDelivery  = "X-Webhook-Delivery"
+	WebhookHeaderSignature = "X-Webhook-Signature"
+)
+
+const (
+	webhookTimeout          = 10 * time.Second
+	webhookResponseLimit    = 512 // bytes of the receiver's response kept in the delivery log
+	maxWebhookEndpoints     = 10  // per carwash
+	defaultWebhookLogLimit  = 50
+	maxWebhookLogLimit      = 100
+	webhookSecretByteLength = 24
+)
+
+// WebhookService lets carwashes receive their events on their own systems.
+// Each event is saved as a delivery per subscribed endpoint and sent by the scheduler,
+// which retries failed deliveries with backoff.
+type WebhookService struct {
+	webhookRepo  *repositories.WebhookRepository
+	jobRepo      *repositories.ScheduledJobRepository
+	client       *http.Client
+	allowPrivate bool
+}
+
+// NewWebhookService creates a new WebhookService instance. Unless allowPrivate is set (for local
+// development), endpoints must use https and may not resolve to loopback or private addresses.
+func NewWebhookService(webhookRepo *repositories.WebhookRepository, jobRepo *repositories.ScheduledJobRepository, allowPrivate bool) *WebhookService {
+	return &WebhookService{
+		webhookRepo:  webhookRepo,
+		jobRepo:      jobRepo,
+		client:       newWebhookClient(allowPrivate),
+		allowPrivate: allowPrivate,
+	}
+}
+
+// WebhookEndpointInput creates or changes an endpoint; nil fields are left as they are
+type WebhookEndpointInput struct {
+	URL         *string  `json:"url"`
+	Description *string  `json:"description"`
+	Events      []string `json:"events"`
+	Active      *bool    `json:"active"`
+}
+
+// webhookEnvelope is the JSON body of every delivery
+type webhookEnvelope struct {
+	ID        string             `json:"id"`
+	Type      string             `json:"type"`
+	CreatedAt time.Time          `json:"created_at"`
+	CarwashID primitive.ObjectID `json:"carwash_id"`
+	Data      interface{}        `json:"data"`
+}
+
+// webhookBooking is the booking sent to webhooks; it leaves out the customer's handshake code
+type webhookBooking struct {
+	ID             primitive.ObjectID  `json:"id"`
+	UserID         primitive.ObjectID  `json:"user_id"`
+	CarID          primitive.ObjectID  `json:"car_id"`
+	BookingTime    time.Time           `json:"booking_time"`
+	BookingType    string              `json:"booking_type"`
+	Status         string              `json:"status"`
+	PreviousStatus string              `json:"previous_status,omitempty"`
+	QueueNumber    int                 `json:"queue_number"`
+	Notes          string              `json:"notes,omitempty"`
+	AddressNote    string              `json:"address_note,omitempty"`
+	UserLocation   *models.GeoLocation `json:"user_location,omitempty"`
+	CreatedAt      time.Time           `json:"created_at"`
+}
+
+func newWebhookBooking(booking *models.Booking, previousStatus string) webhookBooking {
+	return webhookBooking{
+		ID:             booking.ID,
+		UserID:         booking.UserID,
+		CarID:          booking.CarID,
+		BookingTime:    booking.BookingTime,
+		BookingType:    booking.BookingType,
+		Status:         booking.Status,
+		PreviousStatus: previousStatus,
+		QueueNumber:    booking.QueueNumber,
+		Notes:          booking.Notes,
+		AddressNote:    booking.AddressNote,
+		UserLocation:   booking.UserLocation,
+		CreatedAt:      booking.CreatedAt,
+	}
+}
+
+// Subscribe sends the domain events carwashes can subscribe to on to their webhooks
+func (ws *WebhookService) Subscribe(bus events.Bus) {
+	events.On(bus, func(e events.BookingCreated) {
+		ws.Dispatch(e.Booking.CarwashID, models.WebhookEventBookingCreated, newWebhookBooking(e.Booking, ""))
+	})
+	events.On(bus, func(e events.BookingStatusChanged) {
+		ws.Dispatch(e.Booking.CarwashID, models.WebhookEventBookingStatusChanged, newWebhookBooking(e.Booking, e.PreviousStatus))
+	})
+	events.On(bus, func(e events.OrderCompleted) {
+		ws.Dispatch(e.Order.CarwashID, models.WebhookEventOrderCompleted, e.Order)
+	})
+	events.On(bus, func(e events.PaymentSucceeded) {
+		ws.Dispatch(e.Payment.CarwashID, models.WebhookEventPaymentSucceeded, e.Payment)
+	})
+	events.On(bus, func(e events.ReviewCreated) {
+		ws.Dispatch(e.Review.CarwashID, models.WebhookEventReviewCreated, e.Review)
+	})
+}
+
+// Dispatch queues an event for every active endpoint of the carwash that subscribed to it.
+// Failures are logged; the action that raised the event has already happened.
+func (ws *WebhookService) Dispatch(carwashID primitive.ObjectID, event string, data interface{}) {
+	if carwashID.IsZero() {
+		return
+	}
+	endpoints, err := ws.webhookRepo.FindSubscribed(carwashID, event)
+	if err != nil {
+		logrus.Errorf("Failed to look up webhooks of carwash %s: %v", carwashID.Hex(), err)
+		return
+	}
+	if len(endpoints) == 0 {
+		return
+	}
+
+	envelope := webhookEnvelope{
+		ID:        "evt_" + primitive.NewObjectID().Hex(),
+		Type:      event,
+		CreatedAt: time.Now().UTC(),
+		CarwashID: carwashID,
+		Data:      data,
+	}
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		logrus.Errorf("Failed to encode %s webhook: %v", event, err)
+		return
+	}
+
+	for _, endpoint := range endpoints {
+		delivery := &models.WebhookDelivery{
+			EndpointID: endpoint.ID,
+			CarwashID:  carwashID,
+			EventID:    envelope.ID,
+			Event:      event,
+			Payload:    string(payload),
+		}
+		if err := ws.enqueue(delivery); err != nil {
+			logrus.Errorf("Failed to queue %s webhook for endpoint %s: %v", event, endpoint.ID.Hex(), err)
+		}
+	}
+}
+
+// enqueue saves a delivery and schedules it to be sent right away
+func (ws *WebhookService) enqueue(delivery *models.WebhookDelivery) error {
+	now := time.Now()
+	delivery.ID = primitive.NewObjectID()
+	delivery.Status = models.WebhookDeliveryPending
+	delivery.CreatedAt = now
+	delivery.UpdatedAt = now
+	if err := ws.webhookRepo.CreateDelivery(delivery); err != nil {
+		return err
+	}
+
+	return ws.jobRepo.Schedule(&models.ScheduledJob{
+		Kind:        models.JobKindWebhookDelivery,
+		Key:         fmt.Sprintf("%s:%s", models.JobKindWebhookDelivery, delivery.ID.Hex()),
+		SubjectID:   delivery.ID,
+		RunAt:       now,
+		MaxAttempts: models.WebhookDeliveryMaxAttempts,
+	})
+}
+
+// HandleDelivery is the scheduler handler that sends a delivery. A failed attempt returns
+// its error, so the scheduler retries it until the job runs out of attempts.
+func (ws *WebhookService) HandleDelivery(ctx context.Context, job *models.ScheduledJob) error {
+	delivery, err := ws.webhookRepo.FindDeliveryByID(job.SubjectID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return Permanent(errors.New("webhook delivery not found"))
+	}
+	if err != nil {
+		return err
+	}
+	if delivery.Status == models.WebhookDeliverySucceeded {
+		return nil
+	}
+
+	endpoint, err := ws.webhookRepo.FindEndpointByID(delivery.EndpointID)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return err
+	}
+	if endpoint == nil || !endpoint.Active {
+		// Nothing left to send to; close the delivery without counting an attempt
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.LastError = "endpoint was deleted or disabled"
+		return ws.webhookRepo.RecordAttempt(delivery)
+	}
+
+	sendErr := ws.send(ctx, endpoint, delivery)
+	delivery.Attempts++
+	var permanent permanentError
+	switch {
+	case sendErr == nil:
+		now := time.Now()
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+	case errors.As(sendErr, &permanent) || job.Attempts >= job.MaxAttempts:
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.LastError = sendErr.Error()
+	default:
+		delivery.LastError = sendErr.Error()
+	}
+	if err := ws.webhookRepo.RecordAttempt(delivery); err != nil {
+		logrus.Errorf("Failed to log webhook delivery %s: %v", delivery.ID.Hex(), err)
+	}
+	return sendErr
+}
+
+// send posts the signed payload and records the receiver's response on the delivery.
+// Any 2xx response counts as delivered.
+func (ws *WebhookService) send(ctx context.Context, endpoint *models.WebhookEndpoint, delivery *models.WebhookDelivery) error {
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, strings.NewReader(delivery.Payload))
+	if err != nil {
+		return Permanent(err)
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "CarWashApp-Webhooks/1.0")
+	req.Header.Set(WebhookHeaderEvent, delivery.Event)
+	req.Header.Set(WebhookHeaderEventID, delivery.EventID)
+	req.Header.Set(WebhookHeaderDelivery, delivery.ID.Hex())
+	req.Header.Set(WebhookHeaderSignature, SignWebhookPayload(endpoint.Secret, timestamp, []byte(delivery.Payload)))
+
+	started := time.Now()
+	resp, err := ws.client.Do(req)
+	delivery.DurationMs = time.Since(started).Milliseconds()
+	if err != nil {
+		delivery.ResponseStatus = 0
+		delivery.ResponseBody = ""
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseLimit))
+	delivery.ResponseStatus = resp.StatusCode
+	delivery.ResponseBody = string(body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("endpoint responded %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SignWebhookPayload builds the X-Webhook-Signature header: "t=<unix time>,v1=<hex HMAC-SHA256>",
+// where the HMAC is taken with the endpoint's secret over "<unix time>.<body>".
+// Receivers recompute it and should reject old timestamps to stop replays.
+func SignWebhookPayload(secret string, timestamp int64, payload []byte) string {
+	ts := strconv.FormatInt(timestamp, 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// CreateEndpoint registers an endpoint for a carwash. The returned secret is not shown again.
+func (ws *WebhookService) CreateEndpoint(carwashID string, input WebhookEndpointInput) (*models.WebhookEndpoint, string, error) {
+	carwashObjID, err := primitive.ObjectIDFromHex(carwashID)
+	if err != nil {
+		return nil, "", errors.New("invalid carwash ID")
+	}
+	if input.URL == nil {
+		return nil, "", errors.New("url is required")
+	}
+
+	existing, err := ws.webhookRepo.FindEndpointsByCarwash(carwashObjID)
+	if err != nil {
+		return nil, "", errors.New("failed to create webhook")
+	}
+	if len(existing) >= maxWebhookEndpoints {
+		return nil, "", fmt.Errorf("a carwash can have at most %d webhooks", maxWebhookEndpoints)
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, "", err
+	}
+	now := time.Now()
+	endpoint := models.WebhookEndpoint{
+		ID:        primitive.NewObjectID(),
+		CarwashID: carwashObjID,
+		URL:       strings.TrimSpace(*input.URL),
+		Secret:    secret,
+		Events:    dedupeEvents(input.Events),
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if input.Description != nil {
+		endpoint.Description = strings.TrimSpace(*input.Description)
+	}
+	if input.Active != nil {
+		endpoint.Active = *input.Active
+	}
+	if err := endpoint.Validate(); err != nil {
+		return nil, "", err
+	}
+	if err := ws.validateURL(endpoint.URL); err != nil {
+		return nil, "", err
+	}
+
+	if err := ws.webhookRepo.CreateEndpoint(&endpoint); err != nil {
+		logrus.Error("Failed to create webhook: ", err)
+		return nil, "", errors.New("failed to create webhook")
+	}
+	return &endpoint, secret, nil
+}
+
+// ListEndpoints returns a carwash's endpoints
+func (ws *WebhookService) ListEndpoints(carwashID string) ([]models.WebhookEndpoint, error) {
+	carwashObjID, err := primitive.ObjectIDFromHex(carwashID)
+	if err != nil {
+		return nil, errors.New("invalid carwash ID")
+	}
+	return ws.webhookRepo.FindEndpointsByCarwash(carwashObjID)
+}
+
+// GetEndpoint returns one of a carwash's endpoints
+func (ws *WebhookService) GetEndpoint(carwashID, endpointID string) (*models.WebhookEndpoint, error) {
+	carwashObjID, endpointObjID, err := parseWebhookIDs(carwashID, endpointID)
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := ws.webhookRepo.FindEndpoint(carwashObjID, endpointObjID)
+	if err != nil {
+		return nil, errors.New("webhook not found")
+	}
+	return endpoint, nil
+}
+
+// UpdateEndpoint changes an endpoint's URL, description, events or whether it is active
+func (ws *WebhookService) UpdateEndpoint(carwashID, endpointID string, input WebhookEndpointInput) (*models.WebhookEndpoint, error) {
+	endpoint, err := ws.GetEndpoint(carwashID, endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := bson.M{}
+	if input.URL != nil {
+		endpoint.URL = strings.TrimSpace(*input.URL)
+		updates["url"] = endpoint.URL
+	}
+	if input.Description != nil {
+		endpoint.Description = strings.TrimSpace(*input.Description)
+		updates["description"] = endpoint.Description
+	}
+	if input.Events != nil {
+		endpoint.Events = dedupeEvents(input.Events)
+		updates["events"] = endpoint.Events
+	}
+	if input.Active != nil {
+		endpoint.Active = *input.Active
+		updates["active"] = endpoint.Active
+	}
+	if len(updates) == 0 {
+		return endpoint, nil
+	}
+	if err := endpoint.Validate(); err != nil {
+		return nil, err
+	}
+	if input.URL != nil {
+		if err := ws.validateURL(endpoint.URL); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := ws.webhookRepo.UpdateEndpoint(endpoint.CarwashID, endpoint.ID, updates)
+	if err != nil {
+		return nil, errors.New("failed to update webhook")
+	}
+	return updated, nil
+}
+
+// RotateSecret gives an endpoint a new signing secret and returns it; the old one stops working at once
+func (ws *WebhookService) RotateSecret(carwashID, endpointID string) (string, error) {
+	endpoint, err := ws.GetEndpoint(carwashID, endpointID)
+	if err != nil {
+		return "", err
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return "", err
+	}
+	if _, err := ws.webhookRepo.UpdateEndpoint(endpoint.CarwashID, endpoint.ID, bson.M{"secret": secret}); err != nil {
+		return "", errors.New("failed to rotate webhook secret")
+	}
+	return secret, nil
+}
+
+// DeleteEndpoint removes an endpoint. Deliveries still queued for it are dropped when they come up.
+func (ws *WebhookService) DeleteEndpoint(carwashID, endpointID string) error {
+	carwashObjID, endpointObjID, err := parseWebhookIDs(carwashID, endpointID)
+	if err != nil {
+		return err
+	}
+	deleted, err := ws.webhookRepo.DeleteEndpoint(carwashObjID, endpointObjID)
+	if err != nil {
+		return errors.New("failed to delete webhook")
+	}
+	if !deleted {
+		return errors.New("webhook not found")
+	}
+	return nil
+}
+
+// ListDeliveries returns an endpoint's delivery log, newest first, without payloads.
+// status optionally narrows it to pending, succeeded or failed deliveries.
+func (ws *WebhookService) ListDeliveries(carwashID, endpointID, status string, limit int) ([]models.WebhookDelivery, error) {
+	endpoint, err := ws.GetEndpoint(carwashID, endpointID)
+	if err != nil {
+		return nil, err
+	}
+	switch status {
+	case "", models.WebhookDeliveryPending, models.WebhookDeliverySucceeded, models.WebhookDeliveryFailed:
+	default:
+		return nil, errors.New("invalid delivery status")
+	}
+	if limit <= 0 {
+		limit = defaultWebhookLogLimit
+	}
+	if limit > maxWebhookLogLimit {
+		limit = maxWebhookLogLimit
+	}
+	return ws.webhookRepo.FindDeliveries(endpoint.CarwashID, endpoint.ID, status, int64(limit))
+}
+
+// GetDelivery returns one delivery of an endpoint, including the payload that was sent
+func (ws *WebhookService) GetDelivery(carwashID, endpointID, deliveryID string) (*models.WebhookDelivery, error) {
+	carwashObjID, endpointObjID, err := parseWebhookIDs(carwashID, endpointID)
+	if err != nil {
+		return nil, err
+	}
+	deliveryObjID, err := primitive.ObjectIDFromHex(deliveryID)
+	if err != nil {
+		return nil, errors.New("invalid delivery ID")
+	}
+	delivery, err := ws.webhookRepo.FindDelivery(carwashObjID, endpointObjID, deliveryObjID)
+	if err != nil {
+		return nil, errors.New("delivery not found")
+	}
+	return delivery, nil
+}
+
+// Redeliver sends a past delivery's payload to its endpoint again as a new delivery.
+// The event ID stays the same so receivers can recognise the duplicate.
+func (ws *WebhookService) Redeliver(carwashID, endpointID, deliveryID string) (*models.WebhookDelivery, error) {
+	original, err := ws.GetDelivery(carwashID, endpointID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := ws.GetEndpoint(carwashID, endpointID)
+	if err != nil {
+		return nil, err
+	}
+	if !endpoint.Active {
+		return nil, errors.New("webhook is disabled")
+	}
+
+	redelivery := &models.WebhookDelivery{
+		EndpointID:   original.EndpointID,
+		CarwashID:    original.CarwashID,
+		EventID:      original.EventID,
+		Event:        original.Event,
+		Payload:      original.Payload,
+		RedeliveryOf: &original.ID,
+	}
+	if err := ws.enqueue(redelivery); err != nil {
+		logrus.Error("Failed to queue webhook redelivery: ", err)
+		return nil, errors.New("failed to redeliver webhook")
+	}
+	return redelivery, nil
+}
+
+// validateURL rejects URLs deliveries can't or mustn't be sent to
+func (ws *WebhookService) validateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return errors.New("url must be an absolute URL")
+	}
+	if u.User != nil {
+		return errors.New("url must not contain credentials")
+	}
+	if u.Scheme != "https" && !(ws.allowPrivate && u.Scheme == "http") {
+		return errors.New("url must use https")
+	}
+	if ws.allowPrivate {
+		return nil
+	}
+	host := u.Hostname()
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return errors.New("url must point to a public host")
+	}
+	if ip := net.ParseIP(host); ip != nil && !isPublicIP(ip) {
+		return errors.New("url must point to a public host")
+	}
+	return nil
+}
+
+// newWebhookClient returns the client deliveries are sent with. Unless private addresses are
+// allowed, the dialer refuses them, so a public hostname that resolves to one can't be used
+// to reach internal services. Redirects are not followed for the same reason.
+func newWebhookClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("refusing to deliver webhook to non-public address %s", host)
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	transport.Proxy = nil
+	return &http.Client{
+		Transport: transport,
+		Timeout:   webhookTimeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast())
+}
+
+func newWebhookSecret() (string, error) {
+	token, err := utils.GenerateSecureToken(webhookSecretByteLength)
+	if err != nil {
+		return "", errors.New("failed to generate webhook secret")
+	}
+	return "whsec_" + token, nil
+}
+
+func dedupeEvents(events []string) []string {
+	seen := map[string]bool{}
+	out := []string{}
+	for _, event := range events {
+		event = strings.TrimSpace(event)
+		if event == "" || seen[event] {
+			continue
+		}
+		seen[event] = true
+		out = append(out, event)
+	}
+	return out
+}
+
+func parseWebhookIDs(carwashID, endpointID string) (primitive.ObjectID, primitive.ObjectID, error) {
+	carwashObjID, err := primitive.ObjectIDFromHex(carwashID)
+	if err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, errors.New("invalid carwash ID")
+	}
+	endpointObjID, err := primitive.ObjectIDFromHex(endpointID)
+	if err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, errors.New("invalid webhook ID")
+	}
+	return carwashObjID, endpointObjID, nil
+}
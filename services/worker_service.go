@@ -1,14 +1,17 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
 
 	"github.com/olabanji12-ojo/CarWashApp/models"
+	"github.com/olabanji12-ojo/CarWashApp/policy"
 	"github.com/olabanji12-ojo/CarWashApp/repositories"
 	"github.com/olabanji12-ojo/CarWashApp/utils"
 	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -16,63 +19,310 @@ import (
 type WorkerService struct {
 	userRepo   *repositories.UserRepository
 	workerRepo *repositories.WorkerRepository
+	inviteRepo *repositories.WorkerInviteRepository
+	smsService *SMSService
+	authz      *policy.Policy
 }
 
 // NewWorkerService creates a new WorkerService instance
-func NewWorkerService(userRepo *repositories.UserRepository, workerRepo *repositories.WorkerRepository) *WorkerService {
+func NewWorkerService(userRepo *repositories.UserRepository, workerRepo *repositories.WorkerRepository, inviteRepo *repositories.WorkerInviteRepository, smsService *SMSService, authz *policy.Policy) *WorkerService {
 	return &WorkerService{
 		userRepo:   userRepo,
 		workerRepo: workerRepo,
+		inviteRepo: inviteRepo,
+		smsService: smsService,
+		authz:      authz,
 	}
 }
 
-// CreateWorker creates a new worker (called by business)
-func (ws *WorkerService) CreateWorker(requester models.User, input models.User) error {
+// CreateWorker creates a new worker (called by business) and sends them an invite link.
+// The worker has no password until they accept the invite.
+func (ws *WorkerService) CreateWorker(requester models.User, input models.User) (*models.WorkerInvite, error) {
 	logrus.Infof("🔍 [WorkerService.CreateWorker] Validating requester: ID=%s, AccountType=%s, Role=%s, CarWashID=%v", requester.ID.Hex(), requester.AccountType, requester.Role, requester.CarWashID)
 
-	if requester.AccountType != utils.ACCOUNT_TYPE_CAR_WASH || requester.Role != utils.ROLE_BUSINESS {
-		logrus.Warnf("⚠️ [WorkerService.CreateWorker] Validation failed: Expected AccountType=%s, Role=%s", utils.ACCOUNT_TYPE_CAR_WASH, utils.ROLE_BUSINESS)
-		return errors.New("only business owners can create workers")
+	if input.Email == "" && input.Phone == "" {
+		return nil, errors.New("an email or phone number is required to invite a worker")
+	}
+
+	input.Phone = normalizedPhone(input.Phone)
+
+	if input.StaffRole == "" {
+		input.StaffRole = models.StaffRoleWasher
+	}
+	if !models.IsValidStaffRole(input.StaffRole) {
+		return nil, errors.New("invalid staff role. Must be: manager, cashier or washer")
 	}
 
 	input.ID = primitive.NewObjectID()
 	input.AccountType = utils.ACCOUNT_TYPE_CAR_WASH
 	input.Role = utils.ROLE_WORKER
-	input.Status = "active"
-	input.WorkerStatus = "online"
-
-	// Robust CarWashID assignment
-	if requester.CarWashID != nil {
+	input.Status = "invited"
+	input.WorkerStatus = "offline"
+	input.Password = ""
+
+	// Owners with several branches pick the branch in the input; otherwise it's the requester's own.
+	// Either way authorizeStaffRole checks the requester may manage workers there.
+	if input.CarWashID != nil {
+		logrus.Infof("🏢 [WorkerService.CreateWorker] Inviting worker to branch %s", input.CarWashID.Hex())
+	} else if requester.CarWashID != nil {
 		input.CarWashID = requester.CarWashID
-	} else if input.CarWashID != nil {
-		logrus.Warn("⚠️ [WorkerService.CreateWorker] Requester CarWashID is nil, falling back to input CarWashID")
-		// Use input CarWashID if requester's is nil (might happen if local state is ahead of DB)
 	} else {
 		logrus.Error("❌ [WorkerService.CreateWorker] No CarWashID available from requester or input")
-		return errors.New("no carwash associated with this business owner")
+		return nil, errors.New("no carwash associated with this business owner")
 	}
 
 	logrus.Infof("📝 [WorkerService.CreateWorker] Final CarWashID: %v", input.CarWashID)
 
-	// Hash a default password for the worker
-	// We use their phone or a generic placeholder if not provided
-	defaultPass := "password123"
-	if input.Phone != "" {
-		defaultPass = input.Phone
-	}
-	logrus.Infof("🔐 [WorkerService.CreateWorker] Hashing password for %s", input.Email)
-	hashedPassword, err := utils.HashPassword(defaultPass)
-	if err != nil {
-		logrus.Errorf("❌ [WorkerService.CreateWorker] Password hashing failed: %v", err)
-		return errors.New("failed to secure worker account: " + err.Error())
+	if err := ws.authorizeStaffRole(policy.SubjectFromUser(&requester), *input.CarWashID, input.StaffRole); err != nil {
+		logrus.Warnf("⚠️ [WorkerService.CreateWorker] Requester %s may not invite a %s", requester.ID.Hex(), input.StaffRole)
+		return nil, err
 	}
-	input.Password = hashedPassword
 
 	input.CreatedAt = time.Now()
 	input.UpdatedAt = time.Now()
 
 	logrus.Infof("💾 [WorkerService.CreateWorker] Saving worker to DB: Email=%s, CarWashID=%v", input.Email, input.CarWashID)
-	return ws.userRepo.CreateUser(input)
+	if err := ws.userRepo.CreateUser(input); err != nil {
+		return nil, err
+	}
+
+	invite := &models.WorkerInvite{
+		WorkerID:  input.ID,
+		CarwashID: *input.CarWashID,
+		InvitedBy: requester.ID,
+		Name:      input.Name,
+		Email:     input.Email,
+		Phone:     input.Phone,
+		StaffRole: input.StaffRole,
+	}
+	invite.SetDefaults()
+
+	token, err := ws.issueInviteToken(invite)
+	if err != nil {
+		ws.discardInvitedWorker(input.ID)
+		return nil, err
+	}
+
+	if err := ws.inviteRepo.CreateInvite(invite); err != nil {
+		logrus.Errorf("❌ [WorkerService.CreateWorker] Failed to save invite: %v", err)
+		// Without an invite nobody can ever activate the account, so don't leave it behind
+		ws.discardInvitedWorker(input.ID)
+		return nil, errors.New("failed to create worker invite")
+	}
+
+	go ws.deliverInvite(*invite, token)
+
+	return invite, nil
+}
+
+// issueInviteToken generates a fresh raw token and stores its hash on the invite
+func (ws *WorkerService) issueInviteToken(invite *models.WorkerInvite) (string, error) {
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		logrus.Errorf("❌ [WorkerService] Failed to generate invite token: %v", err)
+		return "", errors.New("failed to generate invite token")
+	}
+	invite.TokenHash = utils.HashToken(token)
+	return token, nil
+}
+
+// discardInvitedWorker deletes a worker account whose invite could not be created
+func (ws *WorkerService) discardInvitedWorker(workerID primitive.ObjectID) {
+	if err := ws.userRepo.DeleteUser(workerID); err != nil {
+		logrus.Errorf("❌ [WorkerService] Failed to delete worker %s left without an invite: %v", workerID.Hex(), err)
+	}
+}
+
+// deliverInvite sends the invite link to the worker by email, or by SMS when the worker only
+// has a phone number. The token is a live credential and is never logged.
+func (ws *WorkerService) deliverInvite(invite models.WorkerInvite, token string) {
+	if invite.Email != "" {
+		if err := utils.SendWorkerInviteEmail(invite.Email, invite.Name, token, ""); err != nil {
+			logrus.Errorf("❌ [WorkerService] Failed to send invite email to %s: %v", invite.Email, err)
+			return
+		}
+		logrus.Infof("✅ [WorkerService] Invite email sent to %s", invite.Email)
+		return
+	}
+
+	phone, err := utils.NormalizePhone(invite.Phone)
+	if err != nil {
+		logrus.Errorf("❌ [WorkerService] Cannot text invite %s: %v", invite.ID.Hex(), err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	message := fmt.Sprintf("You've been invited to CarWash App. Set your password within %d hours: %s",
+		int(models.WorkerInviteTTL.Hours()), utils.WorkerInviteLink(token))
+	err = ws.smsService.Send(ctx, SMSRequest{
+		To:        phone,
+		Body:      message,
+		Purpose:   models.SMSPurposeWorkerInvite,
+		UserID:    &invite.WorkerID,
+		CarwashID: &invite.CarwashID,
+	})
+	if err != nil {
+		logrus.Errorf("❌ [WorkerService] Failed to send invite SMS for invite %s: %v", invite.ID.Hex(), err)
+		return
+	}
+	logrus.Infof("✅ [WorkerService] Invite SMS sent for invite %s", invite.ID.Hex())
+}
+
+// ListPendingInvites returns open invites for the requester's carwash
+func (ws *WorkerService) ListPendingInvites(requester models.User) ([]models.WorkerInvite, error) {
+	if requester.CarWashID == nil || !ws.authz.HasCarwashPermission(policy.SubjectFromUser(&requester), *requester.CarWashID, models.PermManageWorkers) {
+		return nil, errors.New("you do not have permission to view worker invites")
+	}
+	return ws.inviteRepo.FindPendingInvitesByCarwashID(*requester.CarWashID)
+}
+
+// RevokeInvite cancels a pending invite and deactivates the worker account it was created for
+func (ws *WorkerService) RevokeInvite(requester models.User, inviteID string) error {
+	invite, err := ws.findOwnedInvite(requester, inviteID)
+	if err != nil {
+		return err
+	}
+
+	if err := ws.inviteRepo.RevokeInvite(invite.ID); err != nil {
+		return err
+	}
+
+	return ws.workerRepo.UpdateWorkerStatus(invite.WorkerID, "inactive")
+}
+
+// ResendInvite issues a new token for a pending invite, invalidating the previous link
+func (ws *WorkerService) ResendInvite(requester models.User, inviteID string) (*models.WorkerInvite, error) {
+	invite, err := ws.findOwnedInvite(requester, inviteID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := ws.issueInviteToken(invite)
+	if err != nil {
+		return nil, err
+	}
+	invite.ExpiresAt = time.Now().Add(models.WorkerInviteTTL)
+
+	if err := ws.inviteRepo.ReissueInvite(invite.ID, invite.TokenHash, invite.ExpiresAt); err != nil {
+		return nil, err
+	}
+
+	go ws.deliverInvite(*invite, token)
+
+	return invite, nil
+}
+
+// AcceptInvite consumes an invite token, sets the worker's password and activates the account
+func (ws *WorkerService) AcceptInvite(token, password string) (*models.User, error) {
+	if token == "" {
+		return nil, errors.New("invite token is required")
+	}
+	if len(password) < 6 || len(password) > 100 {
+		return nil, errors.New("password must be between 6 and 100 characters")
+	}
+
+	invite, err := ws.inviteRepo.ConsumeInvite(utils.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		return nil, errors.New("failed to secure worker account: " + err.Error())
+	}
+
+	err = ws.userRepo.UpdateUserByID(invite.WorkerID, bson.M{
+		"password":      hashedPassword,
+		"status":        "active",
+		"worker_status": "online",
+		"verified":      true,
+		"updated_at":    time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	worker, err := ws.userRepo.FindUserByID(invite.WorkerID)
+	if err != nil {
+		return nil, errors.New("worker not found")
+	}
+	worker.Password = ""
+	return worker, nil
+}
+
+// findOwnedInvite loads an invite and checks it belongs to the requester's carwash
+func (ws *WorkerService) findOwnedInvite(requester models.User, inviteID string) (*models.WorkerInvite, error) {
+	objID, err := primitive.ObjectIDFromHex(inviteID)
+	if err != nil {
+		return nil, errors.New("invalid invite ID format")
+	}
+
+	invite, err := ws.inviteRepo.FindInviteByID(objID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ws.authz.HasCarwashPermission(policy.SubjectFromUser(&requester), invite.CarwashID, models.PermManageWorkers) {
+		return nil, errors.New("you can only manage invites for your own carwash")
+	}
+
+	return invite, nil
+}
+
+// authorizeAssignment checks the requester manages bookings at the carwash behind both the booking and the worker
+func (ws *WorkerService) authorizeAssignment(requester policy.Subject, workerID, orderID string) error {
+	if err := ws.authz.Authorize(requester, orderID, ws.authz.CanOnBooking(models.PermManageBookings)); err != nil {
+		return err
+	}
+	return ws.authz.Authorize(requester, workerID, ws.authz.CanOnWorker(models.PermManageBookings))
+}
+
+// authorizeStaffRole checks the requester may give someone staffRole at the carwash.
+// Managing workers is enough for cashiers and washers; only the owner can appoint managers.
+func (ws *WorkerService) authorizeStaffRole(requester policy.Subject, carwashID primitive.ObjectID, staffRole string) error {
+	if !ws.authz.HasCarwashPermission(requester, carwashID, models.PermManageWorkers) {
+		return policy.ErrForbidden
+	}
+	if staffRole == models.StaffRoleManager && !requester.IsAdmin() && !ws.authz.CarwashOwner(requester, carwashID.Hex()) {
+		return errors.New("only the carwash owner can appoint managers")
+	}
+	return nil
+}
+
+// SetStaffRole changes a worker's staff role (and so their permissions)
+func (ws *WorkerService) SetStaffRole(requester policy.Subject, workerID, staffRole string) error {
+	if !models.IsValidStaffRole(staffRole) {
+		return errors.New("invalid staff role. Must be: manager, cashier or washer")
+	}
+
+	objID, err := primitive.ObjectIDFromHex(workerID)
+	if err != nil {
+		return errors.New("invalid worker ID")
+	}
+	worker, err := ws.userRepo.FindUserByID(objID)
+	if err != nil || worker.Role != utils.ROLE_WORKER || worker.CarWashID == nil {
+		return errors.New("worker not found")
+	}
+	if worker.ID == requester.UserID {
+		return errors.New("you cannot change your own staff role")
+	}
+
+	if err := ws.authorizeStaffRole(requester, *worker.CarWashID, staffRole); err != nil {
+		return err
+	}
+	// Demoting a manager is also reserved for the owner
+	if worker.StaffRole == models.StaffRoleManager {
+		if err := ws.authorizeStaffRole(requester, *worker.CarWashID, models.StaffRoleManager); err != nil {
+			return err
+		}
+	}
+
+	return ws.userRepo.UpdateUserByID(objID, bson.M{
+		"staff_role": staffRole,
+		"updated_at": time.Now(),
+	})
 }
 
 // GetWorkersByCarwashID gets all workers under a carwash
@@ -126,7 +376,11 @@ func (ws *WorkerService) SetWorkerWorkStatus(workerID string, workStatus string)
 }
 
 // AssignWorkerToOrder assigns worker to order (manual assignment by business)
-func (ws *WorkerService) AssignWorkerToOrder(workerID string, orderID string) error {
+func (ws *WorkerService) AssignWorkerToOrder(requester policy.Subject, workerID string, orderID string) error {
+	if err := ws.authorizeAssignment(requester, workerID, orderID); err != nil {
+		return err
+	}
+
 	// 1. Validate IDs
 	workerObjID, err := primitive.ObjectIDFromHex(workerID)
 	if err != nil {
@@ -175,7 +429,11 @@ func (ws *WorkerService) AssignWorkerToOrder(workerID string, orderID string) er
 }
 
 // RemoveWorkerFromOrder removes worker from order (unassign worker)
-func (ws *WorkerService) RemoveWorkerFromOrder(workerID string, orderID string) error {
+func (ws *WorkerService) RemoveWorkerFromOrder(requester policy.Subject, workerID string, orderID string) error {
+	if err := ws.authorizeAssignment(requester, workerID, orderID); err != nil {
+		return err
+	}
+
 	// 1. Validate IDs
 	workerObjID, err := primitive.ObjectIDFromHex(workerID)
 	if err != nil {
@@ -222,6 +480,9 @@ func (ws *WorkerService) UpdateWorker(workerID string, updateData map[string]int
 			cleanUpdate[k] = v
 		}
 	}
+	if phone, ok := cleanUpdate["phone"].(string); ok {
+		cleanUpdate["phone"] = normalizedPhone(phone)
+	}
 
 	if len(cleanUpdate) == 0 {
 		return errors.New("no valid fields to update")
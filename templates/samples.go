@@ -0,0 +1,29 @@
+package templates
+
+import "time"
+
+// SampleData returns placeholder values for previewing an event's templates
+func SampleData(event Event) map[string]interface{} {
+	when := time.Date(2025, time.March, 14, 10, 30, 0, 0, time.UTC)
+	samples := map[Event]map[string]interface{}{
+		EventCustom:              {"Title": "Weekend offer", "Message": "Get 20% off any full wash this weekend."},
+		EventBookingConfirmation: {"Name": "Ada", "Carwash": "Sparkle Auto Spa", "Time": when},
+		EventBookingAccepted:     {"Carwash": "Sparkle Auto Spa", "Time": when},
+		EventBookingRejected:     {"Time": when, "Reason": "Cancelled by business"},
+		EventBookingReminder:     {"Carwash": "Sparkle Auto Spa", "Time": when, "Hours": 24, "Minutes": 0},
+		EventWashCompleted:       {"Carwash": "Sparkle Auto Spa"},
+		EventWorkerArrivingSoon:  {"ETA": 5},
+		EventWorkerArrived:       {"Code": "4821"},
+		EventNewBookingBusiness:  {"Customer": "Ada", "Service": "Full Wash"},
+		EventOrderCreated:        {},
+		EventWorkerAssigned:      {"Worker": "Tunde"},
+		EventOrderStatusUpdate:   {"Status": "completed", "Details": ""},
+		EventEmailVerification:   {"Name": "Ada", "Code": "482913"},
+		EventPasswordReset:       {"Name": "Ada", "Code": "731052", "Link": "https://example.com/reset-password?token=731052"},
+		EventWorkerInvite:        {"Name": "Tunde", "Link": "https://example.com/accept-invite?token=sample"},
+	}
+	if data, ok := samples[event]; ok {
+		return data
+	}
+	return map[string]interface{}{}
+}
@@ -0,0 +1,317 @@
+// Package templates renders notification and email content from versioned, localized templates.
+//
+// Each version lives in its own directory (v1, v2, ...) with one subdirectory per locale.
+// An event has a .txt file defining "subject" and "text" (text/template), used for in-app
+// notifications and the plain-text email part, and optionally a .html file defining "content"
+// (html/template), which is wrapped in the locale's layout.html for the HTML email part.
+// layout.txt defines the "footer" appended to the plain-text email part.
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+	"time"
+)
+
+//go:embed v1
+var files embed.FS
+
+// CurrentVersion is the template set used for new messages
+const CurrentVersion = "v1"
+
+// DefaultLocale is used when a user has no preferred language, or a template is not translated yet
+const DefaultLocale = "en"
+
+// Event names a kind of message
+type Event string
+
+// Notification and email events
+const (
+	EventCustom              Event = "custom" // free-form Title and Message
+	EventBookingConfirmation Event = "booking_confirmation"
+	EventBookingAccepted     Event = "booking_accepted"
+	EventBookingRejected     Event = "booking_rejected"
+	EventBookingReminder     Event = "booking_reminder"
+	EventWashCompleted       Event = "wash_completed"
+	EventWorkerArrivingSoon  Event = "worker_arriving_soon"
+	EventWorkerArrived       Event = "worker_arrived"
+	EventNewBookingBusiness  Event = "new_booking_business"
+	EventOrderCreated        Event = "order_created"
+	EventWorkerAssigned      Event = "worker_assigned"
+	EventOrderStatusUpdate   Event = "order_status_update"
+	EventEmailVerification   Event = "email_verification"
+	EventPasswordReset       Event = "password_reset"
+	EventWorkerInvite        Event = "worker_invite"
+)
+
+// Message is a rendered event. HTML is empty for events that are never emailed.
+type Message struct {
+	Event   Event  `json:"event"`
+	Locale  string `json:"locale"`
+	Version string `json:"version"`
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html,omitempty"`
+
+	// EmailText is Text with the email footer (e.g. the unsubscribe link) appended
+	EmailText string `json:"email_text"`
+}
+
+// Ref identifies the template a message was rendered from, e.g. "v1/fr/booking_accepted"
+func (m *Message) Ref() string {
+	return m.Version + "/" + m.Locale + "/" + string(m.Event)
+}
+
+// UnsubscribeURLKey is the data key of the unsubscribe link; the email footers show it when set
+const UnsubscribeURLKey = "UnsubscribeURL"
+
+type eventTemplates struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template // nil when the event has no email body
+}
+
+type localeTemplates struct {
+	footer *texttemplate.Template
+	events map[Event]*eventTemplates
+}
+
+// Renderer holds one parsed template version
+type Renderer struct {
+	version string
+	locales map[string]*localeTemplates
+}
+
+// dateLayouts formats times the way each locale writes them
+var dateLayouts = map[string]struct{ date, dateTime string }{
+	"en": {"Jan 2, 2006", "Jan 2, 2006 at 3:04 PM"},
+	"fr": {"02/01/2006", "02/01/2006 à 15:04"},
+}
+
+// Load parses every locale of a template version
+func Load(version string) (*Renderer, error) {
+	root, err := fs.Sub(files, version)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := fs.ReadDir(root, ".")
+	if err != nil {
+		return nil, fmt.Errorf("template version %s not found: %v", version, err)
+	}
+
+	r := &Renderer{version: version, locales: map[string]*localeTemplates{}}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		locale, err := loadLocale(root, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		r.locales[entry.Name()] = locale
+	}
+	if _, ok := r.locales[DefaultLocale]; !ok {
+		return nil, fmt.Errorf("template version %s has no %s templates", version, DefaultLocale)
+	}
+	return r, nil
+}
+
+func loadLocale(root fs.FS, locale string) (*localeTemplates, error) {
+	funcs := localeFuncs(locale)
+	layout, err := fs.ReadFile(root, path.Join(locale, "layout.html"))
+	if err != nil {
+		return nil, fmt.Errorf("locale %s has no layout.html", locale)
+	}
+	footer, err := texttemplate.New("layout.txt").Funcs(texttemplate.FuncMap(funcs)).ParseFS(root, path.Join(locale, "layout.txt"))
+	if err != nil || footer.Lookup("footer") == nil {
+		return nil, fmt.Errorf("locale %s needs a layout.txt defining \"footer\"", locale)
+	}
+
+	textFiles, err := fs.Glob(root, path.Join(locale, "*.txt"))
+	if err != nil {
+		return nil, err
+	}
+	events := map[Event]*eventTemplates{}
+	for _, file := range textFiles {
+		name := strings.TrimSuffix(path.Base(file), ".txt")
+		if name == "layout" {
+			continue
+		}
+		text, err := texttemplate.New(name).Funcs(texttemplate.FuncMap(funcs)).ParseFS(root, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", file, err)
+		}
+		if text.Lookup("subject") == nil || text.Lookup("text") == nil {
+			return nil, fmt.Errorf("%s must define \"subject\" and \"text\"", file)
+		}
+		set := &eventTemplates{text: text}
+
+		htmlFile := path.Join(locale, name+".html")
+		if _, err := fs.Stat(root, htmlFile); err == nil {
+			html, err := htmltemplate.New("layout").Funcs(htmltemplate.FuncMap(funcs)).Parse(string(layout))
+			if err == nil {
+				html, err = html.ParseFS(root, htmlFile)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %v", htmlFile, err)
+			}
+			set.html = html
+		}
+		events[Event(name)] = set
+	}
+	return &localeTemplates{footer: footer, events: events}, nil
+}
+
+func localeFuncs(locale string) map[string]interface{} {
+	layouts, ok := dateLayouts[locale]
+	if !ok {
+		layouts = dateLayouts[DefaultLocale]
+	}
+	return map[string]interface{}{
+		"date":     func(t time.Time) string { return t.Format(layouts.date) },
+		"datetime": func(t time.Time) string { return t.Format(layouts.dateTime) },
+		"title":    strings.Title,
+	}
+}
+
+// Render fills an event's templates with data. Untranslated events fall back to DefaultLocale.
+func (r *Renderer) Render(event Event, locale string, data map[string]interface{}) (*Message, error) {
+	locale = r.resolve(event, locale)
+	set, ok := r.locales[locale].events[event]
+	if !ok {
+		return nil, fmt.Errorf("no template for event %q", event)
+	}
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+
+	msg := &Message{Event: event, Locale: locale, Version: r.version}
+	var err error
+	if msg.Subject, err = executeText(set.text, "subject", data); err != nil {
+		return nil, err
+	}
+	if msg.Text, err = executeText(set.text, "text", data); err != nil {
+		return nil, err
+	}
+	footer, err := executeText(r.locales[locale].footer, "footer", data)
+	if err != nil {
+		return nil, err
+	}
+	msg.EmailText = msg.Text
+	if footer != "" {
+		msg.EmailText += "\n\n" + footer
+	}
+	if set.html != nil {
+		var buf bytes.Buffer
+		htmlData := map[string]interface{}{
+			"Locale":         locale,
+			"Subject":        msg.Subject,
+			"UnsubscribeURL": data[UnsubscribeURLKey],
+			"Data":           data,
+		}
+		if err := set.html.ExecuteTemplate(&buf, "layout", htmlData); err != nil {
+			return nil, fmt.Errorf("failed to render %s html: %v", event, err)
+		}
+		msg.HTML = buf.String()
+	}
+	return msg, nil
+}
+
+func executeText(tmpl *texttemplate.Template, name string, data map[string]interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %v", name, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+func (r *Renderer) resolve(event Event, locale string) string {
+	locale = NormalizeLocale(locale)
+	if l, ok := r.locales[locale]; ok && l.events[event] != nil {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// Version returns the template version the renderer was loaded from
+func (r *Renderer) Version() string {
+	return r.version
+}
+
+// Locales lists the translated locales
+func (r *Renderer) Locales() []string {
+	locales := make([]string, 0, len(r.locales))
+	for locale := range r.locales {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Events lists every event with a default-locale template
+func (r *Renderer) Events() []Event {
+	events := make([]Event, 0, len(r.locales[DefaultLocale].events))
+	for event := range r.locales[DefaultLocale].events {
+		events = append(events, event)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i] < events[j] })
+	return events
+}
+
+// Supports reports whether locale has its own translations
+func (r *Renderer) Supports(locale string) bool {
+	_, ok := r.locales[locale]
+	return ok
+}
+
+// NormalizeLocale reduces a language tag such as "fr-FR" to its language, "fr"
+func NormalizeLocale(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		locale = locale[:i]
+	}
+	return locale
+}
+
+var (
+	defaultRenderer *Renderer
+	defaultOnce     sync.Once
+)
+
+// Default returns the renderer for CurrentVersion. The embedded templates are checked at startup,
+// so a broken template stops the server instead of failing on the first message.
+func Default() *Renderer {
+	defaultOnce.Do(func() {
+		r, err := Load(CurrentVersion)
+		if err != nil {
+			panic(err)
+		}
+		defaultRenderer = r
+	})
+	return defaultRenderer
+}
+
+// Render renders an event with the current template version
+func Render(event Event, locale string, data map[string]interface{}) (*Message, error) {
+	return Default().Render(event, locale, data)
+}
+
+// ErrUnsupportedLocale is returned when a user picks a language there are no templates for
+var ErrUnsupportedLocale = errors.New("unsupported language")
+
+// ValidateLocale checks a preferred language against the current templates
+func ValidateLocale(locale string) (string, error) {
+	normalized := NormalizeLocale(locale)
+	if !Default().Supports(normalized) {
+		return "", ErrUnsupportedLocale
+	}
+	return normalized, nil
+}
@@ -1,215 +1,62 @@
 package utils
 
 import (
-	"crypto/tls"
+	"context"
 	"fmt"
-	"net/smtp"
 	"os"
-	"strings"
+	"time"
+
+	"github.com/olabanji12-ojo/CarWashApp/services/email"
+	"github.com/olabanji12-ojo/CarWashApp/templates"
 )
 
-// EmailConfig holds email configuration
-type EmailConfig struct {
-	SMTPHost     string
-	SMTPPort     string
-	SMTPUsername string
-	SMTPPassword string
-	FromEmail    string
-	FromName     string
-}
+// emailSender delivers every email the app sends. Until SetEmailSender is called emails are only logged.
+var emailSender email.EmailSender = email.NewConsoleSender()
 
-// GetEmailConfig loads email configuration from environment variables
-func GetEmailConfig() *EmailConfig {
-	return &EmailConfig{
-		SMTPHost:     getEnvOrDefault("SMTP_HOST", "smtp.gmail.com"),
-		SMTPPort:     getEnvOrDefault("SMTP_PORT", "587"),
-		SMTPUsername: getEnvOrDefault("SMTP_USERNAME", ""),
-		SMTPPassword: getEnvOrDefault("SMTP_PASSWORD", ""),
-		FromEmail:    getEnvOrDefault("FROM_EMAIL", ""),
-		FromName:     getEnvOrDefault("FROM_NAME", "CarWash App"),
-	}
+// SetEmailSender installs the sender emails go out through (SMTP, a provider's HTTP API, ...)
+func SetEmailSender(sender email.EmailSender) {
+	emailSender = sender
 }
 
-// SendEmail sends an email using SMTP (supports both port 587 and 465)
+// SendEmail sends an HTML email
 func SendEmail(to, subject, body string) error {
-	config := GetEmailConfig()
-
-	// Check if configuration is complete
-	if config.SMTPUsername == "" || config.SMTPPassword == "" || config.FromEmail == "" {
-		// Log email to console for development/testing
-		fmt.Println("==================================================")
-		fmt.Println("📧 [MOCK EMAIL] SMTP Config Missing - Logging Email")
-		fmt.Printf("To: %s\n", to)
-		fmt.Printf("Subject: %s\n", subject)
-		fmt.Println("Body:")
-		fmt.Println(body)
-		fmt.Println("==================================================")
-		return nil // Return success so flow continues
-	}
-
-	// Compose message
-	msg := []byte(fmt.Sprintf(
-		"To: %s\r\n"+
-			"From: %s <%s>\r\n"+
-			"Subject: %s\r\n"+
-			"Content-Type: text/html; charset=UTF-8\r\n"+
-			"\r\n"+
-			"%s\r\n",
-		to, config.FromName, config.FromEmail, subject, body))
-
-	// Use different methods based on port
-	if config.SMTPPort == "465" {
-		// Port 465 uses SSL/TLS from the start
-		return sendEmailSSL(config, to, msg)
-	}
-
-	// Port 587 uses STARTTLS
-	return sendEmailSTARTTLS(config, to, msg)
+	return SendEmailWithText(to, subject, "", body)
 }
 
-// sendEmailSTARTTLS sends email using port 587 with STARTTLS
-func sendEmailSTARTTLS(config *EmailConfig, to string, msg []byte) error {
-	fmt.Printf("🔌 Connecting to SMTP server %s:%s via STARTTLS...\n", config.SMTPHost, config.SMTPPort)
-	auth := smtp.PlainAuth("", config.SMTPUsername, config.SMTPPassword, config.SMTPHost)
-
-	err := smtp.SendMail(
-		config.SMTPHost+":"+config.SMTPPort,
-		auth,
-		config.FromEmail,
-		[]string{to},
-		msg,
-	)
-
-	if err != nil {
-		fmt.Printf("❌ SMTP Error: %v\n", err)
-		return fmt.Errorf("failed to send email: %v", err)
-	}
-
-	fmt.Println("✅ Email sent via STARTTLS")
-	return nil
+// SendEmailWithText sends an email with a plain-text part, an HTML part, or both as multipart/alternative
+func SendEmailWithText(to, subject, text, html string) error {
+	return SendEmailWithHeaders(to, subject, text, html, nil)
 }
 
-// sendEmailSSL sends email using port 465 with SSL/TLS
-func sendEmailSSL(config *EmailConfig, to string, msg []byte) error {
-	fmt.Printf("🔌 Connecting to SMTP server %s:%s via SSL...\n", config.SMTPHost, config.SMTPPort)
-
-	// TLS config
-	tlsConfig := &tls.Config{
-		ServerName: config.SMTPHost,
-	}
-
-	// Connect to SMTP server with TLS
-	conn, err := tls.Dial("tcp", config.SMTPHost+":"+config.SMTPPort, tlsConfig)
-	if err != nil {
-		return fmt.Errorf("failed to connect to SMTP server: %v", err)
-	}
-	defer conn.Close()
-	fmt.Println("✅ SSL Connection established")
-
-	// Create SMTP client
-	client, err := smtp.NewClient(conn, config.SMTPHost)
-	if err != nil {
-		return fmt.Errorf("failed to create SMTP client: %v", err)
-	}
-	defer client.Quit()
-
-	// Authenticate
-	fmt.Println("🔐 Authenticating...")
-	auth := smtp.PlainAuth("", config.SMTPUsername, config.SMTPPassword, config.SMTPHost)
-	if err = client.Auth(auth); err != nil {
-		return fmt.Errorf("SMTP authentication failed: %v", err)
-	}
-	fmt.Println("✅ Authentication successful")
-
-	// Set sender
-	if err = client.Mail(config.FromEmail); err != nil {
-		return fmt.Errorf("failed to set sender: %v", err)
-	}
-
-	// Set recipient
-	if err = client.Rcpt(to); err != nil {
-		return fmt.Errorf("failed to set recipient: %v", err)
-	}
-
-	// Send message
-	writer, err := client.Data()
-	if err != nil {
-		return fmt.Errorf("failed to get data writer: %v", err)
-	}
-
-	_, err = writer.Write(msg)
-	if err != nil {
-		return fmt.Errorf("failed to write message: %v", err)
-	}
-
-	err = writer.Close()
-	if err != nil {
-		return fmt.Errorf("failed to close writer: %v", err)
-	}
-
-	return nil
+// SendEmailWithHeaders is SendEmailWithText with extra headers, such as List-Unsubscribe
+func SendEmailWithHeaders(to, subject, text, html string, headers map[string]string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	return emailSender.Send(ctx, email.Message{
+		To:      to,
+		Subject: subject,
+		Text:    text,
+		HTML:    html,
+		Headers: headers,
+	})
 }
 
 // SendVerificationEmail sends email verification code
-func SendVerificationEmail(userEmail, userName, token string) error {
-	subject := "Verify Your Email - CarWash App"
-	body := fmt.Sprintf(`
-		<html>
-		<body>
-			<h2>Welcome to CarWash App!</h2>
-			<p>Hi %s,</p>
-			<p>Please use the following code to verify your email address:</p>
-			<h1 style="color: #2563EB; letter-spacing: 5px;">%s</h1>
-			<p>This code will expire in 24 hours.</p>
-			<p>If you didn't create an account, please ignore this email.</p>
-		</body>
-		</html>
-	`, userName, token)
-
-	return SendEmail(userEmail, subject, body)
-}
-
-// SendBookingConfirmationEmail sends a booking confirmation email
-func SendBookingConfirmationEmail(userEmail, userName, carwashName, bookingTime string) error {
-	subject := "Booking Confirmation - CarWash App"
-	body := fmt.Sprintf(`
-		<html>
-		<body>
-			<h2>Booking Confirmed!</h2>
-			<p>Hi %s,</p>
-			<p>Your carwash booking has been confirmed:</p>			
-			<ul>
-
-				<li><strong>Carwash:</strong> %s</li>
-				<li><strong>Time:</strong> %s</li>
-
-			</ul>
-			<p>We'll notify you when your booking is accepted by the business.</p>
-			<p>Thank you for using CarWash App!</p>
-		</body>
-		</html>
-	`, userName, carwashName, bookingTime)
-
-	return SendEmail(userEmail, subject, body)
-
+func SendVerificationEmail(userEmail, userName, token, locale string) error {
+	return sendTemplatedEmail(userEmail, templates.EventEmailVerification, locale, map[string]interface{}{
+		"Name": userName,
+		"Code": token,
+	})
 }
 
 // SendOrderUpdateEmail sends order status update email
-func SendOrderUpdateEmail(userEmail, userName, status, details string) error {
-	subject := fmt.Sprintf("Order Update - %s", strings.Title(status))
-	body := fmt.Sprintf(`
-		<html>
-		<body>
-			<h2>Order Update</h2>
-			<p>Hi %s,</p>
-			<p>Your order status has been updated to: <strong>%s</strong></p>
-			<p>%s</p>
-			<p>Thank you for using CarWash App!</p>
-		</body>
-		</html>
-	`, userName, strings.Title(status), details)
-
-	return SendEmail(userEmail, subject, body)
+func SendOrderUpdateEmail(userEmail, userName, status, details, locale string) error {
+	return sendTemplatedEmail(userEmail, templates.EventOrderStatusUpdate, locale, map[string]interface{}{
+		"Name":    userName,
+		"Status":  status,
+		"Details": details,
+	})
 }
 
 // Helper function to get environment variable with default
@@ -221,28 +68,34 @@ func getEnvOrDefault(key, defaultValue string) string {
 }
 
 // SendPasswordResetEmail sends a password reset email with token
-func SendPasswordResetEmail(userEmail, userName, resetToken string) error {
+func SendPasswordResetEmail(userEmail, userName, resetToken, locale string) error {
 	frontendURL := getEnvOrDefault("FRONTEND_URL", "http://localhost:5173")
-	resetLink := fmt.Sprintf("%s/reset-password?token=%s", frontendURL, resetToken)
+	return sendTemplatedEmail(userEmail, templates.EventPasswordReset, locale, map[string]interface{}{
+		"Name": userName,
+		"Code": resetToken,
+		"Link": fmt.Sprintf("%s/reset-password?token=%s", frontendURL, resetToken),
+	})
+}
 
-	subject := "Reset Your Password - CarWash App"
-	body := fmt.Sprintf(`
-		<html>
-		<body style="font-family: Arial, sans-serif; max-width: 600px; margin: 0 auto;">
-			<h2 style="color: #2563EB;">Password Reset Request</h2>
-			<p>Hi %s,</p>
-			<p>You requested to reset your password. Use the code below or click the button:</p>
-			<div style="background: #f3f4f6; padding: 20px; border-radius: 8px; text-align: center; margin: 20px 0;">
-				<h1 style="color: #2563EB; letter-spacing: 5px; margin: 0;">%s</h1>
-			</div>
-			<div style="text-align: center; margin: 30px 0;">
-				<a href="%s" style="background: #2563EB; color: white; padding: 12px 32px; text-decoration: none; border-radius: 6px; display: inline-block;">Reset Password</a>
-			</div>
-			<p style="color: #6B7280; font-size: 14px;">This link expires in 1 hour.</p>
-			<p style="color: #6B7280; font-size: 14px;">If you didn't request this, please ignore this email.</p>
-		</body>
-		</html>
-	`, userName, resetToken, resetLink)
+// SendWorkerInviteEmail sends a worker their one-time account setup link
+func SendWorkerInviteEmail(workerEmail, workerName, inviteToken, locale string) error {
+	return sendTemplatedEmail(workerEmail, templates.EventWorkerInvite, locale, map[string]interface{}{
+		"Name": workerName,
+		"Link": WorkerInviteLink(inviteToken),
+	})
+}
+
+// WorkerInviteLink is the page where a worker accepts their invite and sets a password
+func WorkerInviteLink(inviteToken string) string {
+	frontendURL := getEnvOrDefault("FRONTEND_URL", "http://localhost:5173")
+	return fmt.Sprintf("%s/accept-invite?token=%s", frontendURL, inviteToken)
+}
 
-	return SendEmail(userEmail, subject, body)
+// sendTemplatedEmail renders an event in the recipient's language and sends both parts
+func sendTemplatedEmail(to string, event templates.Event, locale string, data map[string]interface{}) error {
+	msg, err := templates.Render(event, locale, data)
+	if err != nil {
+		return err
+	}
+	return SendEmailWithText(to, msg.Subject, msg.EmailText, msg.HTML)
 }
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/olabanji12-ojo/CarWashApp/services/email"
+)
+
+func captureEmails(t *testing.T) *email.CaptureSender {
+	t.Helper()
+	sender := email.NewCaptureSender()
+	previous := emailSender
+	SetEmailSender(sender)
+	t.Cleanup(func() { SetEmailSender(previous) })
+	return sender
+}
+
+func TestSendWorkerInviteEmail(t *testing.T) {
+	t.Setenv("FRONTEND_URL", "https://app.example.com")
+	sender := captureEmails(t)
+
+	if err := SendWorkerInviteEmail("tunde@example.com", "Tunde", "tok123", "en"); err != nil {
+		t.Fatal(err)
+	}
+
+	sent := sender.SentTo("tunde@example.com")
+	if len(sent) != 1 {
+		t.Fatalf("sent %d emails, want 1", len(sent))
+	}
+	link := "https://app.example.com/accept-invite?token=tok123"
+	msg := sent[0]
+	if msg.Subject == "" || msg.Text == "" || msg.HTML == "" {
+		t.Errorf("invite is missing a part: %+v", msg)
+	}
+	if !strings.Contains(msg.Text, link) || !strings.Contains(msg.HTML, link) {
+		t.Errorf("invite does not carry the setup link %s", link)
+	}
+}
+
+func TestSendEmailWithHeaders(t *testing.T) {
+	sender := captureEmails(t)
+	headers := map[string]string{"List-Unsubscribe": "<https://example.com/u>"}
+
+	if err := SendEmailWithHeaders("ada@example.com", "Hi", "plain", "<p>html</p>", headers); err != nil {
+		t.Fatal(err)
+	}
+
+	sent := sender.Sent()
+	if len(sent) != 1 || sent[0].Headers["List-Unsubscribe"] != "<https://example.com/u>" {
+		t.Errorf("headers were not passed on: %+v", sent)
+	}
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+// NormalizePhone converts a phone number to E.164 form ("+2348012345678").
+// Spaces, dashes, dots and brackets are dropped and a leading "00" becomes "+".
+// Local numbers starting with "0" get DEFAULT_PHONE_COUNTRY_CODE (e.g. "234") when it is set.
+func NormalizePhone(phone string) (string, error) {
+	cleaned := strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "").Replace(strings.TrimSpace(phone))
+
+	switch {
+	case strings.HasPrefix(cleaned, "+"):
+	case strings.HasPrefix(cleaned, "00"):
+		cleaned = "+" + cleaned[2:]
+	case strings.HasPrefix(cleaned, "0") && os.Getenv("DEFAULT_PHONE_COUNTRY_CODE") != "":
+		cleaned = "+" + strings.TrimPrefix(os.Getenv("DEFAULT_PHONE_COUNTRY_CODE"), "+") + cleaned[1:]
+	default:
+		return "", errors.New("phone number must include a country code, e.g. +2348012345678")
+	}
+
+	digits := cleaned[1:]
+	if len(digits) < 8 || len(digits) > 15 {
+		return "", errors.New("invalid phone number length")
+	}
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return "", errors.New("phone number may only contain digits")
+		}
+	}
+	return cleaned, nil
+}
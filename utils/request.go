@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// trustedProxies are the proxies whose X-Forwarded-For entries ClientIP believes
+var trustedProxies []*net.IPNet
+
+// SetTrustedProxies sets the proxies (IPs or CIDR ranges) in front of the app, e.g. the hosting
+// load balancer. Without any, X-Forwarded-For is ignored.
+func SetTrustedProxies(proxies []string) error {
+	var nets []*net.IPNet
+	for _, entry := range proxies {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil && ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return fmt.Errorf("invalid trusted proxy %q", entry)
+		}
+		nets = append(nets, network)
+	}
+	trustedProxies = nets
+	return nil
+}
+
+// ClientIP returns the caller's IP. X-Forwarded-For is only honoured when the request comes from
+// a trusted proxy, and then read from the right: the left-most entries are whatever the client sent.
+func ClientIP(r *http.Request) string {
+	remote, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remote = r.RemoteAddr
+	}
+	if !isTrustedProxy(remote) {
+		return remote
+	}
+
+	hops := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		// Chained proxies each append the address they received the request from
+		if !isTrustedProxy(hop) {
+			return hop
+		}
+	}
+	return remote
+}
+
+func isTrustedProxy(address string) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+	for _, network := range trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	cases := []struct {
+		name      string
+		proxies   []string
+		remote    string
+		forwarded string
+		want      string
+	}{
+		{"no proxy configured ignores the header", nil, "203.0.113.7:4000", "198.51.100.1", "203.0.113.7"},
+		{"untrusted peer ignores the header", []string{"10.0.0.0/8"}, "203.0.113.7:4000", "198.51.100.1", "203.0.113.7"},
+		{"trusted proxy", []string{"10.0.0.0/8"}, "10.1.2.3:4000", "198.51.100.1", "198.51.100.1"},
+		{"spoofed entries before the proxy's", []string{"10.0.0.0/8"}, "10.1.2.3:4000", "1.1.1.1, 2.2.2.2, 198.51.100.1", "198.51.100.1"},
+		{"chained trusted proxies", []string{"10.0.0.0/8", "192.0.2.10"}, "10.1.2.3:4000", "6.6.6.6, 198.51.100.1, 192.0.2.10", "198.51.100.1"},
+		{"trusted proxy without header", []string{"10.0.0.0/8"}, "10.1.2.3:4000", "", "10.1.2.3"},
+		{"garbage hop stops the walk", []string{"10.0.0.0/8"}, "10.1.2.3:4000", "198.51.100.1, nonsense", "10.1.2.3"},
+		{"IPv6 proxy", []string{"fd00::/8"}, "[fd00::1]:4000", "2001:db8::5", "2001:db8::5"},
+	}
+	defer SetTrustedProxies(nil)
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := SetTrustedProxies(tc.proxies); err != nil {
+				t.Fatal(err)
+			}
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tc.remote
+			if tc.forwarded != "" {
+				r.Header.Set("X-Forwarded-For", tc.forwarded)
+			}
+			if got := ClientIP(r); got != tc.want {
+				t.Errorf("ClientIP = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSetTrustedProxiesRejectsGarbage(t *testing.T) {
+	defer SetTrustedProxies(nil)
+	if err := SetTrustedProxies([]string{"10.0.0.0/8", "not-an-ip"}); err == nil {
+		t.Error("invalid proxy was accepted")
+	}
+}
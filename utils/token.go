@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// GenerateSecureToken returns a random hex-encoded token built from byteLength random bytes
+// For example, GenerateSecureToken(32) returns a 64 character string
+func GenerateSecureToken(byteLength int) (string, error) {
+	if byteLength <= 0 {
+		return "", fmt.Errorf("byte length must be greater than 0")
+	}
+
+	b := make([]byte, byteLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random token: %v", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// HashToken returns the SHA-256 hex digest of a token
+// Only the hash is stored in the database so a leaked collection can't be replayed
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}